@@ -1,7 +1,9 @@
 "use client";
 
 import { webApi } from "@/app/lib/api/client";
+import { isAxiosError } from "axios";
 import { useMemo, useState } from "react";
+import { toast } from "sonner";
 
 type MeState = {
   hasRequestedAccount: boolean;
@@ -23,6 +25,13 @@ const NOTICE_JOIN =
 const NOTICE_JOINED =
   "You have successfully joined the competition. See you at kickoff! 🚀";
 
+const NOTICE_UNVERIFIED =
+  "Verify your email address first. Open the link we sent you when you registered, or have us send a new one.";
+
+function isUnverified(err: unknown) {
+  return isAxiosError(err) && err.response?.status === 403;
+}
+
 export default function JoinPanel({
   competitionId,
   initialMe,
@@ -43,6 +52,7 @@ export default function JoinPanel({
   );
 
   const [errors, setErrors] = useState<FieldErrors>({});
+  const [unverified, setUnverified] = useState(false);
 
   const canRequest = useMemo(
     () => !me.hasRequestedAccount && !me.hasJoined,
@@ -62,6 +72,22 @@ export default function JoinPanel({
       await webApi.post(`/competitions/${competitionId}/account-requests`);
       await refetchMe();
       setNotice(NOTICE_REQUESTED);
+    } catch (err) {
+      if (isUnverified(err)) {
+        setUnverified(true);
+        setNotice(NOTICE_UNVERIFIED);
+      }
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  async function resendVerification() {
+    setLoading(true);
+    try {
+      await webApi.post("/auth/verify-email/resend");
+      toast.success("Verification email sent. Check your inbox.");
+    } catch {
     } finally {
       setLoading(false);
     }
@@ -108,7 +134,11 @@ export default function JoinPanel({
       setShowJoin(false);
       setNotice(NOTICE_JOINED);
       e.currentTarget.reset();
-    } catch {
+    } catch (err) {
+      if (isUnverified(err)) {
+        setUnverified(true);
+        setNotice(NOTICE_UNVERIFIED);
+      }
     } finally {
       setLoading(false);
     }
@@ -148,6 +178,21 @@ export default function JoinPanel({
       )}
 
       <div className="mt-6 flex flex-col items-center gap-4">
+        {unverified && (
+          <button
+            onClick={resendVerification}
+            disabled={loading}
+            className="
+              w-full max-w-sm rounded-sm py-3 font-semibold
+              border border-[#60A5FA]/40 text-[#BFDBFE]
+              cursor-pointer hover:bg-[#60A5FA]/10 transition
+              disabled:opacity-50 disabled:cursor-not-allowed
+            "
+          >
+            Resend verification email
+          </button>
+        )}
+
         {canRequest && (
           <button
             onClick={requestAccount}
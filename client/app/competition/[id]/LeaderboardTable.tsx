@@ -68,8 +68,9 @@ export default function LeaderboardTable({
   async function fetchLeaderboard() {
     const { data } = await webApi.get(
       `/competitions/${competitionId}/leaderboard`,
+      { params: { limit: 100 } },
     );
-    setRows(Array.isArray(data) ? data : []);
+    setRows(Array.isArray(data?.entries) ? data.entries : []);
     setLoading(false);
   }
 
@@ -1,4 +1,5 @@
 import Image from "next/image";
+import Link from "next/link";
 import JoinPanel from "./JoinPanel";
 import LeaderboardTable from "./LeaderboardTable";
 import { getServerApi } from "@/app/lib/api/server";
@@ -35,7 +36,19 @@ export default async function CompetitionPage({
   return (
     <div className="min-h-screen bg-[#0B0C10] text-white">
       {/* subtle neon glow */}
-      <div className="flex pt-4 px-4 justify-end">
+      <div className="flex gap-2 pt-4 px-4 justify-end">
+        <Link
+          href="/two-factor"
+          className="
+            flex items-center rounded-sm px-3 py-2 text-sm
+            border border-white/10 bg-white/5
+            text-[#A1A1AA]
+            hover:text-white hover:bg-white/10
+            transition
+          "
+        >
+          Two-factor
+        </Link>
         <LogoutButton />
       </div>
       <div className="pointer-events-none fixed inset-0 opacity-60">
@@ -26,6 +26,7 @@ export type Candle = {
 export type Trade = {
   positionId: number;
   symbol: string;
+  instrument?: string;
   side: TradeSide;
   openPrice: number;
   volume: number;
@@ -142,7 +143,10 @@ export default function TrackedChart({ title, candles, trades }: Props) {
   const priceLinesRef = useRef<IPriceLine[]>([]);
 
   const filteredTrades = useMemo(
-    () => trades.filter((trade) => normalizeSymbol(trade?.symbol) === title),
+    () =>
+      trades.filter(
+        (trade) => (trade?.instrument ?? normalizeSymbol(trade?.symbol)) === title,
+      ),
     [trades, title],
   );
 
@@ -0,0 +1,91 @@
+"use client";
+
+import Link from "next/link";
+import { useState } from "react";
+import { webApi } from "../lib/api/client";
+
+export default function ForgotPassword() {
+  const [email, setEmail] = useState("");
+  const [loading, setLoading] = useState(false);
+  const [sent, setSent] = useState(false);
+
+  async function onSubmit(e: React.FormEvent) {
+    e.preventDefault();
+    setLoading(true);
+
+    try {
+      await webApi.post(
+        "/auth/password-reset/request",
+        { email },
+        { headers: { "Content-Type": "application/json" } },
+      );
+      setSent(true);
+    } catch {
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  return (
+    <div className="min-h-screen bg-[#0B0C12] flex items-center justify-center px-4">
+      <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
+        <div className="text-center space-y-2">
+          <h1 className="text-3xl sm:text-4xl font-semibold text-white">
+            Forgot password
+          </h1>
+          <p className="text-sm text-[#A1A1AA]">
+            Remembered it?{" "}
+            <Link
+              href="/login"
+              className="text-[#60A5FA] underline underline-offset-4 hover:opacity-80 transition"
+            >
+              Log in
+            </Link>
+          </p>
+        </div>
+
+        {sent ? (
+          <p className="text-sm text-[#C7D2FE] text-center">
+            If an account exists for {email}, we&apos;ve sent it a link to
+            reset the password. Check your inbox.
+          </p>
+        ) : (
+          <form onSubmit={onSubmit} className="space-y-4">
+            <div className="space-y-1.5">
+              <label className="block mb-1.5 text-sm font-medium text-[#C7D2FE]">
+                Email
+              </label>
+              <input
+                type="email"
+                required
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                placeholder="Email"
+                className="
+                  w-full rounded-xl px-3 py-2
+                  bg-[#0F1016]/80 border border-white/10
+                  text-white placeholder:text-white/30
+                  focus:outline-none focus:ring-2 focus:ring-[#60A5FA]/60
+                  transition
+                "
+              />
+            </div>
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="
+                w-full rounded-sm py-3 font-semibold
+                bg-gradient-to-r from-[#A855F7] to-[#60A5FA]
+                hover:opacity-90 transition
+                disabled:opacity-50 disabled:cursor-not-allowed
+              "
+            >
+              {loading ? "Sending..." : "Send reset link"}
+            </button>
+          </form>
+        )}
+      </div>
+    </div>
+  );
+}
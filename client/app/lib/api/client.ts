@@ -13,9 +13,47 @@ export const webApi = axios.create({
   timeout: 15000,
 });
 
+// Access tokens are short-lived. On a 401 the session is refreshed once and
+// the request retried; concurrent failures share the same refresh.
+let refreshing: Promise<void> | null = null;
+
+function refreshSession() {
+  refreshing ??= axios
+    .post("/auth/refresh", null, { baseURL, withCredentials: true })
+    .then(() => undefined)
+    .finally(() => {
+      refreshing = null;
+    });
+  return refreshing;
+}
+
+const noRefreshPaths = [
+  "/auth/login",
+  "/auth/login/mfa",
+  "/auth/refresh",
+  "/auth/logout",
+];
+
 webApi.interceptors.response.use(
   (res) => res,
-  (err) => {
+  async (err) => {
+    const original = err?.config;
+    if (
+      err?.response?.status === 401 &&
+      original &&
+      !original._retried &&
+      !noRefreshPaths.includes(original.url)
+    ) {
+      original._retried = true;
+      try {
+        await refreshSession();
+        return webApi(original);
+      } catch {
+        if (typeof window !== "undefined") window.location.assign("/login");
+        return Promise.reject(err);
+      }
+    }
+
     if (!err?.response) {
       toast.error("Network error. Please try again.");
       return Promise.reject(err);
@@ -1,34 +1,131 @@
 "use client";
 
+import axios from "axios";
 import { Eye, EyeOff } from "lucide-react";
 import Link from "next/link";
 import { useRouter } from "next/navigation";
 import { useState } from "react";
 import { webApi } from "../lib/api/client";
 
+type MFAChallenge = {
+  mfaRequired: boolean;
+  mfaToken: string;
+};
+
 export default function Login() {
   const router = useRouter();
   const [showPassword, setShowPassword] = useState(false);
   const [form, setForm] = useState({ email: "", password: "" });
   const [loading, setLoading] = useState(false);
+  // Set once the password is accepted for an account with two-factor
+  // authentication; the login finishes with a code from the app.
+  const [mfaToken, setMfaToken] = useState("");
+  const [code, setCode] = useState("");
+
+  function loggedIn() {
+    router.push("/competition");
+    router.refresh();
+  }
 
   async function onSubmit(e: React.FormEvent) {
     e.preventDefault();
     setLoading(true);
 
     try {
-      await webApi.post("/auth/login", form, {
+      const res = await webApi.post<MFAChallenge | "">("/auth/login", form, {
         headers: { "Content-Type": "application/json" },
       });
 
-      router.push("/competition");
-      router.refresh();
+      if (res.data && res.data.mfaRequired) {
+        setMfaToken(res.data.mfaToken);
+        return;
+      }
+
+      loggedIn();
     } catch {
     } finally {
       setLoading(false);
     }
   }
 
+  async function onSubmitCode(e: React.FormEvent) {
+    e.preventDefault();
+    setLoading(true);
+
+    try {
+      await webApi.post(
+        "/auth/login/mfa",
+        { mfaToken, code },
+        { headers: { "Content-Type": "application/json" } },
+      );
+
+      loggedIn();
+    } catch (err) {
+      // The challenge expired; start over from the password.
+      if (axios.isAxiosError(err) && err.response?.status === 401) {
+        setMfaToken("");
+        setCode("");
+      }
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  if (mfaToken) {
+    return (
+      <div className="min-h-screen bg-[#0B0C12] flex items-center justify-center px-4">
+        <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
+          <div className="text-center space-y-2">
+            <h1 className="text-3xl sm:text-4xl font-semibold text-white">
+              Two-factor
+            </h1>
+            <p className="text-sm text-[#A1A1AA]">
+              Enter the code from your authenticator app, or one of your
+              recovery codes.
+            </p>
+          </div>
+
+          <form onSubmit={onSubmitCode} className="space-y-4">
+            <div className="space-y-1.5">
+              <label className="block mb-1.5 text-sm font-medium text-[#C7D2FE]">
+                Code
+              </label>
+              <input
+                type="text"
+                required
+                autoFocus
+                autoComplete="one-time-code"
+                value={code}
+                onChange={(e) => setCode(e.target.value)}
+                placeholder="123456"
+                className="
+                  w-full rounded-xl px-3 py-2
+                  bg-[#0F1016]/80 border border-white/10
+                  text-white placeholder:text-white/30
+                  focus:outline-none focus:ring-2 focus:ring-[#60A5FA]/60
+                  transition
+                "
+              />
+            </div>
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="
+                w-full rounded-sm py-3 font-semibold
+                bg-gradient-to-r from-[#A855F7] to-[#60A5FA]
+                hover:opacity-90 transition
+                disabled:opacity-50 disabled:cursor-not-allowed
+              "
+            >
+              {loading ? "Verifying..." : "Verify"}
+            </button>
+          </form>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="min-h-screen bg-[#0B0C12] flex items-center justify-center px-4">
       <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
@@ -111,6 +208,15 @@ export default function Login() {
             </div>
           </div>
 
+          <div className="text-right">
+            <Link
+              href="/forgot-password"
+              className="text-sm text-[#60A5FA] underline underline-offset-4 hover:opacity-80 transition"
+            >
+              Forgot password?
+            </Link>
+          </div>
+
           {/* Submit */}
           <button
             type="submit"
@@ -4,6 +4,7 @@ import { useMemo, useState } from "react";
 import { Eye, EyeOff } from "lucide-react";
 import Link from "next/link";
 import { useRouter } from "next/navigation";
+import { toast } from "sonner";
 import { webApi } from "../lib/api/client";
 
 /* ---------- FE validation helpers (UX only) ---------- */
@@ -95,6 +96,9 @@ export default function Register() {
         password: form.password,
       });
 
+      toast.success(
+        "Account created. Check your email to verify your address.",
+      );
       router.push("/login");
     } catch {
     } finally {
@@ -0,0 +1,119 @@
+"use client";
+
+import { Eye, EyeOff } from "lucide-react";
+import Link from "next/link";
+import { useRouter, useSearchParams } from "next/navigation";
+import { Suspense, useState } from "react";
+import { toast } from "sonner";
+import { webApi } from "../lib/api/client";
+
+function ResetPasswordForm() {
+  const router = useRouter();
+  const token = useSearchParams().get("token") ?? "";
+  const [showPassword, setShowPassword] = useState(false);
+  const [newPassword, setNewPassword] = useState("");
+  const [loading, setLoading] = useState(false);
+
+  async function onSubmit(e: React.FormEvent) {
+    e.preventDefault();
+    setLoading(true);
+
+    try {
+      await webApi.post(
+        "/auth/password-reset/confirm",
+        { token, newPassword },
+        { headers: { "Content-Type": "application/json" } },
+      );
+
+      toast.success("Password changed. Log in with your new password.");
+      router.replace("/login");
+    } catch {
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  if (!token) {
+    return (
+      <p className="text-sm text-[#C7D2FE] text-center">
+        This reset link is incomplete.{" "}
+        <Link
+          href="/forgot-password"
+          className="text-[#60A5FA] underline underline-offset-4 hover:opacity-80 transition"
+        >
+          Request a new one
+        </Link>
+      </p>
+    );
+  }
+
+  return (
+    <form onSubmit={onSubmit} className="space-y-4">
+      <div className="space-y-1.5">
+        <label className="block mb-1.5 text-sm font-medium text-[#C7D2FE]">
+          New password
+        </label>
+
+        <div className="relative">
+          <input
+            type={showPassword ? "text" : "password"}
+            required
+            value={newPassword}
+            onChange={(e) => setNewPassword(e.target.value)}
+            placeholder="New password"
+            className="
+              w-full rounded-xl px-3 py-2 pr-10
+              bg-[#0F1016]/80 border border-white/10
+              text-white placeholder:text-white/30
+              focus:outline-none focus:ring-2 focus:ring-[#A855F7]/60
+              transition
+            "
+          />
+
+          <button
+            type="button"
+            aria-label={showPassword ? "Hide password" : "Show password"}
+            onClick={() => setShowPassword((v) => !v)}
+            className="
+              absolute inset-y-0 right-3 flex items-center
+              text-white/40 hover:text-white transition
+            "
+          >
+            {showPassword ? <EyeOff size={18} /> : <Eye size={18} />}
+          </button>
+        </div>
+      </div>
+
+      <button
+        type="submit"
+        disabled={loading}
+        className="
+          w-full rounded-sm py-3 font-semibold
+          bg-gradient-to-r from-[#A855F7] to-[#60A5FA]
+          hover:opacity-90 transition
+          disabled:opacity-50 disabled:cursor-not-allowed
+        "
+      >
+        {loading ? "Saving..." : "Set new password"}
+      </button>
+    </form>
+  );
+}
+
+export default function ResetPassword() {
+  return (
+    <div className="min-h-screen bg-[#0B0C12] flex items-center justify-center px-4">
+      <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
+        <div className="text-center space-y-2">
+          <h1 className="text-3xl sm:text-4xl font-semibold text-white">
+            Reset password
+          </h1>
+        </div>
+
+        <Suspense>
+          <ResetPasswordForm />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -36,6 +36,7 @@ type Trade = {
 type ApiResponse = {
   username: string;
   trades: any[]; // keep as-is (backend shape), we map safely
+  nextCursor?: string;
 };
 
 type Row = Trade & { total: number };
@@ -195,14 +196,24 @@ export default function TradingAccountTradeHistoryPage() {
       setError(null);
 
       try {
-        const { data } = await webApi.get<ApiResponse>(
-          `/trading-accounts/${accountId}/trade-history`,
-        );
-
-        const mapped = (data?.trades ?? []).map(mapTrade);
+        // History is paginated; the chart needs every trade, so follow the cursor.
+        const all: any[] = [];
+        let name = "";
+        let cursor: string | undefined;
+        do {
+          const { data } = await webApi.get<ApiResponse>(
+            `/trading-accounts/${accountId}/trade-history`,
+            { params: { limit: 500, cursor } },
+          );
+          name = data?.username ?? "";
+          all.push(...(data?.trades ?? []));
+          cursor = data?.nextCursor;
+        } while (cursor && alive);
+
+        const mapped = all.map(mapTrade);
 
         if (!alive) return;
-        setUsername(data?.username ?? "");
+        setUsername(name);
         setTrades(mapped);
       } catch (e: any) {
         if (!alive) return;
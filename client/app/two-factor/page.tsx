@@ -0,0 +1,255 @@
+"use client";
+
+import { useRouter } from "next/navigation";
+import { useEffect, useState } from "react";
+import { toast } from "sonner";
+import BackButton from "../components/BackButton";
+import { webApi } from "../lib/api/client";
+
+type MFAStatus = {
+  enabled: boolean;
+  recoveryCodesLeft: number;
+};
+
+type Enrollment = {
+  secret: string;
+  uri: string;
+};
+
+const jsonHeaders = { headers: { "Content-Type": "application/json" } };
+
+const inputClassName = `
+  w-full rounded-xl px-3 py-2
+  bg-[#0F1016]/80 border border-white/10
+  text-white placeholder:text-white/30
+  focus:outline-none focus:ring-2 focus:ring-[#60A5FA]/60
+  transition
+`;
+
+const buttonClassName = `
+  w-full rounded-sm py-3 font-semibold
+  bg-gradient-to-r from-[#A855F7] to-[#60A5FA]
+  hover:opacity-90 transition
+  disabled:opacity-50 disabled:cursor-not-allowed
+`;
+
+const secondaryButtonClassName = `
+  w-full rounded-sm py-3 text-sm
+  border border-white/10 bg-white/5
+  text-[#A1A1AA]
+  hover:text-white hover:bg-white/10
+  transition
+  disabled:opacity-50 disabled:cursor-not-allowed
+`;
+
+export default function TwoFactor() {
+  const router = useRouter();
+  const [status, setStatus] = useState<MFAStatus | null>(null);
+  const [enrollment, setEnrollment] = useState<Enrollment | null>(null);
+  // Recovery codes are shown once, right after they are generated.
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+  const [code, setCode] = useState("");
+  const [loading, setLoading] = useState(false);
+
+  async function loadStatus() {
+    try {
+      const res = await webApi.get<MFAStatus>("/auth/mfa");
+      setStatus(res.data);
+    } catch {}
+  }
+
+  useEffect(() => {
+    loadStatus();
+  }, []);
+
+  async function run(action: () => Promise<void>) {
+    setLoading(true);
+    try {
+      await action();
+    } catch {
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  function startEnrollment() {
+    run(async () => {
+      const res = await webApi.post<Enrollment>("/auth/mfa/totp");
+      setEnrollment(res.data);
+      setCode("");
+    });
+  }
+
+  function confirmEnrollment(e: React.FormEvent) {
+    e.preventDefault();
+    run(async () => {
+      const res = await webApi.post<{ recoveryCodes: string[] }>(
+        "/auth/mfa/totp/confirm",
+        { code },
+        jsonHeaders,
+      );
+      setEnrollment(null);
+      setRecoveryCodes(res.data.recoveryCodes);
+      setCode("");
+      toast.success("Two-factor authentication is enabled.");
+      await loadStatus();
+    });
+  }
+
+  function regenerateRecoveryCodes() {
+    run(async () => {
+      const res = await webApi.post<{ recoveryCodes: string[] }>(
+        "/auth/mfa/recovery-codes",
+        { code },
+        jsonHeaders,
+      );
+      setRecoveryCodes(res.data.recoveryCodes);
+      setCode("");
+      await loadStatus();
+    });
+  }
+
+  function disable() {
+    run(async () => {
+      await webApi.post("/auth/mfa/totp/disable", { code }, jsonHeaders);
+      // Disabling ends every session, this one included.
+      toast.success("Two-factor authentication is disabled.");
+      router.replace("/login");
+      router.refresh();
+    });
+  }
+
+  return (
+    <div className="min-h-screen bg-[#0B0C12] relative px-4">
+      <div className="absolute top-4 left-4 z-50">
+        <BackButton text="Back" />
+      </div>
+
+      <div className="flex justify-center pt-24">
+        <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
+          <div className="text-center space-y-2">
+            <h1 className="text-3xl sm:text-4xl font-semibold text-white">
+              Two-factor
+            </h1>
+            <p className="text-sm text-[#A1A1AA]">
+              Protect your account with codes from an authenticator app.
+              Admins need it to use the admin pages.
+            </p>
+          </div>
+
+          {recoveryCodes.length > 0 && (
+            <div className="space-y-2">
+              <p className="text-sm text-[#C7D2FE]">
+                Save these recovery codes somewhere safe. Each one logs you in
+                once if you lose your authenticator app. They won&apos;t be
+                shown again.
+              </p>
+              <ul className="grid grid-cols-2 gap-2 rounded-xl border border-white/10 bg-[#0F1016]/80 p-3 font-mono text-sm text-white">
+                {recoveryCodes.map((c) => (
+                  <li key={c}>{c}</li>
+                ))}
+              </ul>
+            </div>
+          )}
+
+          {status && !status.enabled && !enrollment && (
+            <button
+              type="button"
+              onClick={startEnrollment}
+              disabled={loading}
+              className={buttonClassName}
+            >
+              {loading ? "Starting..." : "Set up two-factor"}
+            </button>
+          )}
+
+          {enrollment && (
+            <form onSubmit={confirmEnrollment} className="space-y-4">
+              <div className="space-y-2 text-sm text-[#C7D2FE]">
+                <p>
+                  Add this key to your authenticator app, or{" "}
+                  <a
+                    href={enrollment.uri}
+                    className="text-[#60A5FA] underline underline-offset-4 hover:opacity-80 transition"
+                  >
+                    open it in the app
+                  </a>{" "}
+                  on this device.
+                </p>
+                <p className="break-all rounded-xl border border-white/10 bg-[#0F1016]/80 p-3 font-mono text-white">
+                  {enrollment.secret}
+                </p>
+              </div>
+
+              <div className="space-y-1.5">
+                <label className="block mb-1.5 text-sm font-medium text-[#C7D2FE]">
+                  Code from the app
+                </label>
+                <input
+                  type="text"
+                  required
+                  inputMode="numeric"
+                  autoComplete="one-time-code"
+                  value={code}
+                  onChange={(e) => setCode(e.target.value)}
+                  placeholder="123456"
+                  className={inputClassName}
+                />
+              </div>
+
+              <button
+                type="submit"
+                disabled={loading}
+                className={buttonClassName}
+              >
+                {loading ? "Verifying..." : "Enable"}
+              </button>
+            </form>
+          )}
+
+          {status && status.enabled && (
+            <div className="space-y-4">
+              <p className="text-sm text-[#C7D2FE] text-center">
+                Two-factor authentication is on. {status.recoveryCodesLeft}{" "}
+                recovery {status.recoveryCodesLeft === 1 ? "code" : "codes"}{" "}
+                left.
+              </p>
+
+              <div className="space-y-1.5">
+                <label className="block mb-1.5 text-sm font-medium text-[#C7D2FE]">
+                  Code from the app or a recovery code
+                </label>
+                <input
+                  type="text"
+                  autoComplete="one-time-code"
+                  value={code}
+                  onChange={(e) => setCode(e.target.value)}
+                  placeholder="123456"
+                  className={inputClassName}
+                />
+              </div>
+
+              <button
+                type="button"
+                onClick={regenerateRecoveryCodes}
+                disabled={loading || !code}
+                className={buttonClassName}
+              >
+                New recovery codes
+              </button>
+
+              <button
+                type="button"
+                onClick={disable}
+                disabled={loading || !code}
+                className={secondaryButtonClassName}
+              >
+                Disable two-factor
+              </button>
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,79 @@
+"use client";
+
+import Link from "next/link";
+import { useSearchParams } from "next/navigation";
+import { Suspense, useEffect, useRef, useState } from "react";
+import { webApi } from "../lib/api/client";
+
+type Status = "verifying" | "verified" | "failed";
+
+function VerifyEmailStatus() {
+  const token = useSearchParams().get("token") ?? "";
+  const [status, setStatus] = useState<Status>(
+    token ? "verifying" : "failed",
+  );
+  // Tokens work once; don't let a double-run effect spend it twice.
+  const sent = useRef(false);
+
+  useEffect(() => {
+    if (!token || sent.current) return;
+    sent.current = true;
+
+    webApi
+      .post(
+        "/auth/verify-email",
+        { token },
+        { headers: { "Content-Type": "application/json" } },
+      )
+      .then(() => setStatus("verified"))
+      .catch(() => setStatus("failed"));
+  }, [token]);
+
+  if (status === "verifying") {
+    return (
+      <p className="text-sm text-[#C7D2FE] text-center">
+        Verifying your email...
+      </p>
+    );
+  }
+
+  if (status === "verified") {
+    return (
+      <p className="text-sm text-[#C7D2FE] text-center">
+        Your email is verified. You can now{" "}
+        <Link
+          href="/competition"
+          className="text-[#60A5FA] underline underline-offset-4 hover:opacity-80 transition"
+        >
+          join a competition
+        </Link>
+        .
+      </p>
+    );
+  }
+
+  return (
+    <p className="text-sm text-[#C7D2FE] text-center">
+      This verification link is invalid or has expired. Log in and request a
+      new one from the competition page.
+    </p>
+  );
+}
+
+export default function VerifyEmail() {
+  return (
+    <div className="min-h-screen bg-[#0B0C12] flex items-center justify-center px-4">
+      <div className="w-full max-w-sm rounded-2xl border border-white/10 bg-[#151621]/80 backdrop-blur p-6 space-y-6">
+        <div className="text-center space-y-2">
+          <h1 className="text-3xl sm:text-4xl font-semibold text-white">
+            Verify email
+          </h1>
+        </div>
+
+        <Suspense>
+          <VerifyEmailStatus />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -1,13 +1,56 @@
 import { NextRequest, NextResponse } from "next/server";
 
-const publicRoutes = ["/login", "/register"];
+const publicRoutes = [
+  "/login",
+  "/register",
+  "/forgot-password",
+  "/reset-password",
+  "/verify-email",
+];
 
-export default function proxy(req: NextRequest) {
+// refreshSession trades the refresh token for a new session when the access
+// token cookie has expired, returning the backend's Set-Cookie headers.
+async function refreshSession(req: NextRequest): Promise<string[] | null> {
+  try {
+    const res = await fetch(
+      `${process.env.NEXT_PUBLIC_BACKEND_URL}/auth/refresh`,
+      {
+        method: "POST",
+        headers: { cookie: req.headers.get("cookie") ?? "" },
+      },
+    );
+    return res.ok ? res.headers.getSetCookie() : null;
+  } catch {
+    return null;
+  }
+}
+
+export default async function proxy(req: NextRequest) {
   const { pathname } = req.nextUrl;
 
-  const token = req.cookies.get("access_token")?.value;
-  const isLoggedIn = Boolean(token);
+  let token = req.cookies.get("access_token")?.value;
+  let setCookies: string[] = [];
+
+  if (!token && req.cookies.has("refresh_token")) {
+    const refreshed = await refreshSession(req);
+    if (refreshed) {
+      setCookies = refreshed;
+      // Let server components of this request see the new cookies too.
+      for (const c of refreshed) {
+        const [pair] = c.split(";");
+        const i = pair.indexOf("=");
+        req.cookies.set(pair.slice(0, i), pair.slice(i + 1));
+      }
+      token = req.cookies.get("access_token")?.value;
+    }
+  }
+
+  const res = route(req, pathname, Boolean(token));
+  for (const c of setCookies) res.headers.append("set-cookie", c);
+  return res;
+}
 
+function route(req: NextRequest, pathname: string, isLoggedIn: boolean) {
   const isPublic = publicRoutes.includes(pathname);
 
   // ✅ Competition paths should NEVER be redirected by "auth redirect" logic
@@ -32,7 +75,7 @@ export default function proxy(req: NextRequest) {
     return NextResponse.redirect(new URL("/competition", req.url));
   }
 
-  return NextResponse.next();
+  return NextResponse.next({ request: { headers: req.headers } });
 }
 
 export const config = {
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/filipcvejic/trading_tournament/db"
+	"github.com/filipcvejic/trading_tournament/internal/competition"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+	"github.com/filipcvejic/trading_tournament/internal/pubsub"
+	"github.com/filipcvejic/trading_tournament/internal/statement"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"log"
+	"os"
+	"time"
+)
+
+const usage = `usage: admin <command> [arguments]
+
+commands:
+  rebuild-stats [competition-id]   recompute competition member stats from trades,
+                                   for one competition or all of them
+  import-statement [flags] <file>  backfill a member's trades from an MT4/MT5
+                                   HTML statement or CSV history export;
+                                   run with -h for flags
+  retag-instruments                resolve the instrument of every stored trade
+                                   again, e.g. after adding broker aliases
+  detect-cheating [flags] <id>     scan a competition for copy trading and
+                                   hedging between members; run with -h for flags
+`
+
+func loadEnv() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if os.Getenv("DATABASE_URL") == "" {
+		log.Fatal("Required environment variable DATABASE_URL is not set")
+	}
+}
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	loadEnv()
+	ctx := context.Background()
+	database := db.NewDatabase(os.Getenv("DATABASE_URL"))
+	defer database.Pool.Close()
+
+	var err error
+	switch flag.Arg(0) {
+	case "rebuild-stats":
+		err = rebuildStats(ctx, database, flag.Args()[1:])
+	case "import-statement":
+		err = importStatement(ctx, database, flag.Args()[1:])
+	case "retag-instruments":
+		err = retagInstruments(ctx, database)
+	case "detect-cheating":
+		err = detectCheating(ctx, database, flag.Args()[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func rebuildStats(ctx context.Context, database *db.DB, args []string) error {
+	repo := competition.NewPostgresRepository(database)
+
+	var ids []uuid.UUID
+	if len(args) > 0 {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid competition id %q: %w", args[0], err)
+		}
+		ids = []uuid.UUID{id}
+	} else {
+		all, err := repo.ListIDs(ctx)
+		if err != nil {
+			return err
+		}
+		ids = all
+	}
+
+	for _, id := range ids {
+		n, err := repo.RebuildMemberStats(ctx, id)
+		if err != nil {
+			return fmt.Errorf("competition %s: %w", id, err)
+		}
+		log.Printf("competition %s: rebuilt stats for %d members", id, n)
+	}
+	return nil
+}
+
+func importStatement(ctx context.Context, database *db.DB, args []string) error {
+	fs := flag.NewFlagSet("import-statement", flag.ExitOnError)
+	competitionID := fs.String("competition", "", "competition id")
+	login := fs.Int64("login", 0, "trading account login")
+	format := fs.String("format", "", "html or csv; detected from the content when empty")
+	tz := fs.String("tz", "UTC", "broker server time zone of the statement")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without writing")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	id, err := uuid.Parse(*competitionID)
+	if err != nil {
+		return fmt.Errorf("invalid competition id %q: %w", *competitionID, err)
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", *tz, err)
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	trades, err := statement.Parse(data, statement.Format(*format), loc)
+	if err != nil {
+		return fmt.Errorf("parse statement: %w", err)
+	}
+
+	// Publishing through Postgres lets running API servers refresh their
+	// leaderboards; with the in-memory backend they catch up on the next change.
+	hub := pubsub.NewPostgresHub(database.Pool, competition.LeaderboardChannel)
+	instruments := instrument.NewService(instrument.NewPostgresRepository(database))
+	service, err := competition.NewService(competition.NewPostgresRepository(database), os.Getenv("CRYPTO_KEY"), hub, instruments)
+	if err != nil {
+		return err
+	}
+
+	outcomes, err := service.ImportTrades(ctx, id, *login, trades, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[model.TradeOutcomeStatus]int)
+	for _, o := range outcomes {
+		counts[o.Status]++
+		if o.Status == model.TradeRejected || o.Status == model.TradeQuarantined {
+			log.Printf("position %d %s: %s", o.PositionID, o.Status, o.Reason)
+		}
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "dry run: "
+	}
+	log.Printf("%sparsed %d trades: %d inserted, %d updated, %d duplicates, %d rejected, %d quarantined",
+		prefix, len(trades), counts[model.TradeInserted], counts[model.TradeUpdated],
+		counts[model.TradeDuplicate], counts[model.TradeRejected], counts[model.TradeQuarantined])
+	return nil
+}
+
+func retagInstruments(ctx context.Context, database *db.DB) error {
+	service := instrument.NewService(instrument.NewPostgresRepository(database))
+	n, err := service.RetagTrades(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("retagged %d trades", n)
+	return nil
+}
+
+func detectCheating(ctx context.Context, database *db.DB, args []string) error {
+	cfg := competition.DefaultCheatDetectionConfig
+
+	fs := flag.NewFlagSet("detect-cheating", flag.ExitOnError)
+	fs.DurationVar(&cfg.Window, "window", cfg.Window, "how far apart two entries may be opened to match")
+	fs.Float64Var(&cfg.VolumeTolerance, "volume-tolerance", cfg.VolumeTolerance, "relative volume difference still considered similar")
+	fs.IntVar(&cfg.MinMatches, "min-matches", cfg.MinMatches, "matched trades needed to open a case")
+	fs.Float64Var(&cfg.MinRatio, "min-ratio", cfg.MinRatio, "share of the less active account's trades that must match")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	id, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid competition id %q: %w", fs.Arg(0), err)
+	}
+
+	hub := pubsub.NewPostgresHub(database.Pool, competition.LeaderboardChannel)
+	instruments := instrument.NewService(instrument.NewPostgresRepository(database))
+	service, err := competition.NewService(competition.NewPostgresRepository(database), os.Getenv("CRYPTO_KEY"), hub, instruments)
+	if err != nil {
+		return err
+	}
+
+	n, err := service.ScanForCheating(ctx, id, cfg)
+	if err != nil {
+		return err
+	}
+	log.Printf("competition %s: %d suspicious pairs", id, n)
+	return nil
+}
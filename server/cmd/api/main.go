@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
 	"github.com/filipcvejic/trading_tournament/db"
+	"github.com/filipcvejic/trading_tournament/internal/apikey"
+	apikeyhttp "github.com/filipcvejic/trading_tournament/internal/apikey/http"
 	"github.com/filipcvejic/trading_tournament/internal/auth"
 	authhttp "github.com/filipcvejic/trading_tournament/internal/auth/http"
 	"github.com/filipcvejic/trading_tournament/internal/competition"
 	competitionhttp "github.com/filipcvejic/trading_tournament/internal/competition/http"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/filipcvejic/trading_tournament/internal/config"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+	instrumenthttp "github.com/filipcvejic/trading_tournament/internal/instrument/http"
+	"github.com/filipcvejic/trading_tournament/internal/mailer"
+	"github.com/filipcvejic/trading_tournament/internal/pubsub"
+	"github.com/filipcvejic/trading_tournament/internal/ratelimit"
+	ratelimithttp "github.com/filipcvejic/trading_tournament/internal/ratelimit/http"
 	"github.com/filipcvejic/trading_tournament/internal/trackedtrade"
 	trackedtradehttp "github.com/filipcvejic/trading_tournament/internal/trackedtrade/http"
 	"github.com/filipcvejic/trading_tournament/internal/tradingaccount"
@@ -19,7 +30,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 )
 
 func loadEnv() {
@@ -38,15 +52,65 @@ func loadEnv() {
 func main() {
 	loadEnv()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	database := db.NewDatabase(os.Getenv("DATABASE_URL"))
 
+	var hub pubsub.Hub = pubsub.NewMemoryHub()
+	if os.Getenv("PUBSUB_BACKEND") == "postgres" {
+		pgHub := pubsub.NewPostgresHub(database.Pool, competition.LeaderboardChannel)
+		go pgHub.Run(ctx)
+		hub = pgHub
+	}
+
+	apiKeyRepo := apikey.NewPostgresRepository(database)
+	apiKeyService, err := apikey.NewService(apiKeyRepo, os.Getenv("CRYPTO_KEY"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	apiKeyHandler := apikeyhttp.NewHandler(apiKeyService)
+	go apiKeyService.Run(ctx)
+
+	instrumentRepo := instrument.NewPostgresRepository(database)
+	instrumentService := instrument.NewService(instrumentRepo)
+	instrumentHandler := instrumenthttp.NewHandler(instrumentService)
+
 	competitionRepo := competition.NewPostgresRepository(database)
-	competitionService, err := competition.NewService(competitionRepo, os.Getenv("CRYPTO_KEY"))
+
+	competitionService, err := competition.NewService(competitionRepo, os.Getenv("CRYPTO_KEY"), hub, instrumentService)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	competitionHandler := competitionhttp.NewHandler(competitionService)
+	competitionService.Subscribe(func(e model.Event) {
+		log.Printf("competition %s: %s -> %s", e.CompetitionID, e.From, e.To)
+	})
+
+	scheduler := competition.NewScheduler(competitionService, competition.SchedulerConfig{
+		Interval:         config.Duration("COMPETITION_SCHEDULER_INTERVAL", 30*time.Second),
+		SettlementPeriod: config.Duration("COMPETITION_SETTLEMENT_PERIOD", time.Hour),
+		ArchiveAfter:     config.Duration("COMPETITION_ARCHIVE_AFTER", 30*24*time.Hour),
+	})
+	go scheduler.Run(ctx)
+
+	snapshotter := competition.NewSnapshotter(
+		competitionService,
+		config.Duration("LEADERBOARD_SNAPSHOT_INTERVAL", time.Hour),
+	)
+	go snapshotter.Run(ctx)
+
+	cheatDetector := competition.NewCheatDetector(
+		competitionService,
+		config.Duration("ANTICHEAT_INTERVAL", 15*time.Minute),
+		competition.DefaultCheatDetectionConfig,
+	)
+	go cheatDetector.Run(ctx)
+
+	broadcaster := competition.NewBroadcaster(competitionService, hub)
+	go broadcaster.Run(ctx)
+
+	competitionHandler := competitionhttp.NewHandler(competitionService, broadcaster, apiKeyService)
 
 	userRepo := user.NewPostgresRepository(database)
 	userService := user.NewService(userRepo)
@@ -56,17 +120,91 @@ func main() {
 	tradingAccountService := tradingaccount.NewService(tradingAccountRepo)
 	tradingAccountHandler := tradingaccounthttp.NewHandler(tradingAccountService)
 
+	var mail mailer.Mailer = mailer.NewLogMailer(os.Getenv("MAIL_DIR"))
+	if os.Getenv("MAILER") == "smtp" {
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Addr:     os.Getenv("SMTP_ADDR"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	}
+
 	refreshTokenRepo := auth.NewPostgresRefreshTokenRepository(database)
-	authService := auth.NewAuthService(userRepo, refreshTokenRepo, os.Getenv("JWT_SECRET"), 15)
-	authHandler := authhttp.NewHandler(authService, 60)
+	passwordResetRepo := auth.NewPostgresPasswordResetRepository(database)
+	emailVerificationRepo := auth.NewPostgresEmailVerificationRepository(database)
+	totpRepo := auth.NewPostgresTOTPRepository(database)
+
+	totpIssuer := os.Getenv("TOTP_ISSUER")
+	if totpIssuer == "" {
+		totpIssuer = "Trading Tournament"
+	}
+
+	authService, err := auth.NewAuthService(userRepo, refreshTokenRepo, passwordResetRepo, emailVerificationRepo, totpRepo, mail, auth.Config{
+		JWTSecret:            os.Getenv("JWT_SECRET"),
+		AccessTokenTTL:       config.Duration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:      config.Duration("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+		PasswordResetTTL:     config.Duration("PASSWORD_RESET_TTL", time.Hour),
+		EmailVerificationTTL: config.Duration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		AppURL:               strings.TrimSuffix(os.Getenv("APP_URL"), "/"),
+		CryptoKey:            os.Getenv("CRYPTO_KEY"),
+		TOTPIssuer:           totpIssuer,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if os.Getenv("RATELIMIT_BACKEND") == "postgres" {
+		rateLimitStore = ratelimit.NewPostgresStore(database)
+	}
+	rateLimitService := ratelimit.NewService(rateLimitStore, ratelimit.NewPostgresLockoutLog(database))
+	rateLimitHandler := ratelimithttp.NewHandler(rateLimitService)
+	go rateLimitService.Run(ctx)
+
+	authHandler := authhttp.NewHandler(authService, authhttp.Limits{
+		Login: rateLimitService.Limiter("login", ratelimit.Policy{
+			FreeFailures: 3,
+			BaseDelay:    time.Second,
+			LockoutAfter: 10,
+			Lockout:      15 * time.Minute,
+			MaxLockout:   24 * time.Hour,
+			Window:       24 * time.Hour,
+		}),
+		Register: rateLimitService.Limiter("register", ratelimit.Policy{
+			FreeFailures: 5,
+			BaseDelay:    time.Minute,
+			LockoutAfter: 20,
+			Lockout:      time.Hour,
+			MaxLockout:   24 * time.Hour,
+			Window:       time.Hour,
+		}),
+		PasswordReset: rateLimitService.Limiter("password-reset", ratelimit.Policy{
+			FreeFailures: 5,
+			BaseDelay:    time.Minute,
+			LockoutAfter: 20,
+			Lockout:      time.Hour,
+			MaxLockout:   24 * time.Hour,
+			Window:       time.Hour,
+		}),
+	})
 
 	trackedTradeRepo := trackedtrade.NewPostgresRepository(database)
-	trackedTradeService := trackedtrade.NewService(trackedTradeRepo)
-	trackedTradeHandler := trackedtradehttp.NewHandler(trackedTradeService)
+	trackedTradeService := trackedtrade.NewService(trackedTradeRepo, instrumentService)
+	trackedTradeHandler := trackedtradehttp.NewHandler(trackedTradeService, apiKeyService)
+
+	// Rate limits key on the client's IP. Behind a reverse proxy,
+	// TRUSTED_PROXIES must list the proxy's addresses, or every client
+	// shares the proxy's limits; forwarded headers from anyone else are
+	// ignored.
+	trustedProxies, err := ratelimit.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(trustedProxies.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -86,6 +224,9 @@ func main() {
 	tradingAccountHandler.RegisterRoutes(r)
 	authHandler.RegisterRoutes(r)
 	trackedTradeHandler.RegisterRoutes(r)
+	apiKeyHandler.RegisterRoutes(r)
+	instrumentHandler.RegisterRoutes(r)
+	rateLimitHandler.RegisterRoutes(r)
 
 	log.Println("listening on :8080")
 	if err := http.ListenAndServe(":8080", r); err != nil {
@@ -51,3 +51,19 @@ func (d *DB) WithTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
 
 	return tx.Commit(ctx)
 }
+
+// WithPgxTx is WithTx for work that needs the raw transaction as well, such
+// as COPY or statements sqlc cannot analyze.
+func (d *DB) WithPgxTx(ctx context.Context, fn func(tx pgx.Tx, q *sqlc.Queries) error) error {
+	tx, err := d.Pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx, d.Query.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
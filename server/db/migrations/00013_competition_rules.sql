@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE competition_rules (
+    competition_id UUID PRIMARY KEY REFERENCES competitions(id) ON DELETE CASCADE,
+    max_drawdown_percent NUMERIC,
+    max_daily_loss_percent NUMERIC,
+    max_lot_size NUMERIC,
+    allowed_symbols TEXT[] NOT NULL DEFAULT '{}',
+    min_trading_days INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE competition_members
+ADD COLUMN disqualified_at TIMESTAMPTZ,
+ADD COLUMN disqualification_reason TEXT,
+ADD COLUMN disqualifying_position_id BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE competition_members
+DROP COLUMN IF EXISTS disqualifying_position_id,
+DROP COLUMN IF EXISTS disqualification_reason,
+DROP COLUMN IF EXISTS disqualified_at;
+
+DROP TABLE IF EXISTS competition_rules;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE competitions
+ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft',
+ADD COLUMN status_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Competitions that already ended are left settling rather than finalized,
+-- so the scheduler finalizes them and freezes their results like any other.
+UPDATE competitions
+SET status = CASE
+    WHEN now() < starts_at THEN 'registration_open'
+    WHEN now() < ends_at THEN 'running'
+    ELSE 'settling'
+END;
+
+ALTER TABLE competitions
+ADD CONSTRAINT competitions_status_check
+CHECK (status IN ('draft', 'registration_open', 'running', 'settling', 'finalized', 'archived'));
+
+CREATE INDEX IF NOT EXISTS competitions_status_idx
+ON competitions (status);
+
+CREATE TABLE competition_events (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL REFERENCES competitions(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS competition_events;
+
+DROP INDEX IF EXISTS competitions_status_idx;
+
+ALTER TABLE competitions
+DROP CONSTRAINT IF EXISTS competitions_status_check;
+
+ALTER TABLE competitions
+DROP COLUMN IF EXISTS status_changed_at,
+DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
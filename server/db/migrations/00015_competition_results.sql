@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE competitions
+ADD COLUMN finalized_at TIMESTAMPTZ,
+ADD COLUMN finalized_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN results_hash TEXT;
+
+CREATE TABLE competition_results (
+    competition_id UUID NOT NULL REFERENCES competitions(id) ON DELETE CASCADE,
+    trading_account_login BIGINT NOT NULL,
+    rank INT NOT NULL,
+    username TEXT NOT NULL,
+    account_size NUMERIC NOT NULL,
+    profit NUMERIC NOT NULL,
+    equity NUMERIC NOT NULL,
+    gain_percent NUMERIC NOT NULL,
+    floating_profit NUMERIC NOT NULL DEFAULT 0,
+    net_deposits NUMERIC NOT NULL DEFAULT 0,
+    trade_count INT NOT NULL DEFAULT 0,
+    -- FLOAT8 rather than NUMERIC so an infinite ratio, such as the profit
+    -- factor of a member without losing trades, can be stored.
+    max_drawdown_percent FLOAT8 NOT NULL DEFAULT 0,
+    sharpe FLOAT8 NOT NULL DEFAULT 0,
+    sortino FLOAT8 NOT NULL DEFAULT 0,
+    profit_factor FLOAT8 NOT NULL DEFAULT 0,
+    win_rate FLOAT8 NOT NULL DEFAULT 0,
+    average_r FLOAT8 NOT NULL DEFAULT 0,
+    score NUMERIC,
+    disqualified_at TIMESTAMPTZ,
+    disqualification_reason TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (competition_id, trading_account_login)
+);
+
+CREATE INDEX IF NOT EXISTS competition_results_rank_idx
+ON competition_results (competition_id, rank);
+
+CREATE FUNCTION competition_results_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'competition_results rows are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Deleting is blocked too, so a finalized competition cannot be deleted
+-- along with its results either.
+CREATE TRIGGER competition_results_immutable
+BEFORE UPDATE OR DELETE ON competition_results
+FOR EACH ROW EXECUTE FUNCTION competition_results_immutable();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS competition_results;
+DROP FUNCTION IF EXISTS competition_results_immutable();
+
+ALTER TABLE competitions
+DROP COLUMN IF EXISTS results_hash,
+DROP COLUMN IF EXISTS finalized_by,
+DROP COLUMN IF EXISTS finalized_at;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE leaderboard_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL REFERENCES competitions(id) ON DELETE CASCADE,
+    taken_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS leaderboard_snapshots_competition_taken_at_idx
+ON leaderboard_snapshots (competition_id, taken_at DESC);
+
+CREATE TABLE leaderboard_snapshot_entries (
+    snapshot_id BIGINT NOT NULL REFERENCES leaderboard_snapshots(id) ON DELETE CASCADE,
+    trading_account_login BIGINT NOT NULL,
+    rank INT NOT NULL,
+    profit NUMERIC NOT NULL,
+    equity NUMERIC NOT NULL,
+    gain_percent NUMERIC NOT NULL,
+
+    PRIMARY KEY (snapshot_id, trading_account_login)
+);
+
+CREATE INDEX IF NOT EXISTS leaderboard_snapshot_entries_login_idx
+ON leaderboard_snapshot_entries (trading_account_login);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS leaderboard_snapshot_entries;
+DROP TABLE IF EXISTS leaderboard_snapshots;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE competition_ranking (
+    competition_id UUID PRIMARY KEY REFERENCES competitions(id) ON DELETE CASCADE,
+    mode VARCHAR(20) NOT NULL DEFAULT 'gain',
+    weights JSONB NOT NULL DEFAULT '{}',
+    tie_breaks TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT competition_ranking_mode_check CHECK (
+        mode IN ('gain', 'max_drawdown', 'sharpe', 'sortino', 'profit_factor', 'win_rate', 'average_r', 'trade_count', 'weighted')
+    )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS competition_ranking;
+-- +goose StatementEnd
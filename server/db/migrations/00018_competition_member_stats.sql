@@ -0,0 +1,120 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Everything the leaderboard derives from a member's closed trades is kept
+-- here and adjusted with every merged batch, so ranking never has to read
+-- the trades themselves. Net P&L is profit plus commission and swap.
+CREATE TABLE competition_member_stats (
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    realized_profit NUMERIC NOT NULL DEFAULT 0,
+    commission NUMERIC NOT NULL DEFAULT 0,
+    swap NUMERIC NOT NULL DEFAULT 0,
+    trade_count INT NOT NULL DEFAULT 0,
+    wins INT NOT NULL DEFAULT 0,
+    losses INT NOT NULL DEFAULT 0,
+    -- Net P&L of the winning trades, and of the losing ones as a positive
+    -- amount.
+    gross_profit NUMERIC NOT NULL DEFAULT 0,
+    gross_loss NUMERIC NOT NULL DEFAULT 0,
+    -- Deepest fall of the equity curve from its running peak, which starts
+    -- at the account size. It depends on the order of all trades, so it is
+    -- recomputed rather than adjusted.
+    max_drawdown_percent FLOAT8 NOT NULL DEFAULT 0,
+    last_trade_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (competition_id, trading_account_login),
+
+    CONSTRAINT competition_member_stats_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+-- Net P&L per member and UTC day of closing, for the daily returns behind
+-- Sharpe and Sortino.
+CREATE TABLE competition_member_daily_pnl (
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    day DATE NOT NULL,
+    net_profit NUMERIC NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (competition_id, trading_account_login, day),
+
+    CONSTRAINT competition_member_daily_pnl_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+INSERT INTO competition_member_stats (
+    competition_id, trading_account_login, realized_profit, commission, swap, trade_count,
+    wins, losses, gross_profit, gross_loss, last_trade_at
+)
+SELECT
+    competition_id,
+    trading_account_login,
+    SUM(profit),
+    SUM(commission),
+    SUM(swap),
+    COUNT(*),
+    COUNT(*) FILTER (WHERE profit + commission + swap > 0),
+    COUNT(*) FILTER (WHERE profit + commission + swap < 0),
+    COALESCE(SUM(profit + commission + swap) FILTER (WHERE profit + commission + swap > 0), 0),
+    COALESCE(-SUM(profit + commission + swap) FILTER (WHERE profit + commission + swap < 0), 0),
+    MAX(close_time)
+FROM trades
+GROUP BY competition_id, trading_account_login;
+
+INSERT INTO competition_member_daily_pnl (
+    competition_id, trading_account_login, day, net_profit
+)
+SELECT
+    competition_id,
+    trading_account_login,
+    (close_time AT TIME ZONE 'UTC')::DATE,
+    SUM(profit + commission + swap)
+FROM trades
+GROUP BY competition_id, trading_account_login, (close_time AT TIME ZONE 'UTC')::DATE;
+
+UPDATE competition_member_stats s
+SET max_drawdown_percent = d.max_drawdown_percent
+FROM (
+    SELECT
+        competition_id,
+        trading_account_login,
+        COALESCE(MAX((peak - equity) / peak * 100) FILTER (WHERE peak > 0), 0)::FLOAT8 AS max_drawdown_percent
+    FROM (
+        SELECT
+            competition_id,
+            trading_account_login,
+            equity,
+            GREATEST(account_size, MAX(equity) OVER (
+                PARTITION BY competition_id, trading_account_login ORDER BY close_time, position_id
+            )) AS peak
+        FROM (
+            SELECT
+                t.competition_id,
+                t.trading_account_login,
+                t.close_time,
+                t.position_id,
+                cm.account_size,
+                cm.account_size + SUM(t.profit + t.commission + t.swap) OVER (
+                    PARTITION BY t.competition_id, t.trading_account_login ORDER BY t.close_time, t.position_id
+                ) AS equity
+            FROM trades t
+            JOIN competition_members cm ON cm.competition_id = t.competition_id
+            AND cm.trading_account_login = t.trading_account_login
+        ) curve
+    ) peaks
+    GROUP BY competition_id, trading_account_login
+) d
+WHERE s.competition_id = d.competition_id
+AND s.trading_account_login = d.trading_account_login;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS competition_member_daily_pnl;
+DROP TABLE IF EXISTS competition_member_stats;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE trades
+ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE trade_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    trading_account_login BIGINT NOT NULL,
+    position_id BIGINT NOT NULL,
+    competition_id UUID NOT NULL REFERENCES competitions(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    changes JSONB NOT NULL,
+    revised_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (trading_account_login, position_id, version),
+
+    CONSTRAINT trade_revisions_trade_fkey
+        FOREIGN KEY (trading_account_login, position_id)
+            REFERENCES trades (trading_account_login, position_id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS trade_revisions_competition_idx
+ON trade_revisions (competition_id, revised_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS trade_revisions;
+
+ALTER TABLE trades
+DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE open_positions (
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    position_id BIGINT NOT NULL,
+    symbol TEXT NOT NULL,
+    side TEXT NOT NULL,
+    volume NUMERIC NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    open_price NUMERIC NOT NULL,
+    current_price NUMERIC NOT NULL,
+    profit NUMERIC NOT NULL,
+    commission NUMERIC NOT NULL DEFAULT 0,
+    swap NUMERIC NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (competition_id, trading_account_login, position_id),
+
+    CONSTRAINT open_positions_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE competition_member_floating (
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    floating_profit NUMERIC NOT NULL DEFAULT 0,
+    open_position_count INT NOT NULL DEFAULT 0,
+    snapshot_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (competition_id, trading_account_login),
+
+    CONSTRAINT competition_member_floating_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+ALTER TABLE competition_ranking
+ADD COLUMN equity_mode VARCHAR(20) NOT NULL DEFAULT 'equity'
+    CHECK (equity_mode IN ('equity', 'realized'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE competition_ranking
+DROP COLUMN IF EXISTS equity_mode;
+
+DROP TABLE IF EXISTS competition_member_floating;
+DROP TABLE IF EXISTS open_positions;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE balance_operations (
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    operation_id BIGINT NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('deposit', 'withdrawal', 'credit', 'bonus')),
+    amount NUMERIC NOT NULL CHECK (amount > 0),
+    occurred_at TIMESTAMPTZ NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (competition_id, trading_account_login, operation_id),
+
+    CONSTRAINT balance_operations_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE balance_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    operation_id BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'disqualified')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+
+    UNIQUE (competition_id, trading_account_login, operation_id),
+
+    CONSTRAINT balance_reviews_operation_fkey
+        FOREIGN KEY (competition_id, trading_account_login, operation_id)
+            REFERENCES balance_operations (competition_id, trading_account_login, operation_id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS balance_reviews_pending_idx
+ON balance_reviews (competition_id, created_at)
+WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS balance_reviews;
+DROP TABLE IF EXISTS balance_operations;
+-- +goose StatementEnd
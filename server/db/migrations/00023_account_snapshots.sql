@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE account_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    balance NUMERIC NOT NULL,
+    equity NUMERIC NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    leverage INT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT account_snapshots_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS account_snapshots_member_idx
+ON account_snapshots (competition_id, trading_account_login, taken_at);
+
+ALTER TABLE competition_members
+ADD COLUMN account_size_source VARCHAR(20)
+    CHECK (account_size_source IN ('snapshot', 'api', 'admin')),
+ADD COLUMN account_size_taken_at TIMESTAMPTZ;
+
+-- Sizes set before snapshots existed were entered by hand
+UPDATE competition_members
+SET account_size_source = 'admin'
+WHERE account_size > 0;
+
+CREATE TABLE account_size_audit (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    old_size NUMERIC NOT NULL,
+    new_size NUMERIC NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('snapshot', 'api', 'admin')),
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT account_size_audit_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS account_size_audit_member_idx
+ON account_size_audit (competition_id, trading_account_login, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_size_audit;
+
+ALTER TABLE competition_members
+DROP COLUMN IF EXISTS account_size_taken_at,
+DROP COLUMN IF EXISTS account_size_source;
+
+DROP TABLE IF EXISTS account_snapshots;
+-- +goose StatementEnd
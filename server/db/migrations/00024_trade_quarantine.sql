@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE competitions
+ADD COLUMN window_mode VARCHAR(20) NOT NULL DEFAULT 'close_time'
+    CHECK (window_mode IN ('close_time', 'open_inside', 'pro_rate'));
+
+CREATE TABLE trade_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL,
+    trading_account_login BIGINT NOT NULL,
+    position_id BIGINT NOT NULL,
+    symbol TEXT NOT NULL,
+    side TEXT NOT NULL,
+    volume NUMERIC NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    close_time TIMESTAMPTZ NOT NULL,
+    open_price NUMERIC NOT NULL,
+    close_price NUMERIC NOT NULL,
+    profit NUMERIC NOT NULL,
+    commission NUMERIC NOT NULL,
+    swap NUMERIC NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'dismissed')),
+    quarantined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+
+    UNIQUE (competition_id, trading_account_login, position_id),
+
+    CONSTRAINT trade_quarantine_member_fkey
+        FOREIGN KEY (competition_id, trading_account_login)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS trade_quarantine_pending_idx
+ON trade_quarantine (competition_id, quarantined_at)
+WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS trade_quarantine;
+
+ALTER TABLE competitions
+DROP COLUMN IF EXISTS window_mode;
+-- +goose StatementEnd
@@ -0,0 +1,111 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE instruments (
+    symbol VARCHAR(20) PRIMARY KEY,
+    asset_class VARCHAR(20) NOT NULL
+        CHECK (asset_class IN ('forex', 'metal', 'index', 'energy', 'crypto')),
+    contract_size NUMERIC NOT NULL CHECK (contract_size > 0),
+    pip_size NUMERIC NOT NULL CHECK (pip_size > 0),
+    digits INT NOT NULL CHECK (digits >= 0),
+    quote_currency CHAR(3) NOT NULL
+);
+
+-- Aliases are stored upper-case with everything but letters and digits
+-- removed. An empty broker applies to every broker.
+CREATE TABLE instrument_aliases (
+    broker TEXT NOT NULL DEFAULT '',
+    alias VARCHAR(32) NOT NULL,
+    symbol VARCHAR(20) NOT NULL REFERENCES instruments(symbol) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (broker, alias)
+);
+
+INSERT INTO instruments (symbol, asset_class, contract_size, pip_size, digits, quote_currency) VALUES
+    ('EURUSD', 'forex', 100000, 0.0001, 5, 'USD'),
+    ('GBPUSD', 'forex', 100000, 0.0001, 5, 'USD'),
+    ('AUDUSD', 'forex', 100000, 0.0001, 5, 'USD'),
+    ('NZDUSD', 'forex', 100000, 0.0001, 5, 'USD'),
+    ('USDJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('USDCHF', 'forex', 100000, 0.0001, 5, 'CHF'),
+    ('USDCAD', 'forex', 100000, 0.0001, 5, 'CAD'),
+    ('EURGBP', 'forex', 100000, 0.0001, 5, 'GBP'),
+    ('EURJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('EURCHF', 'forex', 100000, 0.0001, 5, 'CHF'),
+    ('EURAUD', 'forex', 100000, 0.0001, 5, 'AUD'),
+    ('EURCAD', 'forex', 100000, 0.0001, 5, 'CAD'),
+    ('GBPJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('GBPCHF', 'forex', 100000, 0.0001, 5, 'CHF'),
+    ('GBPAUD', 'forex', 100000, 0.0001, 5, 'AUD'),
+    ('AUDJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('AUDCAD', 'forex', 100000, 0.0001, 5, 'CAD'),
+    ('AUDNZD', 'forex', 100000, 0.0001, 5, 'NZD'),
+    ('NZDJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('CADJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('CHFJPY', 'forex', 100000, 0.01, 3, 'JPY'),
+    ('XAUUSD', 'metal', 100, 0.1, 2, 'USD'),
+    ('XAGUSD', 'metal', 5000, 0.01, 3, 'USD'),
+    ('US30', 'index', 1, 1, 1, 'USD'),
+    ('US500', 'index', 1, 0.1, 2, 'USD'),
+    ('NAS100', 'index', 1, 1, 1, 'USD'),
+    ('GER40', 'index', 1, 1, 1, 'EUR'),
+    ('UK100', 'index', 1, 1, 1, 'GBP'),
+    ('JPN225', 'index', 1, 1, 0, 'JPY'),
+    ('USOIL', 'energy', 1000, 0.01, 2, 'USD'),
+    ('UKOIL', 'energy', 1000, 0.01, 2, 'USD'),
+    ('BTCUSD', 'crypto', 1, 1, 2, 'USD'),
+    ('ETHUSD', 'crypto', 1, 0.1, 2, 'USD');
+
+INSERT INTO instrument_aliases (alias, symbol) VALUES
+    ('GOLD', 'XAUUSD'),
+    ('SILVER', 'XAGUSD'),
+    ('DJ30', 'US30'),
+    ('WS30', 'US30'),
+    ('DJI30', 'US30'),
+    ('SPX500', 'US500'),
+    ('SP500', 'US500'),
+    ('US500CASH', 'US500'),
+    ('USTEC', 'NAS100'),
+    ('NDX100', 'NAS100'),
+    ('US100', 'NAS100'),
+    ('DE40', 'GER40'),
+    ('DAX40', 'GER40'),
+    ('GER30', 'GER40'),
+    ('FTSE100', 'UK100'),
+    ('JP225', 'JPN225'),
+    ('NIKKEI225', 'JPN225'),
+    ('WTI', 'USOIL'),
+    ('XTIUSD', 'USOIL'),
+    ('USOUSD', 'USOIL'),
+    ('BRENT', 'UKOIL'),
+    ('XBRUSD', 'UKOIL'),
+    ('UKOUSD', 'UKOIL'),
+    ('XBTUSD', 'BTCUSD');
+
+ALTER TABLE trades
+ADD COLUMN instrument VARCHAR(20) REFERENCES instruments(symbol) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS trades_competition_instrument_idx
+ON trades (competition_id, instrument);
+
+ALTER TABLE trade_quarantine
+ADD COLUMN instrument VARCHAR(20) REFERENCES instruments(symbol) ON DELETE SET NULL;
+
+ALTER TABLE tracked_trades
+ADD COLUMN instrument VARCHAR(20) REFERENCES instruments(symbol) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tracked_trades
+DROP COLUMN IF EXISTS instrument;
+
+ALTER TABLE trade_quarantine
+DROP COLUMN IF EXISTS instrument;
+
+ALTER TABLE trades
+DROP COLUMN IF EXISTS instrument;
+
+DROP TABLE IF EXISTS instrument_aliases;
+DROP TABLE IF EXISTS instruments;
+-- +goose StatementEnd
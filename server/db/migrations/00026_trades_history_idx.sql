@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS trades_login_close_time_position_idx
+ON trades (trading_account_login, close_time DESC, position_id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS trades_login_close_time_position_idx;
+-- +goose StatementEnd
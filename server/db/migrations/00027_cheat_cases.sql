@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE cheat_cases (
+    id BIGSERIAL PRIMARY KEY,
+    competition_id UUID NOT NULL,
+    login_a BIGINT NOT NULL,
+    login_b BIGINT NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('copy_trading', 'hedging')),
+    score NUMERIC NOT NULL,
+    matched_trades INTEGER NOT NULL,
+    evidence JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'dismissed', 'disqualified')),
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+
+    CHECK (login_a < login_b),
+    UNIQUE (competition_id, login_a, login_b, kind),
+
+    CONSTRAINT cheat_cases_member_a_fkey
+        FOREIGN KEY (competition_id, login_a)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE,
+
+    CONSTRAINT cheat_cases_member_b_fkey
+        FOREIGN KEY (competition_id, login_b)
+            REFERENCES competition_members (competition_id, trading_account_login)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cheat_cases_open_idx
+ON cheat_cases (competition_id, score DESC)
+WHERE status = 'open';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cheat_cases;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens were stored in plain text and never handed out; they cannot be
+-- hashed after the fact, so they are dropped.
+DELETE FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN token,
+    ADD COLUMN token_hash TEXT NOT NULL UNIQUE,
+    ADD COLUMN family_id UUID NOT NULL,
+    ADD COLUMN revoked_at TIMESTAMPTZ,
+    ADD COLUMN replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_idx
+ON refresh_tokens (family_id);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_idx
+ON refresh_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS refresh_tokens_user_idx;
+DROP INDEX IF EXISTS refresh_tokens_family_idx;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN replaced_by,
+    DROP COLUMN revoked_at,
+    DROP COLUMN family_id,
+    DROP COLUMN token_hash,
+    ADD COLUMN token VARCHAR(255) UNIQUE NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE rate_limits (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    blocked_until TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_last_failure_idx
+ON rate_limits (last_failure_at);
+
+CREATE TABLE rate_limit_lockouts (
+    id BIGSERIAL PRIMARY KEY,
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    failures INTEGER NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    released_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_lockouts_created_idx
+ON rate_limit_lockouts (created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limit_lockouts;
+DROP TABLE IF EXISTS rate_limits;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- name: InsertAccountSnapshot :exec
+INSERT INTO account_snapshots (
+    competition_id, trading_account_login, balance, equity, currency, leverage, taken_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: InsertAccountSizeAudit :exec
+INSERT INTO account_size_audit (
+    competition_id, trading_account_login, old_size, new_size, source, changed_by, api_key_id, reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ListAccountSizeAudit :many
+SELECT * FROM account_size_audit
+WHERE competition_id = $1
+AND trading_account_login = $2
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,35 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+    id, name, signing_key_encrypted, scopes, created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetApiKey :one
+SELECT * FROM api_keys
+WHERE id = $1;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys
+ORDER BY created_at DESC;
+
+-- name: RevokeApiKey :execrows
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1
+AND revoked_at IS NULL;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: InsertApiKeyNonce :execrows
+INSERT INTO api_key_nonces (api_key_id, nonce)
+VALUES ($1, $2)
+ON CONFLICT (api_key_id, nonce) DO NOTHING;
+
+-- name: DeleteApiKeyNoncesBefore :exec
+DELETE FROM api_key_nonces
+WHERE created_at < $1;
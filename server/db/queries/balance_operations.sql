@@ -0,0 +1,46 @@
+-- name: InsertBalanceOperation :execrows
+INSERT INTO balance_operations (
+    competition_id, trading_account_login, operation_id, type, amount, occurred_at, comment
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) ON CONFLICT (competition_id, trading_account_login, operation_id)
+DO NOTHING;
+
+-- name: CreateBalanceReview :exec
+INSERT INTO balance_reviews (
+    competition_id, trading_account_login, operation_id, reason
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (competition_id, trading_account_login, operation_id)
+DO NOTHING;
+
+-- name: ListBalanceReviews :many
+SELECT
+    r.id,
+    r.trading_account_login,
+    r.operation_id,
+    o.type,
+    o.amount::FLOAT8 AS amount,
+    o.occurred_at,
+    r.reason,
+    r.status,
+    r.created_at,
+    r.reviewed_by,
+    r.reviewed_at
+FROM balance_reviews r
+JOIN balance_operations o ON o.competition_id = r.competition_id
+AND o.trading_account_login = r.trading_account_login
+AND o.operation_id = r.operation_id
+WHERE r.competition_id = @competition_id
+AND (sqlc.narg(status)::TEXT IS NULL OR r.status = sqlc.narg(status)::TEXT)
+ORDER BY r.created_at ASC, r.id ASC;
+
+-- name: ResolveBalanceReview :one
+UPDATE balance_reviews
+SET status = @status,
+    reviewed_by = @reviewed_by,
+    reviewed_at = now()
+WHERE id = @id
+AND competition_id = @competition_id
+AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,34 @@
+-- name: UpsertCheatCase :exec
+INSERT INTO cheat_cases (
+    competition_id,
+    login_a,
+    login_b,
+    kind,
+    score,
+    matched_trades,
+    evidence
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (competition_id, login_a, login_b, kind) DO UPDATE
+SET score = EXCLUDED.score,
+    matched_trades = EXCLUDED.matched_trades,
+    evidence = EXCLUDED.evidence,
+    updated_at = now()
+WHERE cheat_cases.status = 'open';
+
+-- name: ListCheatCases :many
+SELECT * FROM cheat_cases
+WHERE competition_id = @competition_id
+AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status)::TEXT)
+ORDER BY score DESC, id ASC;
+
+-- name: ResolveCheatCase :one
+UPDATE cheat_cases
+SET status = @status,
+    resolved_by = @resolved_by,
+    resolved_at = now()
+WHERE id = @id
+AND competition_id = @competition_id
+AND status = 'open'
+RETURNING *;
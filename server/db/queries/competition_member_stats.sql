@@ -0,0 +1,94 @@
+-- name: LockCompetition :exec
+SELECT id
+FROM competitions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: LockCompetitionForWrite :one
+SELECT status
+FROM competitions
+WHERE id = $1
+FOR KEY SHARE;
+
+-- name: DeleteCompetitionMemberStats :exec
+DELETE FROM competition_member_stats
+WHERE competition_id = $1;
+
+-- name: RebuildCompetitionMemberStats :execrows
+INSERT INTO competition_member_stats (
+    competition_id, trading_account_login, realized_profit, commission, swap, trade_count,
+    wins, losses, gross_profit, gross_loss, last_trade_at
+)
+SELECT
+    competition_id,
+    trading_account_login,
+    SUM(profit),
+    SUM(commission),
+    SUM(swap),
+    COUNT(*),
+    COUNT(*) FILTER (WHERE profit + commission + swap > 0),
+    COUNT(*) FILTER (WHERE profit + commission + swap < 0),
+    COALESCE(SUM(profit + commission + swap) FILTER (WHERE profit + commission + swap > 0), 0),
+    COALESCE(-SUM(profit + commission + swap) FILTER (WHERE profit + commission + swap < 0), 0),
+    MAX(close_time)
+FROM trades
+WHERE competition_id = $1
+GROUP BY competition_id, trading_account_login;
+
+-- name: DeleteCompetitionMemberDailyPnl :exec
+DELETE FROM competition_member_daily_pnl
+WHERE competition_id = $1;
+
+-- name: RebuildCompetitionMemberDailyPnl :exec
+INSERT INTO competition_member_daily_pnl (
+    competition_id, trading_account_login, day, net_profit
+)
+SELECT
+    competition_id,
+    trading_account_login,
+    (close_time AT TIME ZONE 'UTC')::DATE,
+    SUM(profit + commission + swap)
+FROM trades
+WHERE competition_id = $1
+GROUP BY competition_id, trading_account_login, (close_time AT TIME ZONE 'UTC')::DATE;
+
+-- name: RefreshCompetitionMemberDrawdown :exec
+UPDATE competition_member_stats s
+SET max_drawdown_percent = d.max_drawdown_percent,
+    updated_at = now()
+FROM (
+    SELECT
+        trading_account_login,
+        COALESCE(MAX((peak - equity) / peak * 100) FILTER (WHERE peak > 0), 0)::FLOAT8 AS max_drawdown_percent
+    FROM (
+        SELECT
+            trading_account_login,
+            equity,
+            GREATEST(account_size, MAX(equity) OVER (
+                PARTITION BY trading_account_login ORDER BY close_time, position_id
+            )) AS peak
+        FROM (
+            SELECT
+                t.trading_account_login,
+                t.close_time,
+                t.position_id,
+                cm.account_size,
+                cm.account_size + SUM(t.profit + t.commission + t.swap) OVER (
+                    PARTITION BY t.trading_account_login ORDER BY t.close_time, t.position_id
+                ) AS equity
+            FROM trades t
+            JOIN competition_members cm ON cm.competition_id = t.competition_id
+            AND cm.trading_account_login = t.trading_account_login
+            WHERE t.competition_id = @competition_id
+            AND (sqlc.narg(trading_account_login)::BIGINT IS NULL OR t.trading_account_login = sqlc.narg(trading_account_login)::BIGINT)
+        ) curve
+    ) peaks
+    GROUP BY trading_account_login
+) d
+WHERE s.competition_id = @competition_id
+AND s.trading_account_login = d.trading_account_login;
+
+-- name: ListCompetitionIDs :many
+SELECT id
+FROM competitions
+ORDER BY created_at ASC;
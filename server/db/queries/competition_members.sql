@@ -6,17 +6,43 @@ SELECT
     $1, $2, 0
 FROM competitions c
 WHERE c.id = $1
+AND c.status = 'registration_open'
 AND now() < c.starts_at
 RETURNING competition_id;
 
 -- name: UpdateCompetitionMemberAccountSize :exec
 UPDATE competition_members
-SET account_size = $3
+SET account_size = $3,
+    account_size_source = $4,
+    account_size_taken_at = $5
 WHERE competition_id = $1
 AND trading_account_login = $2;
 
+-- name: LockCompetitionMemberAccountSize :one
+SELECT account_size, account_size_source, account_size_taken_at
+FROM competition_members
+WHERE competition_id = $1
+AND trading_account_login = $2
+FOR UPDATE;
+
+-- name: LockCompetitionMember :exec
+SELECT trading_account_login
+FROM competition_members
+WHERE competition_id = $1
+AND trading_account_login = $2
+FOR UPDATE;
+
 -- name: GetCompetitionMemberAccountSize :one
 SELECT account_size
 FROM competition_members
 WHERE competition_id = $1
 AND trading_account_login = $2;
+
+-- name: DisqualifyCompetitionMember :execrows
+UPDATE competition_members
+SET disqualified_at = @disqualified_at::TIMESTAMPTZ,
+    disqualification_reason = @reason::TEXT,
+    disqualifying_position_id = sqlc.narg(position_id)::BIGINT
+WHERE competition_id = @competition_id
+AND trading_account_login = @trading_account_login
+AND disqualified_at IS NULL;
@@ -0,0 +1,16 @@
+-- name: UpsertCompetitionRanking :one
+INSERT INTO competition_ranking (
+    competition_id, mode, weights, tie_breaks, equity_mode
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (competition_id) DO UPDATE
+SET mode = EXCLUDED.mode,
+    weights = EXCLUDED.weights,
+    tie_breaks = EXCLUDED.tie_breaks,
+    equity_mode = EXCLUDED.equity_mode,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCompetitionRanking :one
+SELECT * FROM competition_ranking
+WHERE competition_id = $1;
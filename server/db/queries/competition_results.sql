@@ -0,0 +1,40 @@
+-- name: CreateCompetitionResult :exec
+INSERT INTO competition_results (
+    competition_id, trading_account_login, rank, username, account_size, profit, equity, gain_percent,
+    floating_profit, net_deposits, trade_count, max_drawdown_percent, sharpe, sortino, profit_factor, win_rate, average_r, score,
+    disqualified_at, disqualification_reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+);
+
+-- name: ListCompetitionResults :many
+SELECT * FROM competition_results
+WHERE competition_id = @competition_id
+AND starts_with(lower(username), @username_prefix::TEXT)
+AND (@status::TEXT = '' OR (@status::TEXT = 'disqualified') = (disqualified_at IS NOT NULL))
+AND trade_count >= @min_trades::INT
+ORDER BY rank ASC
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: CountCompetitionResults :one
+SELECT COUNT(*) FROM competition_results
+WHERE competition_id = @competition_id
+AND starts_with(lower(username), @username_prefix::TEXT)
+AND (@status::TEXT = '' OR (@status::TEXT = 'disqualified') = (disqualified_at IS NOT NULL))
+AND trade_count >= @min_trades::INT;
+
+-- name: GetCompetitionResultPosition :one
+SELECT position::BIGINT FROM (
+    SELECT
+        ta.user_id,
+        ROW_NUMBER() OVER (ORDER BY r.rank) AS position
+    FROM competition_results r
+    LEFT JOIN trading_accounts ta ON ta.login = r.trading_account_login
+    WHERE r.competition_id = @competition_id
+    AND starts_with(lower(r.username), @username_prefix::TEXT)
+    AND (@status::TEXT = '' OR (@status::TEXT = 'disqualified') = (r.disqualified_at IS NOT NULL))
+    AND r.trade_count >= @min_trades::INT
+) filtered
+WHERE user_id = @user_id
+ORDER BY position
+LIMIT 1;
@@ -0,0 +1,17 @@
+-- name: UpsertCompetitionRules :one
+INSERT INTO competition_rules (
+    competition_id, max_drawdown_percent, max_daily_loss_percent, max_lot_size, allowed_symbols, min_trading_days
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) ON CONFLICT (competition_id) DO UPDATE
+SET max_drawdown_percent = EXCLUDED.max_drawdown_percent,
+    max_daily_loss_percent = EXCLUDED.max_daily_loss_percent,
+    max_lot_size = EXCLUDED.max_lot_size,
+    allowed_symbols = EXCLUDED.allowed_symbols,
+    min_trading_days = EXCLUDED.min_trading_days,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCompetitionRules :one
+SELECT * FROM competition_rules
+WHERE competition_id = $1;
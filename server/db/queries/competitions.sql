@@ -1,16 +1,10 @@
 -- name: CreateCompetition :one
 INSERT INTO competitions (
-    id, name, starts_at, ends_at
+    id, name, starts_at, ends_at, window_mode
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
--- name: GetCompetitionStartTime :one
-SELECT starts_at
-FROM competitions
-WHERE id = $1;
-
-
 -- name: GetCompetitionByID :one
 SELECT * FROM competitions
 WHERE id = $1;
@@ -19,6 +13,41 @@ WHERE id = $1;
 SELECT * FROM competitions
 ORDER BY starts_at DESC;
 
+-- name: ListCompetitionsByStatus :many
+SELECT * FROM competitions
+WHERE status = $1
+ORDER BY starts_at ASC;
+
+-- name: UpdateCompetitionStatus :execrows
+UPDATE competitions
+SET status = @to_status,
+    status_changed_at = now()
+WHERE id = @id
+AND status = @from_status;
+
+-- name: UpdateCompetitionWindowMode :execrows
+UPDATE competitions
+SET window_mode = @window_mode
+WHERE id = @id
+AND status IN ('draft', 'registration_open');
+
+-- name: FinalizeCompetition :execrows
+UPDATE competitions
+SET status = 'finalized',
+    status_changed_at = now(),
+    finalized_at = now(),
+    finalized_by = sqlc.narg(finalized_by),
+    results_hash = @results_hash::TEXT
+WHERE id = @id
+AND status = 'settling';
+
+-- name: CreateCompetitionEvent :exec
+INSERT INTO competition_events (
+    competition_id, from_status, to_status
+) VALUES (
+    $1, $2, $3
+);
+
 -- name: GetCompetitionUserState :one
 SELECT
     EXISTS (
@@ -38,6 +67,6 @@ SELECT
 -- name: GetCurrentCompetition :one
 SELECT *
 FROM competitions
-WHERE now() < ends_at
+WHERE status IN ('registration_open', 'running', 'settling')
 ORDER BY starts_at ASC
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
@@ -0,0 +1,24 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: ListEmailVerificationTokenTimes :many
+SELECT created_at
+FROM email_verification_tokens
+WHERE user_id = $1
+AND created_at > $2
+ORDER BY created_at DESC;
+
+-- name: ConsumeEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = now()
+WHERE token_hash = $1
+AND used_at IS NULL
+AND expires_at > now()
+RETURNING user_id;
+
+-- name: InvalidateEmailVerificationTokens :exec
+UPDATE email_verification_tokens
+SET used_at = now()
+WHERE user_id = $1
+AND used_at IS NULL;
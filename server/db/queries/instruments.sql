@@ -0,0 +1,36 @@
+-- name: ListInstruments :many
+SELECT * FROM instruments
+ORDER BY asset_class ASC, symbol ASC;
+
+-- name: ListInstrumentAliases :many
+SELECT * FROM instrument_aliases
+ORDER BY broker ASC, alias ASC;
+
+-- name: UpsertInstrumentAlias :one
+INSERT INTO instrument_aliases (
+    broker, alias, symbol
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (broker, alias) DO UPDATE
+SET symbol = EXCLUDED.symbol
+RETURNING *;
+
+-- name: DeleteInstrumentAlias :execrows
+DELETE FROM instrument_aliases
+WHERE broker = $1
+AND alias = $2;
+
+-- name: ListTradeSymbols :many
+SELECT DISTINCT ta.broker, t.symbol
+FROM trades t
+JOIN trading_accounts ta ON ta.login = t.trading_account_login
+ORDER BY ta.broker ASC, t.symbol ASC;
+
+-- name: TagTradeInstruments :execrows
+UPDATE trades t
+SET instrument = sqlc.narg(instrument)
+FROM trading_accounts ta
+WHERE ta.login = t.trading_account_login
+AND ta.broker = @broker
+AND t.symbol = @symbol
+AND t.instrument IS DISTINCT FROM sqlc.narg(instrument);
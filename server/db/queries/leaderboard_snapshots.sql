@@ -0,0 +1,47 @@
+-- name: CreateLeaderboardSnapshot :one
+INSERT INTO leaderboard_snapshots (competition_id)
+VALUES ($1)
+RETURNING id;
+
+-- name: CreateLeaderboardSnapshotEntry :exec
+INSERT INTO leaderboard_snapshot_entries (
+    snapshot_id, trading_account_login, rank, profit, equity, gain_percent
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetLatestLeaderboardSnapshotEntries :many
+SELECT
+    e.trading_account_login,
+    e.rank,
+    e.gain_percent
+FROM leaderboard_snapshot_entries e
+WHERE e.snapshot_id = (
+    SELECT s.id
+    FROM leaderboard_snapshots s
+    WHERE s.competition_id = $1
+    ORDER BY s.taken_at DESC
+    LIMIT 1
+);
+
+-- name: GetBestLeaderboardRanks :many
+SELECT
+    e.trading_account_login,
+    MIN(e.rank)::INT AS best_rank
+FROM leaderboard_snapshot_entries e
+JOIN leaderboard_snapshots s ON s.id = e.snapshot_id
+WHERE s.competition_id = $1
+GROUP BY e.trading_account_login;
+
+-- name: ListMemberRankHistory :many
+SELECT
+    s.taken_at,
+    e.rank,
+    e.profit,
+    e.equity,
+    e.gain_percent
+FROM leaderboard_snapshot_entries e
+JOIN leaderboard_snapshots s ON s.id = e.snapshot_id
+WHERE s.competition_id = $1
+AND e.trading_account_login = $2
+ORDER BY s.taken_at ASC;
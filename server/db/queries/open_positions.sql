@@ -0,0 +1,42 @@
+-- name: UpsertCompetitionMemberFloating :execrows
+INSERT INTO competition_member_floating (
+    competition_id, trading_account_login, floating_profit, open_position_count, snapshot_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (competition_id, trading_account_login) DO UPDATE
+SET floating_profit = EXCLUDED.floating_profit,
+    open_position_count = EXCLUDED.open_position_count,
+    snapshot_at = EXCLUDED.snapshot_at
+WHERE competition_member_floating.snapshot_at < EXCLUDED.snapshot_at;
+
+-- name: DeleteMemberOpenPositions :exec
+DELETE FROM open_positions
+WHERE competition_id = $1
+AND trading_account_login = $2;
+
+-- name: InsertOpenPosition :exec
+INSERT INTO open_positions (
+    competition_id, trading_account_login, position_id, symbol, side, volume, open_time, open_price, current_price, profit, commission, swap
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+);
+
+-- name: ListMemberOpenPositions :many
+SELECT * FROM open_positions
+WHERE competition_id = $1
+AND trading_account_login = $2
+ORDER BY open_time ASC, position_id ASC;
+
+-- name: CloseOpenPositions :exec
+WITH closed AS (
+    DELETE FROM open_positions
+    WHERE competition_id = @competition_id
+    AND trading_account_login = @trading_account_login
+    AND position_id = ANY(@position_ids::BIGINT[])
+    RETURNING profit + commission + swap AS floating_profit
+)
+UPDATE competition_member_floating f
+SET floating_profit = f.floating_profit - COALESCE((SELECT SUM(floating_profit) FROM closed), 0),
+    open_position_count = f.open_position_count - (SELECT COUNT(*) FROM closed)::INT
+WHERE f.competition_id = @competition_id
+AND f.trading_account_login = @trading_account_login;
@@ -0,0 +1,47 @@
+-- name: GetRateLimit :one
+SELECT * FROM rate_limits
+WHERE key = $1;
+
+-- name: AddRateLimitFailure :one
+INSERT INTO rate_limits (key, failures, last_failure_at)
+VALUES (@key, 1, @failed_at)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN rate_limits.last_failure_at < @window_start THEN 1
+        ELSE rate_limits.failures + 1
+    END,
+    last_failure_at = EXCLUDED.last_failure_at
+RETURNING failures;
+
+-- name: BlockRateLimit :exec
+UPDATE rate_limits
+SET blocked_until = $2
+WHERE key = $1;
+
+-- name: DeleteRateLimit :exec
+DELETE FROM rate_limits
+WHERE key = $1;
+
+-- name: DeleteStaleRateLimits :execrows
+DELETE FROM rate_limits
+WHERE last_failure_at < @before
+AND (blocked_until IS NULL OR blocked_until < @before);
+
+-- name: CreateRateLimitLockout :exec
+INSERT INTO rate_limit_lockouts (scope, key, failures, locked_until)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListRateLimitLockouts :many
+SELECT * FROM rate_limit_lockouts
+WHERE (sqlc.narg(scope)::TEXT IS NULL OR scope = sqlc.narg(scope)::TEXT)
+AND (NOT @active_only::BOOLEAN OR (released_at IS NULL AND locked_until > now()))
+ORDER BY created_at DESC, id DESC
+LIMIT @row_limit;
+
+-- name: ReleaseRateLimitLockout :one
+UPDATE rate_limit_lockouts
+SET released_by = @released_by,
+    released_at = now()
+WHERE id = @id
+AND released_at IS NULL
+RETURNING *;
@@ -1,15 +1,47 @@
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (token, user_id, expires_at)
-VALUES ($1, $2, $3)
-    RETURNING token, user_id, expires_at, revoked, created_at;
+INSERT INTO refresh_tokens (token_hash, user_id, family_id, expires_at, mfa)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
 
--- name: GetRefreshToken :one
-SELECT token, user_id, expires_at, revoked, created_at
-FROM refresh_tokens
-WHERE token = $1
-    LIMIT 1;
+-- name: GetRefreshTokenForUpdate :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
 
--- name: RevokeRefreshToken :exec
+-- name: HasLiveRefreshToken :one
+SELECT EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE family_id = $1
+    AND NOT revoked
+    AND expires_at > now()
+) AS live;
+
+-- name: ReplaceRefreshToken :exec
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now(),
+    replaced_by = @replaced_by
+WHERE id = @id;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE family_id = $1
+AND NOT revoked;
+
+-- name: RevokeRefreshTokenFamilyByHash :execrows
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE family_id = (
+    SELECT family_id FROM refresh_tokens WHERE token_hash = $1
+)
+AND NOT revoked;
+
+-- name: RevokeUserRefreshTokens :execrows
 UPDATE refresh_tokens
-SET revoked = TRUE
-WHERE token = $1;
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE user_id = $1
+AND NOT revoked;
@@ -0,0 +1,48 @@
+-- name: GetTOTP :one
+SELECT * FROM user_totp
+WHERE user_id = $1;
+
+-- name: SaveTOTPEnrollment :execrows
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = now()
+WHERE user_totp.enabled_at IS NULL;
+
+-- name: EnableTOTP :execrows
+UPDATE user_totp
+SET enabled_at = now()
+WHERE user_id = $1
+AND enabled_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = @step
+WHERE user_id = @user_id
+AND last_used_step < @step;
+
+-- name: DeleteTOTP :exec
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+AND code_hash = $2
+AND used_at IS NULL;
+
+-- name: CountRecoveryCodes :one
+SELECT count(*) FROM totp_recovery_codes
+WHERE user_id = $1
+AND used_at IS NULL;
@@ -6,9 +6,10 @@ INSERT INTO tracked_trades (
     open_price,
     stop_loss,
     volume,
-    opened_at
+    opened_at,
+    instrument
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 ON CONFLICT (position_id) DO NOTHING;
 
@@ -26,7 +27,8 @@ SELECT
     stop_loss,
     volume,
     opened_at,
-    closed_at
+    closed_at,
+    instrument
 FROM tracked_trades
 ORDER BY opened_at ASC;
 
@@ -0,0 +1,44 @@
+-- name: QuarantineTrade :exec
+INSERT INTO trade_quarantine (
+    competition_id, trading_account_login, position_id, symbol, side, volume,
+    open_time, close_time, open_price, close_price, profit, commission, swap, reason, instrument
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) ON CONFLICT (competition_id, trading_account_login, position_id) DO UPDATE
+SET symbol = EXCLUDED.symbol,
+    side = EXCLUDED.side,
+    volume = EXCLUDED.volume,
+    open_time = EXCLUDED.open_time,
+    close_time = EXCLUDED.close_time,
+    open_price = EXCLUDED.open_price,
+    close_price = EXCLUDED.close_price,
+    profit = EXCLUDED.profit,
+    commission = EXCLUDED.commission,
+    swap = EXCLUDED.swap,
+    reason = EXCLUDED.reason,
+    instrument = EXCLUDED.instrument,
+    quarantined_at = now()
+WHERE trade_quarantine.status = 'pending';
+
+-- name: ReleaseQuarantinedTrades :exec
+DELETE FROM trade_quarantine
+WHERE competition_id = @competition_id
+AND trading_account_login = @trading_account_login
+AND position_id = ANY(@position_ids::BIGINT[])
+AND status = 'pending';
+
+-- name: ListQuarantinedTrades :many
+SELECT * FROM trade_quarantine
+WHERE competition_id = @competition_id
+AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status)::TEXT)
+ORDER BY quarantined_at ASC, id ASC;
+
+-- name: ResolveQuarantinedTrade :one
+UPDATE trade_quarantine
+SET status = @status,
+    reviewed_by = @reviewed_by,
+    reviewed_at = now()
+WHERE id = @id
+AND competition_id = @competition_id
+AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,6 @@
+-- name: ListTradeRevisions :many
+SELECT * FROM trade_revisions
+WHERE competition_id = $1
+AND trading_account_login = $2
+AND position_id = $3
+ORDER BY version ASC;
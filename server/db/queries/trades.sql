@@ -1,12 +1,36 @@
--- name: InsertTrade :exec
-INSERT INTO trades (
-    trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-) ON CONFLICT (trading_account_login, position_id)
-DO NOTHING;
+-- name: ListTradeHistory :many
+SELECT * FROM trades
+WHERE trading_account_login = @trading_account_login
+AND (sqlc.narg(competition_id)::UUID IS NULL OR competition_id = sqlc.narg(competition_id)::UUID)
+AND (sqlc.narg(closed_from)::TIMESTAMPTZ IS NULL OR close_time >= sqlc.narg(closed_from)::TIMESTAMPTZ)
+AND (sqlc.narg(closed_to)::TIMESTAMPTZ IS NULL OR close_time < sqlc.narg(closed_to)::TIMESTAMPTZ)
+AND (sqlc.narg(symbol)::TEXT IS NULL OR symbol = sqlc.narg(symbol)::TEXT OR instrument = sqlc.narg(symbol)::TEXT)
+AND (sqlc.narg(side)::TEXT IS NULL OR side = sqlc.narg(side)::TEXT)
+AND (
+    sqlc.narg(outcome)::TEXT IS NULL
+    OR (sqlc.narg(outcome)::TEXT = 'win' AND profit + commission + swap > 0)
+    OR (sqlc.narg(outcome)::TEXT = 'loss' AND profit + commission + swap < 0)
+)
+AND (
+    sqlc.narg(cursor_close_time)::TIMESTAMPTZ IS NULL
+    OR (close_time, position_id) < (sqlc.narg(cursor_close_time)::TIMESTAMPTZ, sqlc.narg(cursor_position_id)::BIGINT)
+)
+ORDER BY close_time DESC, position_id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListCompetitionMemberTrades :many
+SELECT * FROM trades
+WHERE competition_id = $1
+AND trading_account_login = $2
+ORDER BY close_time ASC, position_id ASC;
 
--- name: ListTradesByAccountLogin :many
+-- name: ListCompetitionTrades :many
 SELECT * FROM trades
-WHERE trading_account_login = $1
-ORDER BY close_time DESC;
\ No newline at end of file
+WHERE competition_id = $1
+ORDER BY trading_account_login ASC, close_time ASC, position_id ASC;
+
+-- name: ListForeignTradePositions :many
+SELECT position_id FROM trades
+WHERE trading_account_login = @trading_account_login
+AND competition_id <> @competition_id
+AND position_id = ANY(@position_ids::BIGINT[]);
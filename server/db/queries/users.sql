@@ -3,7 +3,7 @@ INSERT INTO users (
      email, username, discord_username, password_hash                   
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING id, email, username, discord_username, created_at, updated_at;
+) RETURNING id, email, username, discord_username, created_at, updated_at, email_verified_at;
 
 -- name: GetUserByID :one
 SELECT * FROM users
@@ -25,3 +25,10 @@ UPDATE users
 SET password_hash = $2,
     updated_at = now()
 WHERE id = $1;
+
+-- name: MarkEmailVerified :execrows
+UPDATE users
+SET email_verified_at = now(),
+    updated_at = now()
+WHERE id = $1
+AND email_verified_at IS NULL;
@@ -7,10 +7,44 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const disqualifyCompetitionMember = `-- name: DisqualifyCompetitionMember :execrows
+UPDATE competition_members
+SET disqualified_at = $1::TIMESTAMPTZ,
+    disqualification_reason = $2::TEXT,
+    disqualifying_position_id = $3::BIGINT
+WHERE competition_id = $4
+AND trading_account_login = $5
+AND disqualified_at IS NULL
+`
+
+type DisqualifyCompetitionMemberParams struct {
+	DisqualifiedAt      time.Time   `db:"disqualified_at" json:"disqualified_at"`
+	Reason              string      `db:"reason" json:"reason"`
+	PositionID          pgtype.Int8 `db:"position_id" json:"position_id"`
+	CompetitionID       uuid.UUID   `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+}
+
+func (q *Queries) DisqualifyCompetitionMember(ctx context.Context, arg DisqualifyCompetitionMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, disqualifyCompetitionMember,
+		arg.DisqualifiedAt,
+		arg.Reason,
+		arg.PositionID,
+		arg.CompetitionID,
+		arg.TradingAccountLogin,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getCompetitionMemberAccountSize = `-- name: GetCompetitionMemberAccountSize :one
 SELECT account_size
 FROM competition_members
@@ -38,6 +72,7 @@ SELECT
     $1, $2, 0
 FROM competitions c
 WHERE c.id = $1
+AND c.status = 'registration_open'
 AND now() < c.starts_at
 RETURNING competition_id
 `
@@ -54,20 +89,78 @@ func (q *Queries) JoinCompetitionBeforeStart(ctx context.Context, arg JoinCompet
 	return competition_id, err
 }
 
+const lockCompetitionMember = `-- name: LockCompetitionMember :exec
+SELECT trading_account_login
+FROM competition_members
+WHERE competition_id = $1
+AND trading_account_login = $2
+FOR UPDATE
+`
+
+type LockCompetitionMemberParams struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+}
+
+func (q *Queries) LockCompetitionMember(ctx context.Context, arg LockCompetitionMemberParams) error {
+	_, err := q.db.Exec(ctx, lockCompetitionMember, arg.CompetitionID, arg.TradingAccountLogin)
+	return err
+}
+
+const lockCompetitionMemberAccountSize = `-- name: LockCompetitionMemberAccountSize :one
+SELECT account_size, account_size_source, account_size_taken_at
+FROM competition_members
+WHERE competition_id = $1
+AND trading_account_login = $2
+FOR UPDATE
+`
+
+type LockCompetitionMemberAccountSizeParams struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+}
+
+type LockCompetitionMemberAccountSizeRow struct {
+	AccountSize        float64     `db:"account_size" json:"account_size"`
+	AccountSizeSource  pgtype.Text `db:"account_size_source" json:"account_size_source"`
+	AccountSizeTakenAt *time.Time  `db:"account_size_taken_at" json:"account_size_taken_at"`
+}
+
+func (q *Queries) LockCompetitionMemberAccountSize(ctx context.Context, arg LockCompetitionMemberAccountSizeParams) (LockCompetitionMemberAccountSizeRow, error) {
+	row := q.db.QueryRow(ctx, lockCompetitionMemberAccountSize, arg.CompetitionID, arg.TradingAccountLogin)
+	var i LockCompetitionMemberAccountSizeRow
+	err := row.Scan(
+		&i.AccountSize,
+		&i.AccountSizeSource,
+		&i.AccountSizeTakenAt,
+	)
+	return i, err
+}
+
 const updateCompetitionMemberAccountSize = `-- name: UpdateCompetitionMemberAccountSize :exec
 UPDATE competition_members
-SET account_size = $3
+SET account_size = $3,
+    account_size_source = $4,
+    account_size_taken_at = $5
 WHERE competition_id = $1
 AND trading_account_login = $2
 `
 
 type UpdateCompetitionMemberAccountSizeParams struct {
-	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
-	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
-	AccountSize         float64   `db:"account_size" json:"account_size"`
+	CompetitionID       uuid.UUID   `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+	AccountSize         float64     `db:"account_size" json:"account_size"`
+	AccountSizeSource   pgtype.Text `db:"account_size_source" json:"account_size_source"`
+	AccountSizeTakenAt  *time.Time  `db:"account_size_taken_at" json:"account_size_taken_at"`
 }
 
 func (q *Queries) UpdateCompetitionMemberAccountSize(ctx context.Context, arg UpdateCompetitionMemberAccountSizeParams) error {
-	_, err := q.db.Exec(ctx, updateCompetitionMemberAccountSize, arg.CompetitionID, arg.TradingAccountLogin, arg.AccountSize)
+	_, err := q.db.Exec(ctx, updateCompetitionMemberAccountSize,
+		arg.CompetitionID,
+		arg.TradingAccountLogin,
+		arg.AccountSize,
+		arg.AccountSizeSource,
+		arg.AccountSizeTakenAt,
+	)
 	return err
 }
@@ -14,17 +14,18 @@ import (
 
 const createCompetition = `-- name: CreateCompetition :one
 INSERT INTO competitions (
-    id, name, starts_at, ends_at
+    id, name, starts_at, ends_at, window_mode
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING id, name, starts_at, ends_at, created_at
+    $1, $2, $3, $4, $5
+) RETURNING id, name, starts_at, ends_at, created_at, status, status_changed_at, finalized_at, finalized_by, results_hash, window_mode
 `
 
 type CreateCompetitionParams struct {
-	ID       uuid.UUID `db:"id" json:"id"`
-	Name     string    `db:"name" json:"name"`
-	StartsAt time.Time `db:"starts_at" json:"starts_at"`
-	EndsAt   time.Time `db:"ends_at" json:"ends_at"`
+	ID         uuid.UUID `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name"`
+	StartsAt   time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt     time.Time `db:"ends_at" json:"ends_at"`
+	WindowMode string    `db:"window_mode" json:"window_mode"`
 }
 
 func (q *Queries) CreateCompetition(ctx context.Context, arg CreateCompetitionParams) (Competition, error) {
@@ -33,6 +34,7 @@ func (q *Queries) CreateCompetition(ctx context.Context, arg CreateCompetitionPa
 		arg.Name,
 		arg.StartsAt,
 		arg.EndsAt,
+		arg.WindowMode,
 	)
 	var i Competition
 	err := row.Scan(
@@ -41,12 +43,62 @@ func (q *Queries) CreateCompetition(ctx context.Context, arg CreateCompetitionPa
 		&i.StartsAt,
 		&i.EndsAt,
 		&i.CreatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.FinalizedAt,
+		&i.FinalizedBy,
+		&i.ResultsHash,
+		&i.WindowMode,
 	)
 	return i, err
 }
 
+const createCompetitionEvent = `-- name: CreateCompetitionEvent :exec
+INSERT INTO competition_events (
+    competition_id, from_status, to_status
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type CreateCompetitionEventParams struct {
+	CompetitionID uuid.UUID `db:"competition_id" json:"competition_id"`
+	FromStatus    string    `db:"from_status" json:"from_status"`
+	ToStatus      string    `db:"to_status" json:"to_status"`
+}
+
+func (q *Queries) CreateCompetitionEvent(ctx context.Context, arg CreateCompetitionEventParams) error {
+	_, err := q.db.Exec(ctx, createCompetitionEvent, arg.CompetitionID, arg.FromStatus, arg.ToStatus)
+	return err
+}
+
+const finalizeCompetition = `-- name: FinalizeCompetition :execrows
+UPDATE competitions
+SET status = 'finalized',
+    status_changed_at = now(),
+    finalized_at = now(),
+    finalized_by = $1,
+    results_hash = $2::TEXT
+WHERE id = $3
+AND status = 'settling'
+`
+
+type FinalizeCompetitionParams struct {
+	FinalizedBy *uuid.UUID `db:"finalized_by" json:"finalized_by"`
+	ResultsHash string     `db:"results_hash" json:"results_hash"`
+	ID          uuid.UUID  `db:"id" json:"id"`
+}
+
+func (q *Queries) FinalizeCompetition(ctx context.Context, arg FinalizeCompetitionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, finalizeCompetition, arg.FinalizedBy, arg.ResultsHash, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getCompetitionByID = `-- name: GetCompetitionByID :one
-SELECT id, name, starts_at, ends_at, created_at FROM competitions
+SELECT id, name, starts_at, ends_at, created_at, status, status_changed_at, finalized_at, finalized_by, results_hash, window_mode FROM competitions
 WHERE id = $1
 `
 
@@ -59,23 +111,16 @@ func (q *Queries) GetCompetitionByID(ctx context.Context, id uuid.UUID) (Competi
 		&i.StartsAt,
 		&i.EndsAt,
 		&i.CreatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.FinalizedAt,
+		&i.FinalizedBy,
+		&i.ResultsHash,
+		&i.WindowMode,
 	)
 	return i, err
 }
 
-const getCompetitionStartTime = `-- name: GetCompetitionStartTime :one
-SELECT starts_at
-FROM competitions
-WHERE id = $1
-`
-
-func (q *Queries) GetCompetitionStartTime(ctx context.Context, id uuid.UUID) (time.Time, error) {
-	row := q.db.QueryRow(ctx, getCompetitionStartTime, id)
-	var starts_at time.Time
-	err := row.Scan(&starts_at)
-	return starts_at, err
-}
-
 const getCompetitionUserState = `-- name: GetCompetitionUserState :one
 SELECT
     EXISTS (
@@ -111,9 +156,9 @@ func (q *Queries) GetCompetitionUserState(ctx context.Context, arg GetCompetitio
 }
 
 const getCurrentCompetition = `-- name: GetCurrentCompetition :one
-SELECT id, name, starts_at, ends_at, created_at
+SELECT id, name, starts_at, ends_at, created_at, status, status_changed_at, finalized_at, finalized_by, results_hash, window_mode
 FROM competitions
-WHERE now() < ends_at
+WHERE status IN ('registration_open', 'running', 'settling')
 ORDER BY starts_at ASC
 LIMIT 1
 `
@@ -127,12 +172,18 @@ func (q *Queries) GetCurrentCompetition(ctx context.Context) (Competition, error
 		&i.StartsAt,
 		&i.EndsAt,
 		&i.CreatedAt,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.FinalizedAt,
+		&i.FinalizedBy,
+		&i.ResultsHash,
+		&i.WindowMode,
 	)
 	return i, err
 }
 
 const listCompetitions = `-- name: ListCompetitions :many
-SELECT id, name, starts_at, ends_at, created_at FROM competitions
+SELECT id, name, starts_at, ends_at, created_at, status, status_changed_at, finalized_at, finalized_by, results_hash, window_mode FROM competitions
 ORDER BY starts_at DESC
 `
 
@@ -151,6 +202,50 @@ func (q *Queries) ListCompetitions(ctx context.Context) ([]Competition, error) {
 			&i.StartsAt,
 			&i.EndsAt,
 			&i.CreatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.FinalizedAt,
+			&i.FinalizedBy,
+			&i.ResultsHash,
+			&i.WindowMode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompetitionsByStatus = `-- name: ListCompetitionsByStatus :many
+SELECT id, name, starts_at, ends_at, created_at, status, status_changed_at, finalized_at, finalized_by, results_hash, window_mode FROM competitions
+WHERE status = $1
+ORDER BY starts_at ASC
+`
+
+func (q *Queries) ListCompetitionsByStatus(ctx context.Context, status string) ([]Competition, error) {
+	rows, err := q.db.Query(ctx, listCompetitionsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Competition
+	for rows.Next() {
+		var i Competition
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.FinalizedAt,
+			&i.FinalizedBy,
+			&i.ResultsHash,
+			&i.WindowMode,
 		); err != nil {
 			return nil, err
 		}
@@ -161,3 +256,45 @@ func (q *Queries) ListCompetitions(ctx context.Context) ([]Competition, error) {
 	}
 	return items, nil
 }
+
+const updateCompetitionStatus = `-- name: UpdateCompetitionStatus :execrows
+UPDATE competitions
+SET status = $1,
+    status_changed_at = now()
+WHERE id = $2
+AND status = $3
+`
+
+type UpdateCompetitionStatusParams struct {
+	ToStatus   string    `db:"to_status" json:"to_status"`
+	ID         uuid.UUID `db:"id" json:"id"`
+	FromStatus string    `db:"from_status" json:"from_status"`
+}
+
+func (q *Queries) UpdateCompetitionStatus(ctx context.Context, arg UpdateCompetitionStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateCompetitionStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateCompetitionWindowMode = `-- name: UpdateCompetitionWindowMode :execrows
+UPDATE competitions
+SET window_mode = $1
+WHERE id = $2
+AND status IN ('draft', 'registration_open')
+`
+
+type UpdateCompetitionWindowModeParams struct {
+	WindowMode string    `db:"window_mode" json:"window_mode"`
+	ID         uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateCompetitionWindowMode(ctx context.Context, arg UpdateCompetitionWindowModeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateCompetitionWindowMode, arg.WindowMode, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -8,63 +8,366 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Competition struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	StartsAt  time.Time `db:"starts_at" json:"starts_at"`
-	EndsAt    time.Time `db:"ends_at" json:"ends_at"`
+type AccountSizeAudit struct {
+	ID                  int64      `db:"id" json:"id"`
+	CompetitionID       uuid.UUID  `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64      `db:"trading_account_login" json:"trading_account_login"`
+	OldSize             float64    `db:"old_size" json:"old_size"`
+	NewSize             float64    `db:"new_size" json:"new_size"`
+	Source              string     `db:"source" json:"source"`
+	ChangedBy           *uuid.UUID `db:"changed_by" json:"changed_by"`
+	ApiKeyID            *uuid.UUID `db:"api_key_id" json:"api_key_id"`
+	Reason              string     `db:"reason" json:"reason"`
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+}
+
+type AccountSnapshot struct {
+	ID                  int64     `db:"id" json:"id"`
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+	Balance             float64   `db:"balance" json:"balance"`
+	Equity              float64   `db:"equity" json:"equity"`
+	Currency            string    `db:"currency" json:"currency"`
+	Leverage            int32     `db:"leverage" json:"leverage"`
+	TakenAt             time.Time `db:"taken_at" json:"taken_at"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+type ApiKey struct {
+	ID                  uuid.UUID  `db:"id" json:"id"`
+	Name                string     `db:"name" json:"name"`
+	SigningKeyEncrypted string     `db:"signing_key_encrypted" json:"signing_key_encrypted"`
+	Scopes              []string   `db:"scopes" json:"scopes"`
+	CreatedBy           *uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+	LastUsedAt          *time.Time `db:"last_used_at" json:"last_used_at"`
+	RevokedAt           *time.Time `db:"revoked_at" json:"revoked_at"`
+}
+
+type ApiKeyNonce struct {
+	ApiKeyID  uuid.UUID `db:"api_key_id" json:"api_key_id"`
+	Nonce     string    `db:"nonce" json:"nonce"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
+type BalanceOperation struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+	OperationID         int64     `db:"operation_id" json:"operation_id"`
+	Type                string    `db:"type" json:"type"`
+	Amount              float64   `db:"amount" json:"amount"`
+	OccurredAt          time.Time `db:"occurred_at" json:"occurred_at"`
+	Comment             string    `db:"comment" json:"comment"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+type BalanceReview struct {
+	ID                  int64      `db:"id" json:"id"`
+	CompetitionID       uuid.UUID  `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64      `db:"trading_account_login" json:"trading_account_login"`
+	OperationID         int64      `db:"operation_id" json:"operation_id"`
+	Reason              string     `db:"reason" json:"reason"`
+	Status              string     `db:"status" json:"status"`
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+	ReviewedBy          *uuid.UUID `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt          *time.Time `db:"reviewed_at" json:"reviewed_at"`
+}
+
+type CheatCase struct {
+	ID            int64      `db:"id" json:"id"`
+	CompetitionID uuid.UUID  `db:"competition_id" json:"competition_id"`
+	LoginA        int64      `db:"login_a" json:"login_a"`
+	LoginB        int64      `db:"login_b" json:"login_b"`
+	Kind          string     `db:"kind" json:"kind"`
+	Score         float64    `db:"score" json:"score"`
+	MatchedTrades int32      `db:"matched_trades" json:"matched_trades"`
+	Evidence      []byte     `db:"evidence" json:"evidence"`
+	Status        string     `db:"status" json:"status"`
+	DetectedAt    time.Time  `db:"detected_at" json:"detected_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	ResolvedBy    *uuid.UUID `db:"resolved_by" json:"resolved_by"`
+	ResolvedAt    *time.Time `db:"resolved_at" json:"resolved_at"`
+}
+
+type Competition struct {
+	ID              uuid.UUID   `db:"id" json:"id"`
+	Name            string      `db:"name" json:"name"`
+	StartsAt        time.Time   `db:"starts_at" json:"starts_at"`
+	EndsAt          time.Time   `db:"ends_at" json:"ends_at"`
+	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
+	Status          string      `db:"status" json:"status"`
+	StatusChangedAt time.Time   `db:"status_changed_at" json:"status_changed_at"`
+	FinalizedAt     *time.Time  `db:"finalized_at" json:"finalized_at"`
+	FinalizedBy     *uuid.UUID  `db:"finalized_by" json:"finalized_by"`
+	ResultsHash     pgtype.Text `db:"results_hash" json:"results_hash"`
+	WindowMode      string      `db:"window_mode" json:"window_mode"`
+}
+
 type CompetitionAccountRequest struct {
 	UserID        uuid.UUID `db:"user_id" json:"user_id"`
 	CompetitionID uuid.UUID `db:"competition_id" json:"competition_id"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 }
 
+type CompetitionEvent struct {
+	ID            int64     `db:"id" json:"id"`
+	CompetitionID uuid.UUID `db:"competition_id" json:"competition_id"`
+	FromStatus    string    `db:"from_status" json:"from_status"`
+	ToStatus      string    `db:"to_status" json:"to_status"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
 type CompetitionMember struct {
+	CompetitionID           uuid.UUID   `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin     int64       `db:"trading_account_login" json:"trading_account_login"`
+	AccountSize             float64     `db:"account_size" json:"account_size"`
+	DisqualifiedAt          *time.Time  `db:"disqualified_at" json:"disqualified_at"`
+	DisqualificationReason  pgtype.Text `db:"disqualification_reason" json:"disqualification_reason"`
+	DisqualifyingPositionID pgtype.Int8 `db:"disqualifying_position_id" json:"disqualifying_position_id"`
+	AccountSizeSource       pgtype.Text `db:"account_size_source" json:"account_size_source"`
+	AccountSizeTakenAt      *time.Time  `db:"account_size_taken_at" json:"account_size_taken_at"`
+}
+
+type CompetitionMemberDailyPnl struct {
+	CompetitionID       uuid.UUID   `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+	Day                 pgtype.Date `db:"day" json:"day"`
+	NetProfit           float64     `db:"net_profit" json:"net_profit"`
+}
+
+type CompetitionMemberFloating struct {
 	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
 	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
-	AccountSize         float64   `db:"account_size" json:"account_size"`
+	FloatingProfit      float64   `db:"floating_profit" json:"floating_profit"`
+	OpenPositionCount   int32     `db:"open_position_count" json:"open_position_count"`
+	SnapshotAt          time.Time `db:"snapshot_at" json:"snapshot_at"`
 }
 
-type RefreshToken struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	Token     string    `db:"token" json:"token"`
-	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+type CompetitionMemberStat struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+	RealizedProfit      float64   `db:"realized_profit" json:"realized_profit"`
+	Commission          float64   `db:"commission" json:"commission"`
+	Swap                float64   `db:"swap" json:"swap"`
+	TradeCount          int32     `db:"trade_count" json:"trade_count"`
+	Wins                int32     `db:"wins" json:"wins"`
+	Losses              int32     `db:"losses" json:"losses"`
+	GrossProfit         float64   `db:"gross_profit" json:"gross_profit"`
+	GrossLoss           float64   `db:"gross_loss" json:"gross_loss"`
+	MaxDrawdownPercent  float64   `db:"max_drawdown_percent" json:"max_drawdown_percent"`
+	LastTradeAt         time.Time `db:"last_trade_at" json:"last_trade_at"`
+	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type CompetitionRanking struct {
+	CompetitionID uuid.UUID `db:"competition_id" json:"competition_id"`
+	Mode          string    `db:"mode" json:"mode"`
+	Weights       []byte    `db:"weights" json:"weights"`
+	TieBreaks     []string  `db:"tie_breaks" json:"tie_breaks"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	EquityMode    string    `db:"equity_mode" json:"equity_mode"`
+}
+
+type CompetitionResult struct {
+	CompetitionID          uuid.UUID  `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin    int64      `db:"trading_account_login" json:"trading_account_login"`
+	Rank                   int32      `db:"rank" json:"rank"`
+	Username               string     `db:"username" json:"username"`
+	AccountSize            float64    `db:"account_size" json:"account_size"`
+	Profit                 float64    `db:"profit" json:"profit"`
+	Equity                 float64    `db:"equity" json:"equity"`
+	GainPercent            float64    `db:"gain_percent" json:"gain_percent"`
+	FloatingProfit         float64    `db:"floating_profit" json:"floating_profit"`
+	NetDeposits            float64    `db:"net_deposits" json:"net_deposits"`
+	TradeCount             int32      `db:"trade_count" json:"trade_count"`
+	MaxDrawdownPercent     float64    `db:"max_drawdown_percent" json:"max_drawdown_percent"`
+	Sharpe                 float64    `db:"sharpe" json:"sharpe"`
+	Sortino                float64    `db:"sortino" json:"sortino"`
+	ProfitFactor           float64    `db:"profit_factor" json:"profit_factor"`
+	WinRate                float64    `db:"win_rate" json:"win_rate"`
+	AverageR               float64    `db:"average_r" json:"average_r"`
+	Score                  *float64   `db:"score" json:"score"`
+	DisqualifiedAt         *time.Time `db:"disqualified_at" json:"disqualified_at"`
+	DisqualificationReason string     `db:"disqualification_reason" json:"disqualification_reason"`
+}
+
+type CompetitionRule struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	MaxDrawdownPercent  *float64  `db:"max_drawdown_percent" json:"max_drawdown_percent"`
+	MaxDailyLossPercent *float64  `db:"max_daily_loss_percent" json:"max_daily_loss_percent"`
+	MaxLotSize          *float64  `db:"max_lot_size" json:"max_lot_size"`
+	AllowedSymbols      []string  `db:"allowed_symbols" json:"allowed_symbols"`
+	MinTradingDays      int32     `db:"min_trading_days" json:"min_trading_days"`
+	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	UserID    uuid.UUID  `db:"user_id" json:"user_id"`
+	TokenHash string     `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+}
+
+type Instrument struct {
+	Symbol        string  `db:"symbol" json:"symbol"`
+	AssetClass    string  `db:"asset_class" json:"asset_class"`
+	ContractSize  float64 `db:"contract_size" json:"contract_size"`
+	PipSize       float64 `db:"pip_size" json:"pip_size"`
+	Digits        int32   `db:"digits" json:"digits"`
+	QuoteCurrency string  `db:"quote_currency" json:"quote_currency"`
+}
+
+type InstrumentAlias struct {
+	Broker    string    `db:"broker" json:"broker"`
+	Alias     string    `db:"alias" json:"alias"`
+	Symbol    string    `db:"symbol" json:"symbol"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	Revoked   bool      `db:"revoked" json:"revoked"`
 }
 
-type TrackedTrade struct {
-	PositionID int64      `db:"position_id" json:"position_id"`
-	Symbol     string     `db:"symbol" json:"symbol"`
-	Side       string     `db:"side" json:"side"`
-	OpenPrice  float64    `db:"open_price" json:"open_price"`
-	Volume     float64    `db:"volume" json:"volume"`
-	StopLoss   *float64   `db:"stop_loss" json:"stop_loss"`
-	OpenedAt   time.Time  `db:"opened_at" json:"opened_at"`
-	ClosedAt   *time.Time `db:"closed_at" json:"closed_at"`
+type LeaderboardSnapshot struct {
+	ID            int64     `db:"id" json:"id"`
+	CompetitionID uuid.UUID `db:"competition_id" json:"competition_id"`
+	TakenAt       time.Time `db:"taken_at" json:"taken_at"`
 }
 
-type Trade struct {
-	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+type LeaderboardSnapshotEntry struct {
+	SnapshotID          int64   `db:"snapshot_id" json:"snapshot_id"`
+	TradingAccountLogin int64   `db:"trading_account_login" json:"trading_account_login"`
+	Rank                int32   `db:"rank" json:"rank"`
+	Profit              float64 `db:"profit" json:"profit"`
+	Equity              float64 `db:"equity" json:"equity"`
+	GainPercent         float64 `db:"gain_percent" json:"gain_percent"`
+}
+
+type OpenPosition struct {
 	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
 	PositionID          int64     `db:"position_id" json:"position_id"`
 	Symbol              string    `db:"symbol" json:"symbol"`
 	Side                string    `db:"side" json:"side"`
 	Volume              float64   `db:"volume" json:"volume"`
 	OpenTime            time.Time `db:"open_time" json:"open_time"`
-	CloseTime           time.Time `db:"close_time" json:"close_time"`
 	OpenPrice           float64   `db:"open_price" json:"open_price"`
-	ClosePrice          float64   `db:"close_price" json:"close_price"`
+	CurrentPrice        float64   `db:"current_price" json:"current_price"`
 	Profit              float64   `db:"profit" json:"profit"`
 	Commission          float64   `db:"commission" json:"commission"`
 	Swap                float64   `db:"swap" json:"swap"`
-	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	UserID    uuid.UUID  `db:"user_id" json:"user_id"`
+	TokenHash string     `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+}
+
+type RateLimit struct {
+	Key           string     `db:"key" json:"key"`
+	Failures      int32      `db:"failures" json:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at" json:"last_failure_at"`
+	BlockedUntil  *time.Time `db:"blocked_until" json:"blocked_until"`
+}
+
+type RateLimitLockout struct {
+	ID          int64      `db:"id" json:"id"`
+	Scope       string     `db:"scope" json:"scope"`
+	Key         string     `db:"key" json:"key"`
+	Failures    int32      `db:"failures" json:"failures"`
+	LockedUntil time.Time  `db:"locked_until" json:"locked_until"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	ReleasedBy  *uuid.UUID `db:"released_by" json:"released_by"`
+	ReleasedAt  *time.Time `db:"released_at" json:"released_at"`
+}
+
+type RefreshToken struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	UserID     uuid.UUID  `db:"user_id" json:"user_id"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	Revoked    bool       `db:"revoked" json:"revoked"`
+	TokenHash  string     `db:"token_hash" json:"token_hash"`
+	FamilyID   uuid.UUID  `db:"family_id" json:"family_id"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at"`
+	ReplacedBy *uuid.UUID `db:"replaced_by" json:"replaced_by"`
+	Mfa        bool       `db:"mfa" json:"mfa"`
+}
+
+type TotpRecoveryCode struct {
+	ID       uuid.UUID  `db:"id" json:"id"`
+	UserID   uuid.UUID  `db:"user_id" json:"user_id"`
+	CodeHash string     `db:"code_hash" json:"code_hash"`
+	UsedAt   *time.Time `db:"used_at" json:"used_at"`
+}
+
+type TrackedTrade struct {
+	PositionID int64       `db:"position_id" json:"position_id"`
+	Symbol     string      `db:"symbol" json:"symbol"`
+	Side       string      `db:"side" json:"side"`
+	OpenPrice  float64     `db:"open_price" json:"open_price"`
+	Volume     float64     `db:"volume" json:"volume"`
+	StopLoss   *float64    `db:"stop_loss" json:"stop_loss"`
+	OpenedAt   time.Time   `db:"opened_at" json:"opened_at"`
+	ClosedAt   *time.Time  `db:"closed_at" json:"closed_at"`
+	Instrument pgtype.Text `db:"instrument" json:"instrument"`
+}
+
+type Trade struct {
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+	CompetitionID       uuid.UUID   `db:"competition_id" json:"competition_id"`
+	PositionID          int64       `db:"position_id" json:"position_id"`
+	Symbol              string      `db:"symbol" json:"symbol"`
+	Side                string      `db:"side" json:"side"`
+	Volume              float64     `db:"volume" json:"volume"`
+	OpenTime            time.Time   `db:"open_time" json:"open_time"`
+	CloseTime           time.Time   `db:"close_time" json:"close_time"`
+	OpenPrice           float64     `db:"open_price" json:"open_price"`
+	ClosePrice          float64     `db:"close_price" json:"close_price"`
+	Profit              float64     `db:"profit" json:"profit"`
+	Commission          float64     `db:"commission" json:"commission"`
+	Swap                float64     `db:"swap" json:"swap"`
+	CreatedAt           time.Time   `db:"created_at" json:"created_at"`
+	Version             int32       `db:"version" json:"version"`
+	Instrument          pgtype.Text `db:"instrument" json:"instrument"`
+}
+
+type TradeQuarantine struct {
+	ID                  int64       `db:"id" json:"id"`
+	CompetitionID       uuid.UUID   `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+	PositionID          int64       `db:"position_id" json:"position_id"`
+	Symbol              string      `db:"symbol" json:"symbol"`
+	Side                string      `db:"side" json:"side"`
+	Volume              float64     `db:"volume" json:"volume"`
+	OpenTime            time.Time   `db:"open_time" json:"open_time"`
+	CloseTime           time.Time   `db:"close_time" json:"close_time"`
+	OpenPrice           float64     `db:"open_price" json:"open_price"`
+	ClosePrice          float64     `db:"close_price" json:"close_price"`
+	Profit              float64     `db:"profit" json:"profit"`
+	Commission          float64     `db:"commission" json:"commission"`
+	Swap                float64     `db:"swap" json:"swap"`
+	Reason              string      `db:"reason" json:"reason"`
+	Status              string      `db:"status" json:"status"`
+	QuarantinedAt       time.Time   `db:"quarantined_at" json:"quarantined_at"`
+	ReviewedBy          *uuid.UUID  `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt          *time.Time  `db:"reviewed_at" json:"reviewed_at"`
+	Instrument          pgtype.Text `db:"instrument" json:"instrument"`
+}
+
+type TradeRevision struct {
+	ID                  int64     `db:"id" json:"id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+	PositionID          int64     `db:"position_id" json:"position_id"`
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	Version             int32     `db:"version" json:"version"`
+	Changes             []byte    `db:"changes" json:"changes"`
+	RevisedAt           time.Time `db:"revised_at" json:"revised_at"`
 }
 
 type TradingAccount struct {
@@ -76,12 +379,21 @@ type TradingAccount struct {
 }
 
 type User struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	Email           string    `db:"email" json:"email"`
-	Username        string    `db:"username" json:"username"`
-	DiscordUsername string    `db:"discord_username" json:"discord_username"`
-	PasswordHash    string    `db:"password_hash" json:"password_hash"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	Role            string    `db:"role" json:"role"`
+	ID              uuid.UUID  `db:"id" json:"id"`
+	Email           string     `db:"email" json:"email"`
+	Username        string     `db:"username" json:"username"`
+	DiscordUsername string     `db:"discord_username" json:"discord_username"`
+	PasswordHash    string     `db:"password_hash" json:"password_hash"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+	Role            string     `db:"role" json:"role"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"email_verified_at"`
+}
+
+type UserTotp struct {
+	UserID       uuid.UUID  `db:"user_id" json:"user_id"`
+	Secret       string     `db:"secret" json:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at" json:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
 }
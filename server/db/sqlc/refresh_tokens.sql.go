@@ -13,73 +13,147 @@ import (
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (token, user_id, expires_at)
-VALUES ($1, $2, $3)
-    RETURNING token, user_id, expires_at, revoked, created_at
+INSERT INTO refresh_tokens (token_hash, user_id, family_id, expires_at, mfa)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, expires_at, created_at, revoked, token_hash, family_id, revoked_at, replaced_by, mfa
 `
 
 type CreateRefreshTokenParams struct {
-	Token     string    `db:"token" json:"token"`
+	TokenHash string    `db:"token_hash" json:"token_hash"`
 	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	FamilyID  uuid.UUID `db:"family_id" json:"family_id"`
 	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	Mfa       bool      `db:"mfa" json:"mfa"`
 }
 
-type CreateRefreshTokenRow struct {
-	Token     string    `db:"token" json:"token"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
-	Revoked   bool      `db:"revoked" json:"revoked"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-}
-
-func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (CreateRefreshTokenRow, error) {
-	row := q.db.QueryRow(ctx, createRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt)
-	var i CreateRefreshTokenRow
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, createRefreshToken,
+		arg.TokenHash,
+		arg.UserID,
+		arg.FamilyID,
+		arg.ExpiresAt,
+		arg.Mfa,
+	)
+	var i RefreshToken
 	err := row.Scan(
-		&i.Token,
+		&i.ID,
 		&i.UserID,
 		&i.ExpiresAt,
-		&i.Revoked,
 		&i.CreatedAt,
+		&i.Revoked,
+		&i.TokenHash,
+		&i.FamilyID,
+		&i.RevokedAt,
+		&i.ReplacedBy,
+		&i.Mfa,
 	)
 	return i, err
 }
 
-const getRefreshToken = `-- name: GetRefreshToken :one
-SELECT token, user_id, expires_at, revoked, created_at
-FROM refresh_tokens
-WHERE token = $1
-    LIMIT 1
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT id, user_id, expires_at, created_at, revoked, token_hash, family_id, revoked_at, replaced_by, mfa FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE
 `
 
-type GetRefreshTokenRow struct {
-	Token     string    `db:"token" json:"token"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
-	Revoked   bool      `db:"revoked" json:"revoked"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-}
-
-func (q *Queries) GetRefreshToken(ctx context.Context, token string) (GetRefreshTokenRow, error) {
-	row := q.db.QueryRow(ctx, getRefreshToken, token)
-	var i GetRefreshTokenRow
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenForUpdate, tokenHash)
+	var i RefreshToken
 	err := row.Scan(
-		&i.Token,
+		&i.ID,
 		&i.UserID,
 		&i.ExpiresAt,
-		&i.Revoked,
 		&i.CreatedAt,
+		&i.Revoked,
+		&i.TokenHash,
+		&i.FamilyID,
+		&i.RevokedAt,
+		&i.ReplacedBy,
+		&i.Mfa,
 	)
 	return i, err
 }
 
-const revokeRefreshToken = `-- name: RevokeRefreshToken :exec
+const hasLiveRefreshToken = `-- name: HasLiveRefreshToken :one
+SELECT EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE family_id = $1
+    AND NOT revoked
+    AND expires_at > now()
+) AS live
+`
+
+func (q *Queries) HasLiveRefreshToken(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, hasLiveRefreshToken, familyID)
+	var live bool
+	err := row.Scan(&live)
+	return live, err
+}
+
+const replaceRefreshToken = `-- name: ReplaceRefreshToken :exec
 UPDATE refresh_tokens
-SET revoked = TRUE
-WHERE token = $1
+SET revoked = TRUE,
+    revoked_at = now(),
+    replaced_by = $1
+WHERE id = $2
 `
 
-func (q *Queries) RevokeRefreshToken(ctx context.Context, token string) error {
-	_, err := q.db.Exec(ctx, revokeRefreshToken, token)
+type ReplaceRefreshTokenParams struct {
+	ReplacedBy *uuid.UUID `db:"replaced_by" json:"replaced_by"`
+	ID         uuid.UUID  `db:"id" json:"id"`
+}
+
+func (q *Queries) ReplaceRefreshToken(ctx context.Context, arg ReplaceRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, replaceRefreshToken, arg.ReplacedBy, arg.ID)
 	return err
 }
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE family_id = $1
+AND NOT revoked
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeRefreshTokenFamilyByHash = `-- name: RevokeRefreshTokenFamilyByHash :execrows
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE family_id = (
+    SELECT family_id FROM refresh_tokens WHERE token_hash = $1
+)
+AND NOT revoked
+`
+
+func (q *Queries) RevokeRefreshTokenFamilyByHash(ctx context.Context, tokenHash string) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeRefreshTokenFamilyByHash, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :execrows
+UPDATE refresh_tokens
+SET revoked = TRUE,
+    revoked_at = now()
+WHERE user_id = $1
+AND NOT revoked
+`
+
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserRefreshTokens, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -8,6 +8,8 @@ package sqlc
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const closeTrackedTrade = `-- name: CloseTrackedTrade :execrows
@@ -37,21 +39,23 @@ INSERT INTO tracked_trades (
     open_price,
     stop_loss,
     volume,
-    opened_at
+    opened_at,
+    instrument
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 ON CONFLICT (position_id) DO NOTHING
 `
 
 type CreateTrackedTradeParams struct {
-	PositionID int64     `db:"position_id" json:"position_id"`
-	Symbol     string    `db:"symbol" json:"symbol"`
-	Side       string    `db:"side" json:"side"`
-	OpenPrice  float64   `db:"open_price" json:"open_price"`
-	StopLoss   *float64  `db:"stop_loss" json:"stop_loss"`
-	Volume     float64   `db:"volume" json:"volume"`
-	OpenedAt   time.Time `db:"opened_at" json:"opened_at"`
+	PositionID int64       `db:"position_id" json:"position_id"`
+	Symbol     string      `db:"symbol" json:"symbol"`
+	Side       string      `db:"side" json:"side"`
+	OpenPrice  float64     `db:"open_price" json:"open_price"`
+	StopLoss   *float64    `db:"stop_loss" json:"stop_loss"`
+	Volume     float64     `db:"volume" json:"volume"`
+	OpenedAt   time.Time   `db:"opened_at" json:"opened_at"`
+	Instrument pgtype.Text `db:"instrument" json:"instrument"`
 }
 
 func (q *Queries) CreateTrackedTrade(ctx context.Context, arg CreateTrackedTradeParams) error {
@@ -63,6 +67,7 @@ func (q *Queries) CreateTrackedTrade(ctx context.Context, arg CreateTrackedTrade
 		arg.StopLoss,
 		arg.Volume,
 		arg.OpenedAt,
+		arg.Instrument,
 	)
 	return err
 }
@@ -76,20 +81,22 @@ SELECT
     stop_loss,
     volume,
     opened_at,
-    closed_at
+    closed_at,
+    instrument
 FROM tracked_trades
 ORDER BY opened_at ASC
 `
 
 type ListTrackedTradesRow struct {
-	PositionID int64      `db:"position_id" json:"position_id"`
-	Symbol     string     `db:"symbol" json:"symbol"`
-	Side       string     `db:"side" json:"side"`
-	OpenPrice  float64    `db:"open_price" json:"open_price"`
-	StopLoss   *float64   `db:"stop_loss" json:"stop_loss"`
-	Volume     float64    `db:"volume" json:"volume"`
-	OpenedAt   time.Time  `db:"opened_at" json:"opened_at"`
-	ClosedAt   *time.Time `db:"closed_at" json:"closed_at"`
+	PositionID int64       `db:"position_id" json:"position_id"`
+	Symbol     string      `db:"symbol" json:"symbol"`
+	Side       string      `db:"side" json:"side"`
+	OpenPrice  float64     `db:"open_price" json:"open_price"`
+	StopLoss   *float64    `db:"stop_loss" json:"stop_loss"`
+	Volume     float64     `db:"volume" json:"volume"`
+	OpenedAt   time.Time   `db:"opened_at" json:"opened_at"`
+	ClosedAt   *time.Time  `db:"closed_at" json:"closed_at"`
+	Instrument pgtype.Text `db:"instrument" json:"instrument"`
 }
 
 func (q *Queries) ListTrackedTrades(ctx context.Context) ([]ListTrackedTradesRow, error) {
@@ -110,6 +117,7 @@ func (q *Queries) ListTrackedTrades(ctx context.Context) ([]ListTrackedTradesRow
 			&i.Volume,
 			&i.OpenedAt,
 			&i.ClosedAt,
+			&i.Instrument,
 		); err != nil {
 			return nil, err
 		}
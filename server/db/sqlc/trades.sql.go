@@ -10,60 +10,181 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const insertTrade = `-- name: InsertTrade :exec
-INSERT INTO trades (
-    trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-) ON CONFLICT (trading_account_login, position_id)
-DO NOTHING
+const listCompetitionMemberTrades = `-- name: ListCompetitionMemberTrades :many
+SELECT trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap, created_at, version, instrument FROM trades
+WHERE competition_id = $1
+AND trading_account_login = $2
+ORDER BY close_time ASC, position_id ASC
 `
 
-type InsertTradeParams struct {
+type ListCompetitionMemberTradesParams struct {
+	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
+	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
+}
+
+func (q *Queries) ListCompetitionMemberTrades(ctx context.Context, arg ListCompetitionMemberTradesParams) ([]Trade, error) {
+	rows, err := q.db.Query(ctx, listCompetitionMemberTrades, arg.CompetitionID, arg.TradingAccountLogin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradingAccountLogin,
+			&i.CompetitionID,
+			&i.PositionID,
+			&i.Symbol,
+			&i.Side,
+			&i.Volume,
+			&i.OpenTime,
+			&i.CloseTime,
+			&i.OpenPrice,
+			&i.ClosePrice,
+			&i.Profit,
+			&i.Commission,
+			&i.Swap,
+			&i.CreatedAt,
+			&i.Version,
+			&i.Instrument,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompetitionTrades = `-- name: ListCompetitionTrades :many
+SELECT trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap, created_at, version, instrument FROM trades
+WHERE competition_id = $1
+ORDER BY trading_account_login ASC, close_time ASC, position_id ASC
+`
+
+func (q *Queries) ListCompetitionTrades(ctx context.Context, competitionID uuid.UUID) ([]Trade, error) {
+	rows, err := q.db.Query(ctx, listCompetitionTrades, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradingAccountLogin,
+			&i.CompetitionID,
+			&i.PositionID,
+			&i.Symbol,
+			&i.Side,
+			&i.Volume,
+			&i.OpenTime,
+			&i.CloseTime,
+			&i.OpenPrice,
+			&i.ClosePrice,
+			&i.Profit,
+			&i.Commission,
+			&i.Swap,
+			&i.CreatedAt,
+			&i.Version,
+			&i.Instrument,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listForeignTradePositions = `-- name: ListForeignTradePositions :many
+SELECT position_id FROM trades
+WHERE trading_account_login = $1
+AND competition_id <> $2
+AND position_id = ANY($3::BIGINT[])
+`
+
+type ListForeignTradePositionsParams struct {
 	TradingAccountLogin int64     `db:"trading_account_login" json:"trading_account_login"`
 	CompetitionID       uuid.UUID `db:"competition_id" json:"competition_id"`
-	PositionID          int64     `db:"position_id" json:"position_id"`
-	Symbol              string    `db:"symbol" json:"symbol"`
-	Side                string    `db:"side" json:"side"`
-	Volume              float64   `db:"volume" json:"volume"`
-	OpenTime            time.Time `db:"open_time" json:"open_time"`
-	CloseTime           time.Time `db:"close_time" json:"close_time"`
-	OpenPrice           float64   `db:"open_price" json:"open_price"`
-	ClosePrice          float64   `db:"close_price" json:"close_price"`
-	Profit              float64   `db:"profit" json:"profit"`
-	Commission          float64   `db:"commission" json:"commission"`
-	Swap                float64   `db:"swap" json:"swap"`
+	PositionIds         []int64   `db:"position_ids" json:"position_ids"`
 }
 
-func (q *Queries) InsertTrade(ctx context.Context, arg InsertTradeParams) error {
-	_, err := q.db.Exec(ctx, insertTrade,
-		arg.TradingAccountLogin,
-		arg.CompetitionID,
-		arg.PositionID,
-		arg.Symbol,
-		arg.Side,
-		arg.Volume,
-		arg.OpenTime,
-		arg.CloseTime,
-		arg.OpenPrice,
-		arg.ClosePrice,
-		arg.Profit,
-		arg.Commission,
-		arg.Swap,
-	)
-	return err
+func (q *Queries) ListForeignTradePositions(ctx context.Context, arg ListForeignTradePositionsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listForeignTradePositions, arg.TradingAccountLogin, arg.CompetitionID, arg.PositionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var position_id int64
+		if err := rows.Scan(&position_id); err != nil {
+			return nil, err
+		}
+		items = append(items, position_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const listTradesByAccountLogin = `-- name: ListTradesByAccountLogin :many
-SELECT trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap, created_at FROM trades
+const listTradeHistory = `-- name: ListTradeHistory :many
+SELECT trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap, created_at, version, instrument FROM trades
 WHERE trading_account_login = $1
-ORDER BY close_time DESC
+AND ($2::UUID IS NULL OR competition_id = $2::UUID)
+AND ($3::TIMESTAMPTZ IS NULL OR close_time >= $3::TIMESTAMPTZ)
+AND ($4::TIMESTAMPTZ IS NULL OR close_time < $4::TIMESTAMPTZ)
+AND ($5::TEXT IS NULL OR symbol = $5::TEXT OR instrument = $5::TEXT)
+AND ($6::TEXT IS NULL OR side = $6::TEXT)
+AND (
+    $7::TEXT IS NULL
+    OR ($7::TEXT = 'win' AND profit + commission + swap > 0)
+    OR ($7::TEXT = 'loss' AND profit + commission + swap < 0)
+)
+AND (
+    $8::TIMESTAMPTZ IS NULL
+    OR (close_time, position_id) < ($8::TIMESTAMPTZ, $9::BIGINT)
+)
+ORDER BY close_time DESC, position_id DESC
+LIMIT $10
 `
 
-func (q *Queries) ListTradesByAccountLogin(ctx context.Context, tradingAccountLogin int64) ([]Trade, error) {
-	rows, err := q.db.Query(ctx, listTradesByAccountLogin, tradingAccountLogin)
+type ListTradeHistoryParams struct {
+	TradingAccountLogin int64       `db:"trading_account_login" json:"trading_account_login"`
+	CompetitionID       *uuid.UUID  `db:"competition_id" json:"competition_id"`
+	ClosedFrom          *time.Time  `db:"closed_from" json:"closed_from"`
+	ClosedTo            *time.Time  `db:"closed_to" json:"closed_to"`
+	Symbol              pgtype.Text `db:"symbol" json:"symbol"`
+	Side                pgtype.Text `db:"side" json:"side"`
+	Outcome             pgtype.Text `db:"outcome" json:"outcome"`
+	CursorCloseTime     *time.Time  `db:"cursor_close_time" json:"cursor_close_time"`
+	CursorPositionID    pgtype.Int8 `db:"cursor_position_id" json:"cursor_position_id"`
+	RowLimit            int32       `db:"row_limit" json:"row_limit"`
+}
+
+func (q *Queries) ListTradeHistory(ctx context.Context, arg ListTradeHistoryParams) ([]Trade, error) {
+	rows, err := q.db.Query(ctx, listTradeHistory,
+		arg.TradingAccountLogin,
+		arg.CompetitionID,
+		arg.ClosedFrom,
+		arg.ClosedTo,
+		arg.Symbol,
+		arg.Side,
+		arg.Outcome,
+		arg.CursorCloseTime,
+		arg.CursorPositionID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +207,8 @@ func (q *Queries) ListTradesByAccountLogin(ctx context.Context, tradingAccountLo
 			&i.Commission,
 			&i.Swap,
 			&i.CreatedAt,
+			&i.Version,
+			&i.Instrument,
 		); err != nil {
 			return nil, err
 		}
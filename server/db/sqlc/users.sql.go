@@ -17,7 +17,7 @@ INSERT INTO users (
      email, username, discord_username, password_hash                   
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING id, email, username, discord_username, created_at, updated_at
+) RETURNING id, email, username, discord_username, created_at, updated_at, email_verified_at
 `
 
 type CreateUserParams struct {
@@ -28,12 +28,13 @@ type CreateUserParams struct {
 }
 
 type CreateUserRow struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	Email           string    `db:"email" json:"email"`
-	Username        string    `db:"username" json:"username"`
-	DiscordUsername string    `db:"discord_username" json:"discord_username"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	ID              uuid.UUID  `db:"id" json:"id"`
+	Email           string     `db:"email" json:"email"`
+	Username        string     `db:"username" json:"username"`
+	DiscordUsername string     `db:"discord_username" json:"discord_username"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"email_verified_at"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
@@ -51,12 +52,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		&i.DiscordUsername,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, username, discord_username, password_hash, created_at, updated_at, role FROM users
+SELECT id, email, username, discord_username, password_hash, created_at, updated_at, role, email_verified_at FROM users
 WHERE email = $1
 `
 
@@ -72,12 +74,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Role,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, username, discord_username, password_hash, created_at, updated_at, role FROM users
+SELECT id, email, username, discord_username, password_hash, created_at, updated_at, role, email_verified_at FROM users
 WHERE id = $1
 `
 
@@ -93,6 +96,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Role,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -112,6 +116,22 @@ func (q *Queries) GetUsernameByTradingAccountLogin(ctx context.Context, login in
 	return username, err
 }
 
+const markEmailVerified = `-- name: MarkEmailVerified :execrows
+UPDATE users
+SET email_verified_at = now(),
+    updated_at = now()
+WHERE id = $1
+AND email_verified_at IS NULL
+`
+
+func (q *Queries) MarkEmailVerified(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markEmailVerified, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePasswordHash = `-- name: UpdatePasswordHash :exec
 UPDATE users
 SET password_hash = $2,
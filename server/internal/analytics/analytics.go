@@ -0,0 +1,75 @@
+// Package analytics derives performance statistics from closed trades. It
+// has no dependencies on storage; callers convert their trades to Trade.
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+// Trade is a closed trade. NetProfit includes commission and swap.
+type Trade struct {
+	PositionID int64
+	// Symbol is what trades are grouped by, preferably the canonical
+	// instrument.
+	Symbol    string
+	Side      string
+	Volume    float64
+	OpenTime  time.Time
+	CloseTime time.Time
+	NetProfit float64
+}
+
+type Report struct {
+	TradeCount     int
+	NetProfit      float64
+	EquityCurve    []EquityPoint
+	DailyPnL       []DailyPnL
+	Symbols        []SymbolStats
+	Long           SideStats
+	Short          SideStats
+	AverageHolding time.Duration
+	// LargestWin and LargestLoss are nil when there is no winning or losing
+	// trade.
+	LargestWin  *TradeResult
+	LargestLoss *TradeResult
+	Streaks     Streaks
+	Drawdown    Drawdown
+}
+
+// Analyze builds the full report for an account that started at
+// accountSize.
+func Analyze(accountSize float64, trades []Trade) Report {
+	sorted := SortByClose(trades)
+	curve := EquityCurve(accountSize, sorted)
+
+	r := Report{
+		TradeCount:     len(sorted),
+		EquityCurve:    curve,
+		DailyPnL:       DailyProfit(sorted),
+		Symbols:        BySymbol(sorted),
+		AverageHolding: AverageHolding(sorted),
+		Streaks:        ConsecutiveStreaks(sorted),
+		Drawdown:       MaxDrawdown(accountSize, curve),
+	}
+	r.Long, r.Short = BySide(sorted)
+	r.LargestWin, r.LargestLoss = Extremes(sorted)
+	for _, t := range sorted {
+		r.NetProfit += t.NetProfit
+	}
+	return r
+}
+
+// SortByClose returns a copy of trades ordered by close time, then position
+// ID, the order every statistic here assumes.
+func SortByClose(trades []Trade) []Trade {
+	sorted := make([]Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CloseTime.Equal(sorted[j].CloseTime) {
+			return sorted[i].PositionID < sorted[j].PositionID
+		}
+		return sorted[i].CloseTime.Before(sorted[j].CloseTime)
+	})
+	return sorted
+}
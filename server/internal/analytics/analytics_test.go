@@ -0,0 +1,94 @@
+package analytics
+
+import "testing"
+
+func TestSortByClose(t *testing.T) {
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   []int64
+	}{
+		{name: "empty"},
+		{
+			name: "by close time, then position",
+			trades: []Trade{
+				closedAt(3, at(2, 10, 0), 0),
+				closedAt(2, at(1, 10, 0), 0),
+				closedAt(1, at(1, 10, 0), 0),
+			},
+			want: []int64{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]Trade(nil), tt.trades...)
+			got := SortByClose(tt.trades)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d trades, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].PositionID != tt.want[i] {
+					t.Errorf("trade %d = %d, want %d", i, got[i].PositionID, tt.want[i])
+				}
+			}
+			for i := range input {
+				if tt.trades[i].PositionID != input[i].PositionID {
+					t.Fatal("SortByClose reordered its input")
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name         string
+		trades       []Trade
+		wantCount    int
+		wantNet      float64
+		wantDays     int
+		wantDrawdown float64
+		wantEquity   float64
+	}{
+		{name: "empty"},
+		{
+			name: "unsorted input",
+			trades: []Trade{
+				closedAt(2, at(2, 10, 0), -100),
+				closedAt(1, at(1, 10, 0), 200),
+				closedAt(3, at(2, 11, 0), 50),
+			},
+			wantCount:    3,
+			wantNet:      150,
+			wantDays:     2,
+			wantDrawdown: 100.0 / 1200 * 100,
+			wantEquity:   1150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Analyze(1000, tt.trades)
+
+			if r.TradeCount != tt.wantCount || !almostEqual(r.NetProfit, tt.wantNet) {
+				t.Errorf("got %d trades for %v, want %d for %v", r.TradeCount, r.NetProfit, tt.wantCount, tt.wantNet)
+			}
+			if len(r.DailyPnL) != tt.wantDays {
+				t.Errorf("got %d days, want %d", len(r.DailyPnL), tt.wantDays)
+			}
+			if !almostEqual(r.Drawdown.Percent, tt.wantDrawdown) {
+				t.Errorf("drawdown = %v%%, want %v%%", r.Drawdown.Percent, tt.wantDrawdown)
+			}
+			if len(r.EquityCurve) != tt.wantCount {
+				t.Fatalf("got %d equity points, want %d", len(r.EquityCurve), tt.wantCount)
+			}
+			if n := len(r.EquityCurve); n > 0 && !almostEqual(r.EquityCurve[n-1].Equity, tt.wantEquity) {
+				t.Errorf("final equity = %v, want %v", r.EquityCurve[n-1].Equity, tt.wantEquity)
+			}
+			if tt.wantCount == 0 && (r.LargestWin != nil || r.LargestLoss != nil || r.Symbols != nil) {
+				t.Errorf("empty report = %+v", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type SymbolStats struct {
+	Symbol     string
+	TradeCount int
+	Wins       int
+	Volume     float64
+	NetProfit  float64
+}
+
+type SideStats struct {
+	TradeCount int
+	Wins       int
+	NetProfit  float64
+	// WinRate is a percentage, 0 without trades.
+	WinRate float64
+}
+
+// TradeResult identifies a single notable trade.
+type TradeResult struct {
+	PositionID int64
+	Symbol     string
+	NetProfit  float64
+	CloseTime  time.Time
+}
+
+// Streaks are runs of consecutive winning or losing trades. A break-even
+// trade ends both. Current is positive for a running win streak and
+// negative for a losing one.
+type Streaks struct {
+	MaxWins   int
+	MaxLosses int
+	Current   int
+}
+
+// BySymbol groups trades by symbol, most traded first.
+func BySymbol(trades []Trade) []SymbolStats {
+	index := make(map[string]int)
+	var out []SymbolStats
+	for _, t := range trades {
+		i, ok := index[t.Symbol]
+		if !ok {
+			i = len(out)
+			index[t.Symbol] = i
+			out = append(out, SymbolStats{Symbol: t.Symbol})
+		}
+		s := &out[i]
+		s.TradeCount++
+		if t.NetProfit > 0 {
+			s.Wins++
+		}
+		s.Volume += t.Volume
+		s.NetProfit += t.NetProfit
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].TradeCount != out[j].TradeCount {
+			return out[i].TradeCount > out[j].TradeCount
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out
+}
+
+// BySide splits trades into buys (long) and sells (short).
+func BySide(trades []Trade) (long, short SideStats) {
+	for _, t := range trades {
+		s := &long
+		if strings.EqualFold(t.Side, "sell") {
+			s = &short
+		}
+		s.TradeCount++
+		if t.NetProfit > 0 {
+			s.Wins++
+		}
+		s.NetProfit += t.NetProfit
+	}
+
+	for _, s := range []*SideStats{&long, &short} {
+		if s.TradeCount > 0 {
+			s.WinRate = float64(s.Wins) / float64(s.TradeCount) * 100
+		}
+	}
+	return long, short
+}
+
+// AverageHolding is the mean time between opening and closing a trade.
+func AverageHolding(trades []Trade) time.Duration {
+	if len(trades) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, t := range trades {
+		total += t.CloseTime.Sub(t.OpenTime)
+	}
+	return total / time.Duration(len(trades))
+}
+
+// Extremes returns the most profitable and the most losing trade. On ties
+// the earlier trade in the slice wins.
+func Extremes(trades []Trade) (largestWin, largestLoss *TradeResult) {
+	for _, t := range trades {
+		if t.NetProfit > 0 && (largestWin == nil || t.NetProfit > largestWin.NetProfit) {
+			largestWin = result(t)
+		}
+		if t.NetProfit < 0 && (largestLoss == nil || t.NetProfit < largestLoss.NetProfit) {
+			largestLoss = result(t)
+		}
+	}
+	return largestWin, largestLoss
+}
+
+func result(t Trade) *TradeResult {
+	return &TradeResult{
+		PositionID: t.PositionID,
+		Symbol:     t.Symbol,
+		NetProfit:  t.NetProfit,
+		CloseTime:  t.CloseTime,
+	}
+}
+
+// ConsecutiveStreaks measures win and loss runs over trades sorted by close
+// time.
+func ConsecutiveStreaks(sorted []Trade) Streaks {
+	var s Streaks
+	for _, t := range sorted {
+		switch {
+		case t.NetProfit > 0:
+			if s.Current < 0 {
+				s.Current = 0
+			}
+			s.Current++
+			s.MaxWins = max(s.MaxWins, s.Current)
+		case t.NetProfit < 0:
+			if s.Current > 0 {
+				s.Current = 0
+			}
+			s.Current--
+			s.MaxLosses = max(s.MaxLosses, -s.Current)
+		default:
+			s.Current = 0
+		}
+	}
+	return s
+}
@@ -0,0 +1,211 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBySymbol(t *testing.T) {
+	trade := func(symbol string, volume, net float64) Trade {
+		return Trade{Symbol: symbol, Volume: volume, NetProfit: net}
+	}
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   []SymbolStats
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "most traded first, then by symbol",
+			trades: []Trade{
+				trade("XAUUSD", 0.5, -20),
+				trade("EURUSD", 1, 30),
+				trade("GBPUSD", 2, 10),
+				trade("EURUSD", 1.5, -5),
+			},
+			want: []SymbolStats{
+				{Symbol: "EURUSD", TradeCount: 2, Wins: 1, Volume: 2.5, NetProfit: 25},
+				{Symbol: "GBPUSD", TradeCount: 1, Wins: 1, Volume: 2, NetProfit: 10},
+				{Symbol: "XAUUSD", TradeCount: 1, Wins: 0, Volume: 0.5, NetProfit: -20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BySymbol(tt.trades)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Symbol != w.Symbol || g.TradeCount != w.TradeCount || g.Wins != w.Wins || !almostEqual(g.Volume, w.Volume) || !almostEqual(g.NetProfit, w.NetProfit) {
+					t.Errorf("symbol %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestBySide(t *testing.T) {
+	trade := func(side string, net float64) Trade {
+		return Trade{Side: side, NetProfit: net}
+	}
+
+	tests := []struct {
+		name      string
+		trades    []Trade
+		wantLong  SideStats
+		wantShort SideStats
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "splits buys and sells",
+			trades: []Trade{
+				trade("buy", 50),
+				trade("SELL", -20),
+				trade("buy", -10),
+				trade("sell", 40),
+				trade("buy", 0),
+				trade("buy", 30),
+			},
+			wantLong:  SideStats{TradeCount: 4, Wins: 2, NetProfit: 70, WinRate: 50},
+			wantShort: SideStats{TradeCount: 2, Wins: 1, NetProfit: 20, WinRate: 50},
+		},
+		{
+			name:     "only longs",
+			trades:   []Trade{trade("buy", 10), trade("buy", 20), trade("buy", -5), trade("buy", 1)},
+			wantLong: SideStats{TradeCount: 4, Wins: 3, NetProfit: 26, WinRate: 75},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			long, short := BySide(tt.trades)
+			if !sameSide(long, tt.wantLong) {
+				t.Errorf("long = %+v, want %+v", long, tt.wantLong)
+			}
+			if !sameSide(short, tt.wantShort) {
+				t.Errorf("short = %+v, want %+v", short, tt.wantShort)
+			}
+		})
+	}
+}
+
+func sameSide(a, b SideStats) bool {
+	return a.TradeCount == b.TradeCount && a.Wins == b.Wins && almostEqual(a.NetProfit, b.NetProfit) && almostEqual(a.WinRate, b.WinRate)
+}
+
+func TestAverageHolding(t *testing.T) {
+	held := func(d time.Duration) Trade {
+		return Trade{OpenTime: at(1, 10, 0), CloseTime: at(1, 10, 0).Add(d)}
+	}
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   time.Duration
+	}{
+		{name: "empty"},
+		{name: "single trade", trades: []Trade{held(90 * time.Minute)}, want: 90 * time.Minute},
+		{name: "mean", trades: []Trade{held(time.Hour), held(3 * time.Hour), held(2 * time.Hour)}, want: 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AverageHolding(tt.trades); got != tt.want {
+				t.Errorf("AverageHolding = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtremes(t *testing.T) {
+	tests := []struct {
+		name     string
+		trades   []Trade
+		wantWin  int64 // position ID, 0 for none
+		wantLoss int64
+	}{
+		{name: "empty"},
+		{
+			name:    "only winners",
+			trades:  []Trade{closedAt(1, at(1, 10, 0), 10), closedAt(2, at(1, 11, 0), 30)},
+			wantWin: 2,
+		},
+		{
+			name:     "only losers",
+			trades:   []Trade{closedAt(1, at(1, 10, 0), -10), closedAt(2, at(1, 11, 0), -30)},
+			wantLoss: 2,
+		},
+		{
+			name:   "break-even is neither",
+			trades: []Trade{closedAt(1, at(1, 10, 0), 0)},
+		},
+		{
+			name: "ties keep the earlier trade",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), 50),
+				closedAt(2, at(1, 11, 0), -40),
+				closedAt(3, at(1, 12, 0), 50),
+				closedAt(4, at(1, 13, 0), -40),
+			},
+			wantWin:  1,
+			wantLoss: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			win, loss := Extremes(tt.trades)
+			if got := positionOf(win); got != tt.wantWin {
+				t.Errorf("largest win = %d, want %d", got, tt.wantWin)
+			}
+			if got := positionOf(loss); got != tt.wantLoss {
+				t.Errorf("largest loss = %d, want %d", got, tt.wantLoss)
+			}
+		})
+	}
+}
+
+func positionOf(r *TradeResult) int64 {
+	if r == nil {
+		return 0
+	}
+	return r.PositionID
+}
+
+func TestConsecutiveStreaks(t *testing.T) {
+	trades := func(nets ...float64) []Trade {
+		out := make([]Trade, len(nets))
+		for i, net := range nets {
+			out[i] = Trade{PositionID: int64(i + 1), NetProfit: net}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   Streaks
+	}{
+		{name: "empty"},
+		{name: "wins then losses", trades: trades(10, 20, -5, -5, -5, 15), want: Streaks{MaxWins: 2, MaxLosses: 3, Current: 1}},
+		{name: "running loss streak", trades: trades(10, -1, -2), want: Streaks{MaxWins: 1, MaxLosses: 2, Current: -2}},
+		{name: "break-even ends a streak", trades: trades(10, 20, 0, 30), want: Streaks{MaxWins: 2, Current: 1}},
+		{name: "ends break-even", trades: trades(-10, 0), want: Streaks{MaxLosses: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConsecutiveStreaks(tt.trades); got != tt.want {
+				t.Errorf("ConsecutiveStreaks = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package analytics
+
+import "time"
+
+// EquityPoint is the account equity right after a trade closed.
+type EquityPoint struct {
+	Time       time.Time
+	PositionID int64
+	Equity     float64
+}
+
+type DailyPnL struct {
+	// Date is the UTC day, formatted as YYYY-MM-DD.
+	Date       string
+	NetProfit  float64
+	TradeCount int
+}
+
+// Drawdown is the largest fall of equity from a running peak. PeakAt is nil
+// when the peak was the starting balance and RecoveredAt is nil while equity
+// has not climbed back to the peak.
+type Drawdown struct {
+	Amount      float64
+	Percent     float64
+	PeakAt      *time.Time
+	TroughAt    time.Time
+	RecoveredAt *time.Time
+}
+
+// EquityCurve accumulates the net profit of trades, sorted by close time, on
+// top of accountSize.
+func EquityCurve(accountSize float64, sorted []Trade) []EquityPoint {
+	curve := make([]EquityPoint, 0, len(sorted))
+	equity := accountSize
+	for _, t := range sorted {
+		equity += t.NetProfit
+		curve = append(curve, EquityPoint{Time: t.CloseTime, PositionID: t.PositionID, Equity: equity})
+	}
+	return curve
+}
+
+// DailyProfit sums net profit per UTC day of closing, in date order. Days
+// without closed trades are left out.
+func DailyProfit(sorted []Trade) []DailyPnL {
+	var days []DailyPnL
+	for _, t := range sorted {
+		date := t.CloseTime.UTC().Format(time.DateOnly)
+		if n := len(days); n == 0 || days[n-1].Date != date {
+			days = append(days, DailyPnL{Date: date})
+		}
+		d := &days[len(days)-1]
+		d.NetProfit += t.NetProfit
+		d.TradeCount++
+	}
+	return days
+}
+
+// MaxDrawdown finds the deepest peak-to-trough fall of the curve, measured
+// as a percentage of the peak. Ties keep the earliest drawdown.
+func MaxDrawdown(accountSize float64, curve []EquityPoint) Drawdown {
+	var (
+		dd     Drawdown
+		peak   = accountSize
+		peakAt *time.Time
+		open   bool // dd has not recovered yet
+	)
+
+	for i := range curve {
+		p := curve[i]
+		if p.Equity >= peak {
+			if open && dd.RecoveredAt == nil {
+				at := p.Time
+				dd.RecoveredAt = &at
+			}
+			open = false
+			peak = p.Equity
+			at := p.Time
+			peakAt = &at
+			continue
+		}
+		if peak <= 0 {
+			continue
+		}
+
+		percent := (peak - p.Equity) / peak * 100
+		if percent > dd.Percent {
+			dd = Drawdown{
+				Amount:   peak - p.Equity,
+				Percent:  percent,
+				PeakAt:   peakAt,
+				TroughAt: p.Time,
+			}
+			open = true
+		}
+	}
+	return dd
+}
@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+}
+
+func closedAt(id int64, closeTime time.Time, net float64) Trade {
+	return Trade{PositionID: id, Symbol: "EURUSD", Side: "buy", OpenTime: closeTime.Add(-time.Hour), CloseTime: closeTime, NetProfit: net}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEquityCurve(t *testing.T) {
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   []EquityPoint
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "accumulates net profit",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), 100),
+				closedAt(2, at(1, 12, 0), -50),
+				closedAt(3, at(2, 9, 0), 25),
+			},
+			want: []EquityPoint{
+				{Time: at(1, 10, 0), PositionID: 1, Equity: 1100},
+				{Time: at(1, 12, 0), PositionID: 2, Equity: 1050},
+				{Time: at(2, 9, 0), PositionID: 3, Equity: 1075},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EquityCurve(1000, tt.trades)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d points, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !got[i].Time.Equal(tt.want[i].Time) || got[i].PositionID != tt.want[i].PositionID || !almostEqual(got[i].Equity, tt.want[i].Equity) {
+					t.Errorf("point %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDailyProfit(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   []DailyPnL
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "groups by UTC day and skips days without trades",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), 100),
+				closedAt(2, at(1, 23, 59), -40),
+				closedAt(3, at(3, 0, 0), 15),
+			},
+			want: []DailyPnL{
+				{Date: "2024-03-01", NetProfit: 60, TradeCount: 2},
+				{Date: "2024-03-03", NetProfit: 15, TradeCount: 1},
+			},
+		},
+		{
+			// Opened on March 1st and closed after local midnight, which is
+			// still March 1st in UTC.
+			name: "trade across a local day boundary",
+			trades: []Trade{
+				{
+					PositionID: 1,
+					OpenTime:   time.Date(2024, time.March, 1, 22, 0, 0, 0, cet),
+					CloseTime:  time.Date(2024, time.March, 2, 0, 30, 0, 0, cet),
+					NetProfit:  80,
+				},
+				{
+					PositionID: 2,
+					OpenTime:   time.Date(2024, time.March, 2, 0, 45, 0, 0, cet),
+					CloseTime:  time.Date(2024, time.March, 2, 1, 30, 0, 0, cet),
+					NetProfit:  -30,
+				},
+			},
+			want: []DailyPnL{
+				{Date: "2024-03-01", NetProfit: 80, TradeCount: 1},
+				{Date: "2024-03-02", NetProfit: -30, TradeCount: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DailyProfit(tt.trades)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Date != tt.want[i].Date || got[i].TradeCount != tt.want[i].TradeCount || !almostEqual(got[i].NetProfit, tt.want[i].NetProfit) {
+					t.Errorf("day %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   Drawdown
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "only gains",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), 100),
+				closedAt(2, at(1, 11, 0), 50),
+			},
+		},
+		{
+			name: "peak, trough and recovery",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), 100), // 1100, peak
+				closedAt(2, at(1, 11, 0), -60), // 1040
+				closedAt(3, at(1, 12, 0), -50), // 990, trough
+				closedAt(4, at(1, 13, 0), 110), // 1100, recovered
+				closedAt(5, at(1, 14, 0), 100), // 1200
+				closedAt(6, at(1, 15, 0), -60), // 1140, shallower
+			},
+			want: Drawdown{
+				Amount:      110,
+				Percent:     10,
+				PeakAt:      ptr(at(1, 10, 0)),
+				TroughAt:    at(1, 12, 0),
+				RecoveredAt: ptr(at(1, 13, 0)),
+			},
+		},
+		{
+			name: "deeper drawdown after a recovery",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), -100), // 900
+				closedAt(2, at(1, 11, 0), 100),  // 1000, recovered
+				closedAt(3, at(1, 12, 0), 200),  // 1200, peak
+				closedAt(4, at(1, 13, 0), -240), // 960
+			},
+			want: Drawdown{
+				Amount:   240,
+				Percent:  20,
+				PeakAt:   ptr(at(1, 12, 0)),
+				TroughAt: at(1, 13, 0),
+			},
+		},
+		{
+			name: "all losing",
+			trades: []Trade{
+				closedAt(1, at(1, 10, 0), -100),
+				closedAt(2, at(1, 11, 0), -150),
+				closedAt(3, at(1, 12, 0), -50),
+			},
+			want: Drawdown{
+				Amount:   300,
+				Percent:  30,
+				TroughAt: at(1, 12, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxDrawdown(1000, EquityCurve(1000, tt.trades))
+
+			if !almostEqual(got.Amount, tt.want.Amount) || !almostEqual(got.Percent, tt.want.Percent) {
+				t.Errorf("drawdown = %v (%v%%), want %v (%v%%)", got.Amount, got.Percent, tt.want.Amount, tt.want.Percent)
+			}
+			if !sameTime(got.PeakAt, tt.want.PeakAt) {
+				t.Errorf("PeakAt = %v, want %v", got.PeakAt, tt.want.PeakAt)
+			}
+			if !got.TroughAt.Equal(tt.want.TroughAt) {
+				t.Errorf("TroughAt = %v, want %v", got.TroughAt, tt.want.TroughAt)
+			}
+			if !sameTime(got.RecoveredAt, tt.want.RecoveredAt) {
+				t.Errorf("RecoveredAt = %v, want %v", got.RecoveredAt, tt.want.RecoveredAt)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
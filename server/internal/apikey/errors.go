@@ -0,0 +1,15 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrNotFound          = errors.New("api key not found")
+	ErrInvalidName       = errors.New("invalid api key name")
+	ErrInvalidScopes     = errors.New("invalid api key scopes")
+	ErrMissingSignature  = errors.New("missing signature headers")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrTimestampSkew     = errors.New("timestamp outside allowed window")
+	ErrNonceReused       = errors.New("nonce already used")
+	ErrRevoked           = errors.New("api key revoked")
+	ErrInsufficientScope = errors.New("api key lacks required scope")
+)
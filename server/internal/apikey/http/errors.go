@@ -0,0 +1,32 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/apikey"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+)
+
+type errorMapping struct {
+	status  int
+	message string
+}
+
+var errorMap = map[error]errorMapping{
+	apikey.ErrNotFound:      {http.StatusNotFound, "API key not found or already revoked"},
+	apikey.ErrInvalidName:   {http.StatusBadRequest, "Name is required and must be at most 100 characters"},
+	apikey.ErrInvalidScopes: {http.StatusBadRequest, "Scopes must be a non-empty list of known, distinct scopes"},
+}
+
+// writeDomainError maps domain errors to HTTP responses
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	for domainErr, mapping := range errorMap {
+		if errors.Is(err, domainErr) {
+			httputil.WriteError(w, r, mapping.status, mapping.message, err)
+			return
+		}
+	}
+
+	httputil.WriteInternalError(w, r, err)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/apikey"
+	"github.com/filipcvejic/trading_tournament/internal/auth"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service *apikey.Service
+}
+
+func NewHandler(service *apikey.Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/api-keys", func(r chi.Router) {
+		r.Use(auth.AuthenticationMiddleware)
+		r.Use(auth.RequireAdmin)
+
+		r.Post("/", h.createKey)
+		r.Get("/", h.listKeys)
+		r.Delete("/{keyID}", h.revokeKey)
+	})
+}
+
+func (h *Handler) createKey(w http.ResponseWriter, r *http.Request) {
+	var req apikey.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID, ok := auth.GetUserID(r); ok {
+		createdBy = &userID
+	}
+
+	scopes := make([]apikey.Scope, 0, len(req.Scopes))
+	for _, s := range req.Scopes {
+		scopes = append(scopes, apikey.Scope(s))
+	}
+
+	k, secret, err := h.service.Create(r.Context(), req.Name, scopes, createdBy)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, apikey.CreatedAPIKeyResponse{
+		APIKeyResponse: apikey.ToResponse(k),
+		Secret:         secret,
+	})
+}
+
+func (h *Handler) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.List(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	out := make([]apikey.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, apikey.ToResponse(k))
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) revokeKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "keyID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid API key ID format", err)
+		return
+	}
+
+	if err := h.service.Revoke(r.Context(), id); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
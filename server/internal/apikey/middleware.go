@@ -0,0 +1,72 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+)
+
+type contextKey string
+
+const KeyContextKey contextKey = "apiKey"
+
+// maxSignedBody caps how much of a request body is read for signing.
+const maxSignedBody = 10 << 20
+
+// RequireAPIKey only lets through requests signed by an active key that has
+// scope. The body is buffered for the signature check and replaced, so
+// handlers can read it as usual.
+func RequireAPIKey(service *Service, scope Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBody))
+			if err != nil {
+				httputil.WriteError(w, r, http.StatusRequestEntityTooLarge, "Request body too large", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			k, err := service.Verify(r.Context(), SignedRequest{
+				KeyID:     r.Header.Get(HeaderKeyID),
+				Timestamp: r.Header.Get(HeaderTimestamp),
+				Nonce:     r.Header.Get(HeaderNonce),
+				Signature: r.Header.Get(HeaderSignature),
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Body:      body,
+			}, scope)
+			if err != nil {
+				writeVerifyError(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), KeyContextKey, k)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetAPIKey retrieves the authenticated API key from the request context
+func GetAPIKey(r *http.Request) (APIKey, bool) {
+	k, ok := r.Context().Value(KeyContextKey).(APIKey)
+	return k, ok
+}
+
+func writeVerifyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrInsufficientScope):
+		httputil.WriteError(w, r, http.StatusForbidden, "API key lacks the required scope", err)
+	case errors.Is(err, ErrMissingSignature),
+		errors.Is(err, ErrInvalidSignature),
+		errors.Is(err, ErrTimestampSkew),
+		errors.Is(err, ErrNonceReused),
+		errors.Is(err, ErrRevoked):
+		httputil.WriteError(w, r, http.StatusUnauthorized, "Invalid request signature", err)
+	default:
+		httputil.WriteInternalError(w, r, err)
+	}
+}
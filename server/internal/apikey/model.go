@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Scope string
+
+const (
+	// ScopeTradesWrite allows posting closed trades and account sizes.
+	ScopeTradesWrite Scope = "trades:write"
+	// ScopeTrackedTradesWrite allows posting tracked trade events.
+	ScopeTrackedTradesWrite Scope = "tracked_trades:write"
+)
+
+var knownScopes = []Scope{ScopeTradesWrite, ScopeTrackedTradesWrite}
+
+type APIKey struct {
+	ID                  uuid.UUID
+	Name                string
+	SigningKeyEncrypted string
+	Scopes              []Scope
+	CreatedBy           *uuid.UUID
+	CreatedAt           time.Time
+	LastUsedAt          *time.Time
+	RevokedAt           *time.Time
+}
+
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/db"
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, k APIKey) (APIKey, error)
+	GetByID(ctx context.Context, id uuid.UUID) (APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID) error
+	// UseNonce records a nonce for the key and reports false if it was
+	// already recorded.
+	UseNonce(ctx context.Context, id uuid.UUID, nonce string) (bool, error)
+	DeleteNoncesBefore(ctx context.Context, before time.Time) error
+}
+
+type PostgresRepository struct {
+	db *db.DB
+}
+
+func NewPostgresRepository(database *db.DB) *PostgresRepository {
+	return &PostgresRepository{db: database}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, k APIKey) (APIKey, error) {
+	row, err := r.db.Query.CreateApiKey(ctx, sqlc.CreateApiKeyParams{
+		ID:                  k.ID,
+		Name:                k.Name,
+		SigningKeyEncrypted: k.SigningKeyEncrypted,
+		Scopes:              scopesToStrings(k.Scopes),
+		CreatedBy:           k.CreatedBy,
+	})
+	if err != nil {
+		return APIKey{}, fmt.Errorf("create api key: %w", err)
+	}
+	return fromDB(row), nil
+}
+
+func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (APIKey, error) {
+	row, err := r.db.Query.GetApiKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, ErrNotFound
+		}
+		return APIKey{}, fmt.Errorf("get api key: %w", err)
+	}
+	return fromDB(row), nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]APIKey, error) {
+	rows, err := r.db.Query.ListApiKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+
+	keys := make([]APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, fromDB(row))
+	}
+	return keys, nil
+}
+
+func (r *PostgresRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	n, err := r.db.Query.RevokeApiKey(ctx, id)
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.Query.TouchApiKey(ctx, id); err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UseNonce(ctx context.Context, id uuid.UUID, nonce string) (bool, error) {
+	n, err := r.db.Query.InsertApiKeyNonce(ctx, sqlc.InsertApiKeyNonceParams{
+		ApiKeyID: id,
+		Nonce:    nonce,
+	})
+	if err != nil {
+		return false, fmt.Errorf("insert nonce: %w", err)
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresRepository) DeleteNoncesBefore(ctx context.Context, before time.Time) error {
+	if err := r.db.Query.DeleteApiKeyNoncesBefore(ctx, before); err != nil {
+		return fmt.Errorf("delete nonces: %w", err)
+	}
+	return nil
+}
+
+func fromDB(row sqlc.ApiKey) APIKey {
+	scopes := make([]Scope, 0, len(row.Scopes))
+	for _, s := range row.Scopes {
+		scopes = append(scopes, Scope(s))
+	}
+
+	return APIKey{
+		ID:                  row.ID,
+		Name:                row.Name,
+		SigningKeyEncrypted: row.SigningKeyEncrypted,
+		Scopes:              scopes,
+		CreatedBy:           row.CreatedBy,
+		CreatedAt:           row.CreatedAt,
+		LastUsedAt:          row.LastUsedAt,
+		RevokedAt:           row.RevokedAt,
+	}
+}
+
+func scopesToStrings(scopes []Scope) []string {
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		out = append(out, string(s))
+	}
+	return out
+}
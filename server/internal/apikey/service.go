@@ -0,0 +1,185 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/crypto"
+	"github.com/google/uuid"
+)
+
+// MaxClockSkew is how far a request timestamp may be from the server clock.
+// Nonces only need to be remembered for this long on either side.
+const MaxClockSkew = 5 * time.Minute
+
+type Service struct {
+	repo      Repository
+	cryptoKey []byte
+}
+
+func NewService(repo Repository, cryptoKeyBase64 string) (*Service, error) {
+	key, err := base64.StdEncoding.DecodeString(cryptoKeyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("decode crypto key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, crypto.ErrInvalidKeyLength
+	}
+	return &Service{repo: repo, cryptoKey: key}, nil
+}
+
+// Create issues a new key and returns it together with its secret. The
+// secret itself is not stored, so it cannot be shown again; the signing key
+// derived from it is, encrypted.
+func (s *Service) Create(ctx context.Context, name string, scopes []Scope, createdBy *uuid.UUID) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return APIKey{}, "", ErrInvalidName
+	}
+	if err := validateScopes(scopes); err != nil {
+		return APIKey{}, "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return APIKey{}, "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+
+	signingKey, err := crypto.EncryptString(s.cryptoKey, hex.EncodeToString(SigningKey(secret)))
+	if err != nil {
+		return APIKey{}, "", fmt.Errorf("encrypt signing key: %w", err)
+	}
+
+	k, err := s.repo.Create(ctx, APIKey{
+		ID:                  uuid.New(),
+		Name:                name,
+		SigningKeyEncrypted: signingKey,
+		Scopes:              scopes,
+		CreatedBy:           createdBy,
+	})
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	return k, secret, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *Service) Revoke(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Verify authenticates a signed request and returns the key that signed it.
+// The nonce is only consumed once the signature has been checked, so
+// forged requests cannot burn nonces of a legitimate client.
+func (s *Service) Verify(ctx context.Context, req SignedRequest, scope Scope) (APIKey, error) {
+	if req.KeyID == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return APIKey{}, ErrMissingSignature
+	}
+
+	id, err := uuid.Parse(req.KeyID)
+	if err != nil {
+		return APIKey{}, ErrInvalidSignature
+	}
+
+	if err := checkTimestamp(req.Timestamp, time.Now()); err != nil {
+		return APIKey{}, err
+	}
+
+	k, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return APIKey{}, ErrInvalidSignature
+		}
+		return APIKey{}, err
+	}
+
+	signingKey, err := s.signingKey(k)
+	if err != nil {
+		return APIKey{}, err
+	}
+	if !req.Valid(signingKey) {
+		return APIKey{}, ErrInvalidSignature
+	}
+	if k.RevokedAt != nil {
+		return APIKey{}, ErrRevoked
+	}
+	if !k.HasScope(scope) {
+		return APIKey{}, ErrInsufficientScope
+	}
+
+	fresh, err := s.repo.UseNonce(ctx, k.ID, req.Nonce)
+	if err != nil {
+		return APIKey{}, err
+	}
+	if !fresh {
+		return APIKey{}, ErrNonceReused
+	}
+
+	if err := s.repo.Touch(ctx, k.ID); err != nil {
+		log.Printf("APIKEY: touch %s: %v", k.ID, err)
+	}
+	return k, nil
+}
+
+func (s *Service) signingKey(k APIKey) ([]byte, error) {
+	encoded, err := crypto.DecryptString(s.cryptoKey, k.SigningKeyEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt signing key of %s: %w", k.ID, err)
+	}
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key of %s: %w", k.ID, err)
+	}
+	return key, nil
+}
+
+// Run forgets nonces old enough that their timestamps would be rejected
+// anyway, until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(MaxClockSkew)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.DeleteNoncesBefore(ctx, time.Now().Add(-2*MaxClockSkew)); err != nil {
+				log.Printf("APIKEY: purge nonces: %v", err)
+			}
+		}
+	}
+}
+
+func validateScopes(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return ErrInvalidScopes
+	}
+
+	seen := make(map[Scope]bool, len(scopes))
+	for _, s := range scopes {
+		known := false
+		for _, k := range knownScopes {
+			if s == k {
+				known = true
+				break
+			}
+		}
+		if !known || seen[s] {
+			return ErrInvalidScopes
+		}
+		seen[s] = true
+	}
+	return nil
+}
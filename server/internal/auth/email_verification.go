@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/mailer"
+	"github.com/filipcvejic/trading_tournament/internal/user"
+	"github.com/google/uuid"
+)
+
+// Resending is limited per user: one email a minute and at most
+// verificationSendLimit in any hour, counting the one sent on registration.
+const (
+	verificationResendCooldown = time.Minute
+	verificationSendWindow     = time.Hour
+	verificationSendLimit      = 5
+)
+
+// ResendVerificationEmail sends the user a fresh verification link; earlier
+// links stop working.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	if u.EmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	sent, err := s.emailVerificationRepo.SentSince(ctx, u.ID, now.Add(-verificationSendWindow))
+	if err != nil {
+		return err
+	}
+	if len(sent) >= verificationSendLimit || len(sent) > 0 && now.Sub(sent[0]) < verificationResendCooldown {
+		return ErrVerificationThrottled
+	}
+
+	return s.sendVerificationEmail(ctx, u)
+}
+
+// VerifyEmail redeems the token from a verification email.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	_, err := s.emailVerificationRepo.Redeem(ctx, hashToken(token))
+	return err
+}
+
+// VerifyUserEmail marks the user's email verified without a token, for
+// admins vouching for an address by hand. The user's current access token
+// still says unverified until the session is next refreshed.
+func (s *AuthService) VerifyUserEmail(ctx context.Context, userID uuid.UUID) error {
+	marked, err := s.userRepo.MarkEmailVerified(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if marked {
+		return nil
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return ErrEmailAlreadyVerified
+}
+
+func (s *AuthService) sendVerificationEmail(ctx context.Context, u user.User) error {
+	token, err := generateTokenString(32)
+	if err != nil {
+		return err
+	}
+	if err := s.emailVerificationRepo.Create(ctx, u.ID, hashToken(token), time.Now().Add(s.cfg.EmailVerificationTTL)); err != nil {
+		return err
+	}
+
+	link := s.cfg.AppURL + "/verify-email?token=" + url.QueryEscape(token)
+	s.sendInBackground(mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm that this is your email address by opening this link:\n\n%s\n\n"+
+				"The link expires in %s. Until then you can log in, but not join competitions or request accounts.\n",
+			u.Username, link, formatTTL(s.cfg.EmailVerificationTTL),
+		),
+	})
+	return nil
+}
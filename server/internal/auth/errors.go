@@ -8,4 +8,15 @@ var (
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrExpiredToken       = errors.New("token expired")
 	ErrUnauthorized       = errors.New("unauthorized")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	ErrInvalidResetToken  = errors.New("invalid password reset token")
+
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrVerificationThrottled    = errors.New("too many verification emails")
+
+	ErrMFANotEnabled     = errors.New("two-factor authentication not enabled")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrInvalidMFAToken   = errors.New("invalid two-factor login token")
 )
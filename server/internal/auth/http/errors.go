@@ -21,6 +21,9 @@ var errorMap = map[error]errorMapping{
 	user.ErrUsernameAlreadyExists:        {http.StatusConflict, "Username is already taken"},
 	user.ErrDiscordUsernameAlreadyExists: {http.StatusConflict, "Discord username is already taken"},
 	user.ErrEmailAlreadyExists:           {http.StatusConflict, "Email is already in use"},
+	auth.ErrEmailAlreadyVerified:         {http.StatusConflict, "Email is already verified"},
+	auth.ErrMFANotEnabled:                {http.StatusConflict, "Two-factor authentication is not enabled"},
+	auth.ErrMFAAlreadyEnabled:            {http.StatusConflict, "Two-factor authentication is already enabled"},
 
 	// Bad Request (400)
 	user.ErrInvalidEmail: {
@@ -43,8 +46,23 @@ var errorMap = map[error]errorMapping{
 		http.StatusBadRequest, "Invalid input",
 	},
 	auth.ErrInvalidCredentials: {http.StatusBadRequest, "Invalid email or password"},
-	
-	auth.ErrUnauthorized: {http.StatusUnauthorized, "Unauthorized"},
+	auth.ErrInvalidResetToken:  {http.StatusBadRequest, "Reset link is invalid or has expired"},
+	auth.ErrInvalidVerificationToken: {
+		http.StatusBadRequest, "Verification link is invalid or has expired",
+	},
+	auth.ErrInvalidMFACode: {http.StatusBadRequest, "Authentication code is invalid"},
+
+	// Too Many Requests (429)
+	auth.ErrVerificationThrottled: {
+		http.StatusTooManyRequests, "Too many verification emails, please try again later",
+	},
+
+	// Unauthorized (401)
+	auth.ErrUnauthorized:       {http.StatusUnauthorized, "Unauthorized"},
+	auth.ErrInvalidToken:       {http.StatusUnauthorized, "Session is invalid, please log in again"},
+	auth.ErrExpiredToken:       {http.StatusUnauthorized, "Session has expired, please log in again"},
+	auth.ErrRefreshTokenReused: {http.StatusUnauthorized, "Session was revoked, please log in again"},
+	auth.ErrInvalidMFAToken:    {http.StatusUnauthorized, "Login has expired, please log in again"},
 }
 
 // writeDomainError maps domain errors to HTTP responses
@@ -2,41 +2,79 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/filipcvejic/trading_tournament/internal/auth"
 	"github.com/filipcvejic/trading_tournament/internal/config"
 	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/ratelimit"
 	"github.com/filipcvejic/trading_tournament/internal/validation"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+)
+
 type Handler struct {
-	service         *auth.AuthService
-	refreshTokenTTL time.Duration
+	service *auth.AuthService
+	limits  Limits
+}
+
+// Limits are the rate limiters of the public auth endpoints. Login counts
+// failed attempts per client IP and per email; the others count every
+// request per client IP.
+type Limits struct {
+	Login         *ratelimit.Limiter
+	Register      *ratelimit.Limiter
+	PasswordReset *ratelimit.Limiter
 }
 
-func NewHandler(service *auth.AuthService, refreshTokenTTL time.Duration) *Handler {
-	return &Handler{service: service, refreshTokenTTL: refreshTokenTTL}
+func NewHandler(service *auth.AuthService, limits Limits) *Handler {
+	return &Handler{service: service, limits: limits}
 }
 
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/register", h.Register)
+		r.With(ratelimit.Middleware(h.limits.Register)).Post("/register", h.Register)
 		r.Post("/login", h.Login)
-		//r.Post("/login-with-refresh", h.LoginWithRefresh)
-		//r.Post("/refresh", h.Refresh)
+		r.Post("/login/mfa", h.loginMFA)
+		r.Post("/refresh", h.Refresh)
 		r.Post("/logout", h.Logout)
-		r.Patch("/{userID}/reset-password", h.resetPassword)
+
+		r.Group(func(r chi.Router) {
+			r.Use(ratelimit.Middleware(h.limits.PasswordReset))
+			r.Post("/password-reset/request", h.requestPasswordReset)
+			r.Post("/password-reset/confirm", h.resetPassword)
+		})
+
+		r.Post("/verify-email", h.verifyEmail)
 
 		// protected
 		r.Group(func(r chi.Router) {
 			r.Use(auth.AuthenticationMiddleware)
 			r.Get("/me", h.me)
+			r.Post("/verify-email/resend", h.resendVerificationEmail)
+
+			r.Get("/mfa", h.mfaStatus)
+			r.Post("/mfa/totp", h.startTOTPEnrollment)
+			r.Post("/mfa/totp/confirm", h.confirmTOTPEnrollment)
+			r.Post("/mfa/totp/disable", h.disableTOTP)
+			r.Post("/mfa/recovery-codes", h.regenerateRecoveryCodes)
 		})
 	})
+
+	r.Route("/admin/users", func(r chi.Router) {
+		r.Use(auth.AuthenticationMiddleware)
+		r.Use(auth.RequireAdmin)
+
+		r.Post("/{userID}/verify-email", h.verifyUserEmail)
+	})
 }
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -74,85 +112,131 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	access, err := h.service.Login(r.Context(), req.Email, req.Password)
+	ipKey := ratelimit.IPKey(r)
+	emailKey := "email:" + strings.ToLower(strings.TrimSpace(req.Email))
+	if err := h.limits.Login.Allow(r.Context(), ipKey, emailKey); err != nil {
+		ratelimit.WriteError(w, r, err)
+		return
+	}
+
+	res, err := h.service.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			if err := h.limits.Login.Fail(r.Context(), ipKey, emailKey); err != nil {
+				log.Printf("AUTH: count failed login: %v", err)
+			}
+		}
 		writeDomainError(w, r, err)
 		return
 	}
 
-	cookie := &http.Cookie{
-		Name:     "access_token",
-		Value:    access,
-		Path:     "/",
-		MaxAge:   60 * 60 * 24 * 7,
-		HttpOnly: true,
+	// Only the account's count starts over; an address guessing passwords
+	// cannot clear its own by logging in to an account it owns.
+	if err := h.limits.Login.Reset(r.Context(), emailKey); err != nil {
+		log.Printf("AUTH: reset login limit: %v", err)
 	}
 
-	if config.IsProduction() {
-		cookie.Secure = true
-		cookie.Domain = ".balkantrd.com"
-		cookie.SameSite = http.SameSiteNoneMode
-	} else {
-		cookie.Secure = false
-		cookie.SameSite = http.SameSiteLaxMode
+	if res.MFAToken != "" {
+		httputil.WriteJSON(w, http.StatusOK, auth.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    res.MFAToken,
+		})
+		return
+	}
+
+	setSessionCookies(w, res.Session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loginMFA finishes a login that Login answered with an MFA challenge.
+// Wrong codes count against the login limits of the client IP and of the
+// account.
+func (h *Handler) loginMFA(w http.ResponseWriter, r *http.Request) {
+	var req auth.LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	if err := validation.V.Struct(req); err != nil {
+		httputil.WriteClientError(w, r, validation.FirstMessage(err), err)
+		return
+	}
+
+	userID, err := h.service.ParseMFAToken(req.MFAToken)
+	if err != nil {
+		writeDomainError(w, r, auth.ErrInvalidMFAToken)
+		return
+	}
+
+	var session auth.Session
+	err = h.checkMFA(r, userID, func() error {
+		var err error
+		session, err = h.service.CompleteMFALogin(r.Context(), userID, req.Code)
+		return err
+	})
+	if err != nil {
+		writeMFAError(w, r, err)
+		return
 	}
 
-	http.SetCookie(w, cookie)
+	setSessionCookies(w, session)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-//func (h *Handler) LoginWithRefresh(w http.ResponseWriter, r *http.Request) {
-//	var req auth.LoginRequest
-//	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-//		http.Error(w, "invalid json body", http.StatusBadRequest)
-//		return
-//	}
-//
-//	access, refresh, err := h.service.LoginWithRefresh(r.Context(), req.Email, req.Password, h.refreshTokenTTL)
-//	if err != nil {
-//		writeAuthError(w, err)
-//		return
-//	}
-//
-//	http.SetCookie(w, &http.Cookie{
-//		Name:     "refresh_token",
-//		Value:    refresh,
-//		Path:     "/auth/refresh",
-//		HttpOnly: true,
-//		SameSite: http.SameSiteLaxMode,
-//		Secure:   false,
-//		MaxAge:   int(h.refreshTokenTTL.Seconds()),
-//	})
-//
-//	w.Header().Set("Content-Type", "application/json")
-//	_ = json.NewEncoder(w).Encode(auth.AuthResponse{
-//		AccessToken: access,
-//	})
-//}
-//
-//func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
-//	var req auth.RefreshRequest
-//	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-//		http.Error(w, "invalid json body", http.StatusBadRequest)
-//		return
-//	}
-//
-//	access, err := h.service.RefreshAccessToken(r.Context(), req.RefreshToken)
-//	if err != nil {
-//		writeAuthError(w, err)
-//		return
-//	}
-//
-//	w.Header().Set("Content-Type", "application/json")
-//	_ = json.NewEncoder(w).Encode(auth.AuthResponse{AccessToken: access})
-//}
+// Refresh trades the refresh token cookie for a new access token and a new
+// refresh token. A failed refresh clears both cookies so the client logs in
+// again.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var token string
+	if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+		token = cookie.Value
+	}
 
+	session, err := h.service.Refresh(r.Context(), token)
+	if err != nil {
+		clearSessionCookies(w)
+		writeDomainError(w, r, err)
+		return
+	}
+
+	setSessionCookies(w, session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Logout revokes the session server-side, so a copied refresh token stops
+// working too, and clears the cookies.
 func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var token string
+	if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+		token = cookie.Value
+	}
+
+	clearSessionCookies(w)
+	if err := h.service.Logout(r.Context(), token); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setSessionCookies(w http.ResponseWriter, session auth.Session) {
+	http.SetCookie(w, newCookie(accessTokenCookie, session.AccessToken, int(time.Until(session.AccessExpiresAt).Seconds())))
+	http.SetCookie(w, newCookie(refreshTokenCookie, session.RefreshToken, int(time.Until(session.RefreshExpiresAt).Seconds())))
+}
+
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, newCookie(accessTokenCookie, "", -1))
+	http.SetCookie(w, newCookie(refreshTokenCookie, "", -1))
+}
+
+func newCookie(name, value string, maxAge int) *http.Cookie {
 	cookie := &http.Cookie{
-		Name:     "access_token",
-		Value:    "",
+		Name:     name,
+		Value:    value,
 		Path:     "/",
-		MaxAge:   -1,
+		MaxAge:   maxAge,
 		HttpOnly: true,
 	}
 
@@ -165,8 +249,7 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 		cookie.SameSite = http.SameSiteLaxMode
 	}
 
-	http.SetCookie(w, cookie)
-	w.WriteHeader(http.StatusNoContent)
+	return cookie
 }
 
 func (h *Handler) me(w http.ResponseWriter, r *http.Request) {
@@ -185,24 +268,273 @@ func (h *Handler) me(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, user)
 }
 
-func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
-	userIDStr := chi.URLParam(r, "userID")
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		http.Error(w, "invalid user id", http.StatusBadRequest)
+// requestPasswordReset always answers 202 for a valid email, registered or
+// not.
+func (h *Handler) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req auth.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	if err := validation.V.Struct(req); err != nil {
+		httputil.WriteClientError(w, r, validation.FirstMessage(err), err)
 		return
 	}
 
+	if err := h.service.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
 	var req auth.ResetPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json body", http.StatusBadRequest)
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
 		return
 	}
 
-	if err := h.service.ResetPassword(r.Context(), userID, req.NewPassword); err != nil {
+	if err := validation.V.Struct(req); err != nil {
+		httputil.WriteClientError(w, r, validation.FirstMessage(err), err)
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// verifyEmail redeems a verification link. When the browser that opens it is
+// logged in, the session is refreshed right away so the new access token
+// already carries the verified email.
+func (h *Handler) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req auth.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	if err := validation.V.Struct(req); err != nil {
+		httputil.WriteClientError(w, r, validation.FirstMessage(err), err)
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), req.Token); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+		if session, err := h.service.Refresh(r.Context(), cookie.Value); err == nil {
+			setSessionCookies(w, session)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) resendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	if err := h.service.ResendVerificationEmail(r.Context(), userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) verifyUserEmail(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid user ID", err)
+		return
+	}
+
+	if err := h.service.VerifyUserEmail(r.Context(), userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) mfaStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	status, err := h.service.MFAStatus(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, auth.MFAStatusResponse{
+		Enabled:           status.Enabled,
+		RecoveryCodesLeft: status.RecoveryCodesLeft,
+	})
+}
+
+func (h *Handler) startTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	enrollment, err := h.service.StartTOTPEnrollment(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, auth.TOTPEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	})
+}
+
+// confirmTOTPEnrollment enables two-factor authentication. It ends the
+// user's other sessions and replaces this one with a session that passed
+// the second factor.
+func (h *Handler) confirmTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	req, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	var (
+		codes   []string
+		session auth.Session
+	)
+	err := h.checkMFA(r, userID, func() error {
+		var err error
+		codes, session, err = h.service.ConfirmTOTPEnrollment(r.Context(), userID, req.Code)
+		return err
+	})
+	if err != nil {
+		writeMFAError(w, r, err)
+		return
+	}
+
+	setSessionCookies(w, session)
+	httputil.WriteJSON(w, http.StatusOK, auth.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// disableTOTP turns two-factor authentication off and ends every session of
+// the user, this one included.
+func (h *Handler) disableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	req, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.checkMFA(r, userID, func() error {
+		return h.service.DisableTOTP(r.Context(), userID, req.Code)
+	})
+	if err != nil {
+		writeMFAError(w, r, err)
+		return
+	}
+
+	clearSessionCookies(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	req, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	var codes []string
+	err := h.checkMFA(r, userID, func() error {
+		var err error
+		codes, err = h.service.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+		return err
+	})
+	if err != nil {
+		writeMFAError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, auth.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func decodeMFACode(w http.ResponseWriter, r *http.Request) (auth.MFACodeRequest, bool) {
+	var req auth.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return req, false
+	}
+
+	if err := validation.V.Struct(req); err != nil {
+		httputil.WriteClientError(w, r, validation.FirstMessage(err), err)
+		return req, false
+	}
+
+	return req, true
+}
+
+// checkMFA runs check, which verifies a second-factor code, under the login
+// limits, so codes cannot be guessed faster than passwords.
+func (h *Handler) checkMFA(r *http.Request, userID uuid.UUID, check func() error) error {
+	ipKey := ratelimit.IPKey(r)
+	userKey := "mfa:" + userID.String()
+	if err := h.limits.Login.Allow(r.Context(), ipKey, userKey); err != nil {
+		return err
+	}
+
+	if err := check(); err != nil {
+		if errors.Is(err, auth.ErrInvalidMFACode) {
+			if err := h.limits.Login.Fail(r.Context(), ipKey, userKey); err != nil {
+				log.Printf("AUTH: count failed mfa code: %v", err)
+			}
+		}
+		return err
+	}
+
+	if err := h.limits.Login.Reset(r.Context(), userKey); err != nil {
+		log.Printf("AUTH: reset mfa limit: %v", err)
+	}
+	return nil
+}
+
+func writeMFAError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ratelimit.ErrLocked) {
+		ratelimit.WriteError(w, r, err)
+		return
+	}
+	writeDomainError(w, r, err)
+}
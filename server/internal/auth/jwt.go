@@ -4,23 +4,68 @@ import (
 	"errors"
 	"github.com/filipcvejic/trading_tournament/internal/user"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"time"
 )
 
-func (s *AuthService) generateAccessToken(user user.User) (string, error) {
+// mfaTokenTTL is how long a user has to enter the second factor after the
+// password.
+const mfaTokenTTL = 5 * time.Minute
+
+// generateAccessToken signs the claims the middleware reads. mfa says the
+// session passed a second factor, which admin routes require.
+func (s *AuthService) generateAccessToken(user user.User, mfa bool) (string, time.Time, error) {
 	now := time.Now()
-	expirationTime := now.Add(24 * time.Hour)
+	expirationTime := now.Add(s.cfg.AccessTokenTTL)
 
 	claims := jwt.MapClaims{
-		"sub":   user.ID.String(),
-		"email": user.Email,
-		"role":  user.Role,
-		"exp":   expirationTime.Unix(),
-		"iat":   now.Unix(),
+		"sub":            user.ID.String(),
+		"email":          user.Email,
+		"role":           user.Role,
+		"exp":            expirationTime.Unix(),
+		"iat":            now.Unix(),
+		"email_verified": user.EmailVerified(),
+		"mfa":            mfa,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	signed, err := token.SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expirationTime, nil
+}
+
+// generateMFAToken proves the password was right while the second factor
+// is pending. It has no role claim, so it is useless as an access token.
+func (s *AuthService) generateMFAToken(userID uuid.UUID) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": userID.String(),
+		"typ": "mfa",
+		"exp": now.Add(mfaTokenTTL).Unix(),
+		"iat": now.Unix(),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+}
+
+// ParseMFAToken returns the user a token from Login is for.
+func (s *AuthService) ParseMFAToken(tokenString string) (uuid.UUID, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+	if typ, _ := claims["typ"].(string); typ != "mfa" {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+
+	sub, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+	return userID, nil
 }
 
 func (s *AuthService) ValidateToken(tokenString string) (jwt.MapClaims, error) {
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/auth/model"
+	"github.com/filipcvejic/trading_tournament/internal/crypto"
+	"github.com/filipcvejic/trading_tournament/internal/user"
+	"github.com/google/uuid"
+)
+
+// TOTPEnrollment is what the user adds to an authenticator app, either by
+// scanning URI as a QR code or by typing Secret.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type MFAStatus struct {
+	Enabled           bool
+	RecoveryCodesLeft int
+}
+
+func (s *AuthService) MFAStatus(ctx context.Context, userID uuid.UUID) (MFAStatus, error) {
+	totp, err := s.totpRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return MFAStatus{}, nil
+		}
+		return MFAStatus{}, err
+	}
+	if !totp.Enabled() {
+		return MFAStatus{}, nil
+	}
+
+	n, err := s.totpRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return MFAStatus{}, err
+	}
+	return MFAStatus{Enabled: true, RecoveryCodesLeft: n}, nil
+}
+
+// StartTOTPEnrollment generates a new secret for the user. It does not
+// protect anything until ConfirmTOTPEnrollment; starting again replaces an
+// unconfirmed secret.
+func (s *AuthService) StartTOTPEnrollment(ctx context.Context, userID uuid.UUID) (TOTPEnrollment, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return TOTPEnrollment{}, ErrUnauthorized
+		}
+		return TOTPEnrollment{}, err
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	encrypted, err := crypto.EncryptString(s.cryptoKey, secret)
+	if err != nil {
+		return TOTPEnrollment{}, fmt.Errorf("encrypt totp secret: %w", err)
+	}
+	if err := s.totpRepo.SaveEnrollment(ctx, u.ID, encrypted); err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return TOTPEnrollment{
+		Secret: secret,
+		URI:    totpURI(s.cfg.TOTPIssuer, u.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication once the user
+// enters a code from the app. Every other session of the user ends; the
+// returned session has passed the second factor. The recovery codes are
+// only ever returned here and by RegenerateRecoveryCodes.
+func (s *AuthService) ConfirmTOTPEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, Session, error) {
+	totp, err := s.totpRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, Session{}, err
+	}
+	if totp.Enabled() {
+		return nil, Session{}, ErrMFAAlreadyEnabled
+	}
+	if err := s.checkTOTP(ctx, totp, code); err != nil {
+		return nil, Session{}, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, Session{}, err
+	}
+	if err := s.totpRepo.Enable(ctx, userID, hashes); err != nil {
+		return nil, Session{}, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, Session{}, err
+	}
+	session, err := s.startSession(ctx, u, true)
+	if err != nil {
+		return nil, Session{}, err
+	}
+	return codes, session, nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking a code
+// or recovery code, and ends every session of the user.
+func (s *AuthService) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkMFACode(ctx, totp, code); err != nil {
+		return err
+	}
+
+	return s.totpRepo.Disable(ctx, userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, used or not.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMFACode(ctx, totp, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.totpRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CompleteMFALogin is the second step of Login, for the user named by the
+// token Login returned; see ParseMFAToken.
+func (s *AuthService) CompleteMFALogin(ctx context.Context, userID uuid.UUID, code string) (Session, error) {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return Session{}, ErrInvalidMFAToken
+		}
+		return Session{}, err
+	}
+	if err := s.checkMFACode(ctx, totp, code); err != nil {
+		return Session{}, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return Session{}, ErrInvalidMFAToken
+	}
+	return s.startSession(ctx, u, true)
+}
+
+func (s *AuthService) enabledTOTP(ctx context.Context, userID uuid.UUID) (model.TOTP, error) {
+	totp, err := s.totpRepo.Get(ctx, userID)
+	if err != nil {
+		return model.TOTP{}, err
+	}
+	if !totp.Enabled() {
+		return model.TOTP{}, ErrMFANotEnabled
+	}
+	return totp, nil
+}
+
+// checkMFACode accepts a code from the app or an unused recovery code,
+// which is used up.
+func (s *AuthService) checkMFACode(ctx context.Context, totp model.TOTP, code string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return s.checkTOTP(ctx, totp, code)
+	}
+
+	ok, err := s.totpRepo.UseRecoveryCode(ctx, totp.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// checkTOTP accepts each code once: a code for a step at or before the last
+// accepted one is rejected, even while it is still current.
+func (s *AuthService) checkTOTP(ctx context.Context, totp model.TOTP, code string) error {
+	secret, err := crypto.DecryptString(s.cryptoKey, totp.Secret)
+	if err != nil {
+		return fmt.Errorf("decrypt totp secret: %w", err)
+	}
+
+	step, ok := matchTOTP(secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := s.totpRepo.UseStep(ctx, totp.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
 	"github.com/filipcvejic/trading_tournament/internal/user"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -14,8 +15,10 @@ import (
 type contextKey string
 
 const (
-	UserIDKey contextKey = "userID"
-	RoleKey   contextKey = "role"
+	UserIDKey        contextKey = "userID"
+	RoleKey          contextKey = "role"
+	EmailVerifiedKey contextKey = "emailVerified"
+	MFAKey           contextKey = "mfa"
 )
 
 func AuthenticationMiddleware(next http.Handler) http.Handler {
@@ -90,13 +93,22 @@ func AuthenticationMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Tokens issued before verification existed have no claim; they
+		// count as unverified until the next refresh.
+		emailVerified, _ := claims["email_verified"].(bool)
+		mfa, _ := claims["mfa"].(bool)
+
 		ctx := context.WithValue(r.Context(), UserIDKey, userID)
 		ctx = context.WithValue(ctx, RoleKey, role)
+		ctx = context.WithValue(ctx, EmailVerifiedKey, emailVerified)
+		ctx = context.WithValue(ctx, MFAKey, mfa)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireAdmin lets through only admins whose session passed two-factor
+// authentication.
 func RequireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		role, ok := GetUserRole(r)
@@ -110,6 +122,29 @@ func RequireAdmin(next http.Handler) http.Handler {
 			return
 		}
 
+		if mfa, _ := r.Context().Value(MFAKey).(bool); !mfa {
+			httputil.WriteError(w, r, http.StatusForbidden, "Two-factor authentication is required for admin access", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireVerifiedEmail lets through only users whose access token says their
+// email is verified. It must run after AuthenticationMiddleware.
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetUserID(r); !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if verified, _ := r.Context().Value(EmailVerifiedKey).(bool); !verified {
+			httputil.WriteError(w, r, http.StatusForbidden, "Verify your email address to continue", nil)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
@@ -5,10 +5,17 @@ import (
 	"time"
 )
 
+// RefreshToken is one link in a chain of rotated tokens. Every token issued
+// from the same login shares a FamilyID; only its SHA-256 hash is stored.
+// MFA records whether that login passed a second factor.
 type RefreshToken struct {
-	Token     string
-	UserID    uuid.UUID
-	ExpiresAt time.Time
-	Revoked   bool
-	CreatedAt time.Time
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	ExpiresAt  time.Time
+	Revoked    bool
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *uuid.UUID
+	MFA        bool
 }
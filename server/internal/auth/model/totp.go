@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TOTP is a user's authenticator app enrollment. It only protects logins
+// once EnabledAt is set, after the user has proven the app works by
+// entering a code. Secret is encrypted.
+type TOTP struct {
+	UserID       uuid.UUID
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+func (t TOTP) Enabled() bool {
+	return t.EnabledAt != nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/mailer"
+	"github.com/filipcvejic/trading_tournament/internal/user"
+)
+
+const mailTimeout = 30 * time.Second
+
+// RequestPasswordReset emails a single-use reset link to the account with
+// this email. It succeeds whether or not the address is registered, and the
+// email goes out in the background, so the response does not reveal which
+// addresses have accounts.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	if email == "" {
+		return ErrInvalidInput
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := generateTokenString(32)
+	if err != nil {
+		return err
+	}
+	if err := s.passwordResetRepo.Create(ctx, u.ID, hashToken(token), time.Now().Add(s.cfg.PasswordResetTTL)); err != nil {
+		return err
+	}
+
+	link := s.cfg.AppURL + "/reset-password?token=" + url.QueryEscape(token)
+	s.sendInBackground(mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open this link to choose a new one:\n\n%s\n\n"+
+				"The link works once and expires in %s. If you did not ask for this, ignore this email; your password has not changed.\n",
+			u.Username, link, formatTTL(s.cfg.PasswordResetTTL),
+		),
+	})
+	return nil
+}
+
+// ResetPassword redeems a reset token. The new password replaces the old one
+// and every session of the user is revoked; access tokens already issued
+// expire on their own shortly after.
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	if newPassword == "" {
+		return ErrInvalidInput
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.passwordResetRepo.Redeem(ctx, hashToken(token), hash)
+	return err
+}
+
+func (s *AuthService) sendInBackground(msg mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("MAIL: send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+// formatTTL renders durations like 1h or 30m the way they read in an email.
+func formatTTL(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		if h := int(d / time.Hour); h != 1 {
+			return fmt.Sprintf("%d hours", h)
+		}
+		return "1 hour"
+	case d >= time.Minute && d%time.Minute == 0:
+		if m := int(d / time.Minute); m != 1 {
+			return fmt.Sprintf("%d minutes", m)
+		}
+		return "1 minute"
+	default:
+		return d.String()
+	}
+}
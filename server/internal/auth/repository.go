@@ -3,8 +3,12 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/filipcvejic/trading_tournament/db"
 	"github.com/filipcvejic/trading_tournament/db/sqlc"
 	"github.com/filipcvejic/trading_tournament/internal/auth/model"
@@ -13,9 +17,9 @@ import (
 )
 
 type RefreshTokenRepository interface {
-	Create(ctx context.Context, userID uuid.UUID, ttl time.Duration) (model.RefreshToken, error)
-	Get(ctx context.Context, token string) (model.RefreshToken, error)
-	Revoke(ctx context.Context, token string) error
+	Create(ctx context.Context, userID, familyID uuid.UUID, tokenHash string, expiresAt time.Time, mfa bool) (model.RefreshToken, error)
+	Rotate(ctx context.Context, tokenHash, newTokenHash string, expiresAt time.Time) (model.RefreshToken, error)
+	RevokeFamily(ctx context.Context, tokenHash string) error
 }
 
 type PostgresRefreshTokenRepository struct {
@@ -26,51 +30,430 @@ func NewPostgresRefreshTokenRepository(database *db.DB) *PostgresRefreshTokenRep
 	return &PostgresRefreshTokenRepository{db: database}
 }
 
-func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, userID uuid.UUID, ttl time.Duration) (model.RefreshToken, error) {
-	token, err := generateRefreshTokenString(32)
+func (r *PostgresRefreshTokenRepository) Create(
+	ctx context.Context,
+	userID uuid.UUID,
+	familyID uuid.UUID,
+	tokenHash string,
+	expiresAt time.Time,
+	mfa bool,
+) (model.RefreshToken, error) {
+	row, err := r.db.Query.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+		Mfa:       mfa,
+	})
+	if err != nil {
+		return model.RefreshToken{}, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	return refreshTokenFromDB(row), nil
+}
+
+// rotationGrace is how long a rotated refresh token can still be exchanged.
+// Tabs, prefetches and the server-side proxy that refresh at the same moment
+// all present the same token; only the first one rotates it, and the others
+// must not be mistaken for a thief.
+const rotationGrace = 10 * time.Second
+
+// Rotate exchanges a live refresh token for a new one in the same family.
+// Presenting a token that was already rotated or revoked means it leaked, so
+// the whole family is revoked and ErrRefreshTokenReused returned, unless the
+// token was rotated within rotationGrace and its family is still live: then
+// the request lost a race with another refresh and gets a token of its own.
+func (r *PostgresRefreshTokenRepository) Rotate(
+	ctx context.Context,
+	tokenHash string,
+	newTokenHash string,
+	expiresAt time.Time,
+) (model.RefreshToken, error) {
+	var (
+		next   model.RefreshToken
+		reused bool
+	)
+
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		current, err := q.GetRefreshTokenForUpdate(ctx, tokenHash)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("get refresh token: %w", err)
+		}
+
+		if current.Revoked {
+			raced, err := rotatedConcurrently(ctx, q, current)
+			if err != nil {
+				return err
+			}
+			if !raced {
+				// Returning nil commits the revocation; the error is
+				// reported once the transaction is done.
+				reused = true
+				if _, err := q.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+					return fmt.Errorf("revoke refresh token family: %w", err)
+				}
+				return nil
+			}
+		}
+		if time.Now().After(current.ExpiresAt) {
+			return ErrExpiredToken
+		}
+
+		row, err := q.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+			TokenHash: newTokenHash,
+			UserID:    current.UserID,
+			FamilyID:  current.FamilyID,
+			ExpiresAt: expiresAt,
+			Mfa:       current.Mfa,
+		})
+		if err != nil {
+			return fmt.Errorf("create refresh token: %w", err)
+		}
+
+		// A token that lost a race keeps pointing at the successor it was
+		// first rotated to.
+		if !current.Revoked {
+			if err := q.ReplaceRefreshToken(ctx, sqlc.ReplaceRefreshTokenParams{
+				ReplacedBy: &row.ID,
+				ID:         current.ID,
+			}); err != nil {
+				return fmt.Errorf("replace refresh token: %w", err)
+			}
+		}
+
+		next = refreshTokenFromDB(row)
+		return nil
+	})
 	if err != nil {
 		return model.RefreshToken{}, err
 	}
+	if reused {
+		return model.RefreshToken{}, ErrRefreshTokenReused
+	}
+	return next, nil
+}
 
-	expiresAt := time.Now().Add(ttl)
+// rotatedConcurrently reports whether t was rotated, not revoked, within
+// rotationGrace and its family has not been revoked since, by a logout or
+// an earlier reuse.
+func rotatedConcurrently(ctx context.Context, q *sqlc.Queries, t sqlc.RefreshToken) (bool, error) {
+	if t.ReplacedBy == nil || t.RevokedAt == nil || time.Since(*t.RevokedAt) > rotationGrace {
+		return false, nil
+	}
+	live, err := q.HasLiveRefreshToken(ctx, t.FamilyID)
+	if err != nil {
+		return false, fmt.Errorf("check refresh token family: %w", err)
+	}
+	return live, nil
+}
 
-	row, err := r.db.Query.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
-		Token:     token,
+// RevokeFamily ends the session the token belongs to. Unknown tokens are
+// ignored.
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, tokenHash string) error {
+	if _, err := r.db.Query.RevokeRefreshTokenFamilyByHash(ctx, tokenHash); err != nil {
+		return fmt.Errorf("revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	Redeem(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+}
+
+type PostgresPasswordResetRepository struct {
+	db *db.DB
+}
+
+func NewPostgresPasswordResetRepository(database *db.DB) *PostgresPasswordResetRepository {
+	return &PostgresPasswordResetRepository{db: database}
+}
+
+// Create stores a new reset token and invalidates any earlier one, so only
+// the most recent email works.
+func (r *PostgresPasswordResetRepository) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.InvalidatePasswordResetTokens(ctx, userID); err != nil {
+			return fmt.Errorf("invalidate password reset tokens: %w", err)
+		}
+		if err := q.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+			UserID:    userID,
+			TokenHash: tokenHash,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return fmt.Errorf("create password reset token: %w", err)
+		}
+		return nil
+	})
+}
+
+// Redeem uses up the token, sets the new password hash and revokes every
+// refresh token of the user, all or nothing.
+func (r *PostgresPasswordResetRepository) Redeem(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		id, err := q.ConsumePasswordResetToken(ctx, tokenHash)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidResetToken
+			}
+			return fmt.Errorf("consume password reset token: %w", err)
+		}
+
+		if err := q.UpdatePasswordHash(ctx, sqlc.UpdatePasswordHashParams{
+			ID:           id,
+			PasswordHash: passwordHash,
+		}); err != nil {
+			return fmt.Errorf("update password hash: %w", err)
+		}
+		if _, err := q.RevokeUserRefreshTokens(ctx, id); err != nil {
+			return fmt.Errorf("revoke refresh tokens: %w", err)
+		}
+		if err := q.InvalidatePasswordResetTokens(ctx, id); err != nil {
+			return fmt.Errorf("invalidate password reset tokens: %w", err)
+		}
+
+		userID = id
+		return nil
+	})
+	return userID, err
+}
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// SentSince lists when tokens were issued to the user after since,
+	// newest first.
+	SentSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+	Redeem(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+type PostgresEmailVerificationRepository struct {
+	db *db.DB
+}
+
+func NewPostgresEmailVerificationRepository(database *db.DB) *PostgresEmailVerificationRepository {
+	return &PostgresEmailVerificationRepository{db: database}
+}
+
+// Create stores a new verification token and invalidates any earlier one, so
+// only the most recent email works.
+func (r *PostgresEmailVerificationRepository) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.InvalidateEmailVerificationTokens(ctx, userID); err != nil {
+			return fmt.Errorf("invalidate email verification tokens: %w", err)
+		}
+		if err := q.CreateEmailVerificationToken(ctx, sqlc.CreateEmailVerificationTokenParams{
+			UserID:    userID,
+			TokenHash: tokenHash,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return fmt.Errorf("create email verification token: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresEmailVerificationRepository) SentSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	times, err := r.db.Query.ListEmailVerificationTokenTimes(ctx, sqlc.ListEmailVerificationTokenTimesParams{
 		UserID:    userID,
-		ExpiresAt: expiresAt,
+		CreatedAt: since,
 	})
 	if err != nil {
-		return model.RefreshToken{}, err
+		return nil, fmt.Errorf("list email verification tokens: %w", err)
 	}
+	return times, nil
+}
 
-	return model.RefreshToken{
-		Token:     row.Token,
-		UserID:    row.UserID,
-		ExpiresAt: row.ExpiresAt,
-		Revoked:   row.Revoked,
-		CreatedAt: row.CreatedAt,
-	}, nil
+// Redeem uses up the token and marks the user's email verified.
+func (r *PostgresEmailVerificationRepository) Redeem(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		id, err := q.ConsumeEmailVerificationToken(ctx, tokenHash)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidVerificationToken
+			}
+			return fmt.Errorf("consume email verification token: %w", err)
+		}
+
+		if _, err := q.MarkEmailVerified(ctx, id); err != nil {
+			return fmt.Errorf("mark email verified: %w", err)
+		}
+		if err := q.InvalidateEmailVerificationTokens(ctx, id); err != nil {
+			return fmt.Errorf("invalidate email verification tokens: %w", err)
+		}
+
+		userID = id
+		return nil
+	})
+	return userID, err
+}
+
+type TOTPRepository interface {
+	// Get fails with ErrMFANotEnabled when the user never started enrolling.
+	Get(ctx context.Context, userID uuid.UUID) (model.TOTP, error)
+	// SaveEnrollment stores a new, not yet enabled secret, replacing an
+	// unfinished enrollment.
+	SaveEnrollment(ctx context.Context, userID uuid.UUID, encryptedSecret string) error
+	// UseStep reports false when a code for this or a later step was
+	// accepted before.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	Enable(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error
+	// UseRecoveryCode reports false for unknown and already used codes.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+type PostgresTOTPRepository struct {
+	db *db.DB
 }
 
-func (r *PostgresRefreshTokenRepository) Get(ctx context.Context, token string) (model.RefreshToken, error) {
-	row, err := r.db.Query.GetRefreshToken(ctx, token)
+func NewPostgresTOTPRepository(database *db.DB) *PostgresTOTPRepository {
+	return &PostgresTOTPRepository{db: database}
+}
+
+func (r *PostgresTOTPRepository) Get(ctx context.Context, userID uuid.UUID) (model.TOTP, error) {
+	row, err := r.db.Query.GetTOTP(ctx, userID)
 	if err != nil {
-		return model.RefreshToken{}, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TOTP{}, ErrMFANotEnabled
+		}
+		return model.TOTP{}, fmt.Errorf("get totp: %w", err)
 	}
 
-	return model.RefreshToken{
-		Token:     row.Token,
-		UserID:    row.UserID,
-		ExpiresAt: row.ExpiresAt,
-		Revoked:   row.Revoked,
-		CreatedAt: row.CreatedAt,
+	return model.TOTP{
+		UserID:       row.UserID,
+		Secret:       row.Secret,
+		EnabledAt:    row.EnabledAt,
+		LastUsedStep: row.LastUsedStep,
+		CreatedAt:    row.CreatedAt,
 	}, nil
 }
 
-func (r *PostgresRefreshTokenRepository) Revoke(ctx context.Context, token string) error {
-	return r.db.Query.RevokeRefreshToken(ctx, token)
+func (r *PostgresTOTPRepository) SaveEnrollment(ctx context.Context, userID uuid.UUID, encryptedSecret string) error {
+	n, err := r.db.Query.SaveTOTPEnrollment(ctx, sqlc.SaveTOTPEnrollmentParams{
+		UserID: userID,
+		Secret: encryptedSecret,
+	})
+	if err != nil {
+		return fmt.Errorf("save totp enrollment: %w", err)
+	}
+	if n == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *PostgresTOTPRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	n, err := r.db.Query.UseTOTPStep(ctx, sqlc.UseTOTPStepParams{
+		Step:   step,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("use totp step: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Enable turns the enrollment on with a fresh set of recovery codes and
+// ends every session of the user, all or nothing; sessions started before
+// have not passed the second factor.
+func (r *PostgresTOTPRepository) Enable(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.EnableTOTP(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("enable totp: %w", err)
+		}
+		if n == 0 {
+			return ErrMFAAlreadyEnabled
+		}
+		if err := replaceRecoveryCodes(ctx, q, userID, recoveryCodeHashes); err != nil {
+			return err
+		}
+		if _, err := q.RevokeUserRefreshTokens(ctx, userID); err != nil {
+			return fmt.Errorf("revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
 }
-func generateRefreshTokenString(nBytes int) (string, error) {
+
+// Disable removes the enrollment and its recovery codes and ends every
+// session of the user.
+func (r *PostgresTOTPRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeleteTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("delete totp: %w", err)
+		}
+		if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+		if _, err := q.RevokeUserRefreshTokens(ctx, userID); err != nil {
+			return fmt.Errorf("revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresTOTPRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return replaceRecoveryCodes(ctx, q, userID, hashes)
+	})
+}
+
+func (r *PostgresTOTPRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	n, err := r.db.Query.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hash,
+	})
+	if err != nil {
+		return false, fmt.Errorf("use recovery code: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *PostgresTOTPRepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	n, err := r.db.Query.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return int(n), nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, hashes []string) error {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	for _, h := range hashes {
+		if err := q.CreateRecoveryCode(ctx, sqlc.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: h,
+		}); err != nil {
+			return fmt.Errorf("create recovery code: %w", err)
+		}
+	}
+	return nil
+}
+
+func refreshTokenFromDB(row sqlc.RefreshToken) model.RefreshToken {
+	return model.RefreshToken{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		FamilyID:   row.FamilyID,
+		ExpiresAt:  row.ExpiresAt,
+		Revoked:    row.Revoked,
+		CreatedAt:  row.CreatedAt,
+		RevokedAt:  row.RevokedAt,
+		ReplacedBy: row.ReplacedBy,
+		MFA:        row.Mfa,
+	}
+}
+
+func generateTokenString(nBytes int) (string, error) {
 	if nBytes < 16 {
 		return "", errors.New("refresh token length too small")
 	}
@@ -83,3 +466,11 @@ func generateRefreshTokenString(nBytes int) (string, error) {
 	// URL-safe, bez padding-a
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
+
+// hashToken is what gets stored for refresh, reset and verification tokens
+// and for recovery codes. Tokens are 256 random bits and recovery codes 80,
+// so a plain SHA-256 is enough; there is nothing to brute-force.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/testdb"
+	"github.com/google/uuid"
+)
+
+// newRefreshTokenRepository returns a repository over a fresh database with
+// one session, whose refresh token hash is returned too.
+func newRefreshTokenRepository(t *testing.T) (*PostgresRefreshTokenRepository, string) {
+	t.Helper()
+	database := testdb.New(t)
+	ctx := context.Background()
+
+	var userID uuid.UUID
+	err := database.Pool.QueryRow(ctx, `
+INSERT INTO users (email, username, discord_username, password_hash)
+VALUES ('trader@example.com', 'trader', 'trader', 'hash')
+RETURNING id`).Scan(&userID)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	repo := NewPostgresRefreshTokenRepository(database)
+	if _, err := repo.Create(ctx, userID, uuid.New(), "token-0", time.Now().Add(time.Hour), false); err != nil {
+		t.Fatalf("create refresh token: %v", err)
+	}
+	return repo, "token-0"
+}
+
+func TestRotateConcurrentRefresh(t *testing.T) {
+	repo, token := newRefreshTokenRepository(t)
+	ctx := context.Background()
+
+	// Two tabs and the server-side proxy refresh with the same token at once.
+	const refreshes = 3
+	var wg sync.WaitGroup
+	errs := make([]error, refreshes)
+	for i := range refreshes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = repo.Rotate(ctx, token, fmt.Sprintf("token-1-%d", i), time.Now().Add(time.Hour))
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("refresh %d: %v", i, err)
+		}
+	}
+	for i := range refreshes {
+		if _, err := repo.Rotate(ctx, fmt.Sprintf("token-1-%d", i), fmt.Sprintf("token-2-%d", i), time.Now().Add(time.Hour)); err != nil {
+			t.Errorf("rotate successor %d: %v", i, err)
+		}
+	}
+}
+
+func TestRotateReuseAfterGrace(t *testing.T) {
+	repo, token := newRefreshTokenRepository(t)
+	ctx := context.Background()
+
+	if _, err := repo.Rotate(ctx, token, "token-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	// Well past rotationGrace.
+	_, err := repo.db.Pool.Exec(ctx, `
+UPDATE refresh_tokens
+SET revoked_at = revoked_at - INTERVAL '1 minute'
+WHERE token_hash = $1`, token)
+	if err != nil {
+		t.Fatalf("age rotation: %v", err)
+	}
+
+	if _, err := repo.Rotate(ctx, token, "token-2", time.Now().Add(time.Hour)); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuse after grace: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	// The reuse ended the session.
+	if _, err := repo.Rotate(ctx, "token-1", "token-3", time.Now().Add(time.Hour)); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("rotate successor: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+}
+
+func TestRotateAfterLogout(t *testing.T) {
+	repo, token := newRefreshTokenRepository(t)
+	ctx := context.Background()
+
+	if _, err := repo.Rotate(ctx, token, "token-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := repo.RevokeFamily(ctx, "token-1"); err != nil {
+		t.Fatalf("revoke family: %v", err)
+	}
+
+	// Still within the grace period, but the session is over.
+	if _, err := repo.Rotate(ctx, token, "token-2", time.Now().Add(time.Hour)); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("rotate after logout: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+}
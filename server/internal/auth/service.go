@@ -2,29 +2,88 @@ package auth
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"github.com/filipcvejic/trading_tournament/internal/crypto"
+	"github.com/filipcvejic/trading_tournament/internal/mailer"
 	"github.com/filipcvejic/trading_tournament/internal/user"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"log"
 	"time"
 )
 
 type AuthService struct {
-	userRepo user.Repository
-	//refreshTokenRepo RefreshTokenRepository
-	jwtSecret      []byte
-	accessTokenTTL time.Duration
+	userRepo              user.Repository
+	refreshTokenRepo      RefreshTokenRepository
+	passwordResetRepo     PasswordResetRepository
+	emailVerificationRepo EmailVerificationRepository
+	totpRepo              TOTPRepository
+	mailer                mailer.Mailer
+	jwtSecret             []byte
+	cryptoKey             []byte
+	cfg                   Config
 }
 
-func NewAuthService(userRepo user.Repository, refreshTokenRepo RefreshTokenRepository, jwtSecret string, accessTokenTTL time.Duration) *AuthService {
-	return &AuthService{
-		userRepo: userRepo,
-		//refreshTokenRepo: refreshTokenRepo,
-		jwtSecret: []byte(jwtSecret),
-		//accessTokenTTL:   accessTokenTTL,
+type Config struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// PasswordResetTTL is how long an emailed reset link stays valid.
+	PasswordResetTTL time.Duration
+	// EmailVerificationTTL is how long an emailed verification link stays
+	// valid.
+	EmailVerificationTTL time.Duration
+	// AppURL is the client's base URL, used to build links in emails.
+	AppURL string
+	// CryptoKey is the base64 AES-256 key TOTP secrets are encrypted with.
+	CryptoKey string
+	// TOTPIssuer names the site in authenticator apps.
+	TOTPIssuer string
+}
+
+// Session is what a login or refresh hands to the client: a short-lived
+// access token and the refresh token to get the next one with.
+type Session struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+func NewAuthService(
+	userRepo user.Repository,
+	refreshTokenRepo RefreshTokenRepository,
+	passwordResetRepo PasswordResetRepository,
+	emailVerificationRepo EmailVerificationRepository,
+	totpRepo TOTPRepository,
+	mailer mailer.Mailer,
+	cfg Config,
+) (*AuthService, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.CryptoKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode crypto key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, crypto.ErrInvalidKeyLength
 	}
+
+	return &AuthService{
+		userRepo:              userRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		passwordResetRepo:     passwordResetRepo,
+		emailVerificationRepo: emailVerificationRepo,
+		totpRepo:              totpRepo,
+		mailer:                mailer,
+		jwtSecret:             []byte(cfg.JWTSecret),
+		cryptoKey:             key,
+		cfg:                   cfg,
+	}, nil
 }
 
+// Register creates an account with an unverified email and sends the link
+// to verify it.
 func (s *AuthService) Register(ctx context.Context, email, username, discordUsername, password string) (*user.User, error) {
 	if email == "" || username == "" || discordUsername == "" || password == "" {
 		return nil, ErrInvalidInput
@@ -37,6 +96,10 @@ func (s *AuthService) Register(ctx context.Context, email, username, discordUser
 
 	u, err := s.userRepo.Create(ctx, email, username, discordUsername, hashedPassword)
 	if err == nil {
+		// The account exists either way; a failed email can be resent.
+		if err := s.sendVerificationEmail(ctx, u); err != nil {
+			log.Printf("AUTH: verification email for %s: %v", u.Email, err)
+		}
 		return &u, nil
 	}
 
@@ -55,96 +118,110 @@ func (s *AuthService) Register(ctx context.Context, email, username, discordUser
 	return nil, err
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// LoginResult is either a session or, for users with two-factor
+// authentication, the token to finish logging in with at CompleteMFALogin.
+type LoginResult struct {
+	Session  Session
+	MFAToken string
+}
+
+// Login checks the credentials and starts a new session, with a refresh
+// token family of its own, unless the user has to enter a second factor
+// first.
+func (s *AuthService) Login(ctx context.Context, email, password string) (LoginResult, error) {
 	if email == "" || password == "" {
-		return "", ErrInvalidInput
+		return LoginResult{}, ErrInvalidInput
 	}
 
 	user, err := s.userRepo.GetByEmail(ctx, email)
 
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return LoginResult{}, ErrInvalidCredentials
 	}
 
 	if err := VerifyPassword(user.PasswordHash, password); err != nil {
-		return "", ErrInvalidCredentials
+		return LoginResult{}, ErrInvalidCredentials
 	}
 
-	return s.generateAccessToken(user)
+	totp, err := s.totpRepo.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, ErrMFANotEnabled) {
+		return LoginResult{}, err
+	}
+	if err == nil && totp.Enabled() {
+		token, err := s.generateMFAToken(user.ID)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{MFAToken: token}, nil
+	}
+
+	session, err := s.startSession(ctx, user, false)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{Session: session}, nil
 }
 
-func (s *AuthService) ResetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
-	if userID == uuid.Nil {
-		return ErrInvalidToken
+// Refresh rotates the refresh token and issues a new access token. Each
+// refresh token works once; see RefreshTokenRepository.Rotate.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (Session, error) {
+	if refreshToken == "" {
+		return Session{}, ErrInvalidToken
 	}
 
-	if newPassword == "" {
-		return ErrInvalidInput
+	next, err := generateTokenString(32)
+	if err != nil {
+		return Session{}, err
+	}
+	token, err := s.refreshTokenRepo.Rotate(ctx, hashToken(refreshToken), hashToken(next), time.Now().Add(s.cfg.RefreshTokenTTL))
+	if err != nil {
+		return Session{}, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return Session{}, ErrInvalidToken
+	}
+
+	return s.newSession(user, next, token.ExpiresAt, token.MFA)
+}
+
+// Logout revokes the session the refresh token belongs to, so neither it
+// nor any token rotated from it can be used again.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
 	}
+	return s.refreshTokenRepo.RevokeFamily(ctx, hashToken(refreshToken))
+}
 
-	hash, err := HashPassword(newPassword)
+// startSession begins a new refresh token family for the user.
+func (s *AuthService) startSession(ctx context.Context, user user.User, mfa bool) (Session, error) {
+	refresh, err := generateTokenString(32)
 	if err != nil {
-		return err
+		return Session{}, err
+	}
+	token, err := s.refreshTokenRepo.Create(ctx, user.ID, uuid.New(), hashToken(refresh), time.Now().Add(s.cfg.RefreshTokenTTL), mfa)
+	if err != nil {
+		return Session{}, err
 	}
 
-	return s.userRepo.UpdatePasswordHash(ctx, userID, hash)
+	return s.newSession(user, refresh, token.ExpiresAt, mfa)
 }
 
-//func (s *AuthService) LoginWithRefresh(ctx context.Context, email, password string, refreshTokenTTL time.Duration) (accessToken string, refreshToken string, err error) {
-//	if email == "" || password == "" {
-//		return "", "", ErrInvalidInput
-//	}
-//
-//	user, err := s.userRepo.GetByEmail(ctx, email)
-//	if err != nil {
-//		return "", "", ErrInvalidCredentials
-//	}
-//
-//	if err := VerifyPassword(user.PasswordHash, password); err != nil {
-//		return "", "", ErrInvalidCredentials
-//	}
-//
-//	accessToken, err = s.generateAccessToken(user)
-//	if err != nil {
-//		return "", "", err
-//	}
-//
-//	token, err := s.refreshTokenRepo.Create(ctx, user.ID, refreshTokenTTL)
-//	if err != nil {
-//		return "", "", err
-//	}
-//
-//	return accessToken, token.Token, nil
-//}
-//
-//func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshTokenString string) (string, error) {
-//	if refreshTokenString == "" {
-//		return "", ErrInvalidInput
-//	}
-//
-//	rt, err := s.refreshTokenRepo.Get(ctx, refreshTokenString)
-//	if err != nil {
-//		if errors.Is(err, sql.ErrNoRows) {
-//			return "", ErrInvalidToken
-//		}
-//		return "", err
-//	}
-//
-//	if rt.Revoked {
-//		return "", ErrInvalidToken
-//	}
-//
-//	if time.Now().After(rt.ExpiresAt) {
-//		return "", ErrExpiredToken
-//	}
-//
-//	user, err := s.userRepo.GetByID(ctx, rt.UserID)
-//	if err != nil {
-//		return "", err
-//	}
-//
-//	return s.generateAccessToken(user)
-//}
+func (s *AuthService) newSession(user user.User, refreshToken string, refreshExpiresAt time.Time, mfa bool) (Session, error) {
+	access, accessExpiresAt, err := s.generateAccessToken(user, mfa)
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
 
 func (s *AuthService) Me(ctx context.Context, userID uuid.UUID) (*UserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -153,7 +230,8 @@ func (s *AuthService) Me(ctx context.Context, userID uuid.UUID) (*UserResponse,
 	}
 
 	return &UserResponse{
-		ID:    user.ID,
-		Email: user.Email,
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified(),
 	}, nil
 }
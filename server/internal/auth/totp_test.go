@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/auth/model"
+	"github.com/filipcvejic/trading_tournament/internal/crypto"
+	"github.com/google/uuid"
+)
+
+// rfcSecret is the shared secret of the RFC 4226 and RFC 6238 SHA-1 test
+// vectors, "12345678901234567890", base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC4226(t *testing.T) {
+	// RFC 4226 Appendix D.
+	want := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	key := []byte("12345678901234567890")
+	for counter, code := range want {
+		if got := totpCode(key, int64(counter)); got != code {
+			t.Errorf("counter %d: code = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// RFC 6238 Appendix B, SHA-1. The RFC lists eight digits; six-digit
+	// codes are the last six of them.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	key := []byte("12345678901234567890")
+	for _, tt := range tests {
+		want := tt.want[len(tt.want)-totpDigits:]
+		if got := totpCode(key, totpStep(time.Unix(tt.unix, 0))); got != want {
+			t.Errorf("t=%d: code = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+	// 1111111110 is the first second of step 37037037.
+	const step = 37037037
+	stepStart := time.Unix(step*totpPeriod, 0)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: totpCode(key, step), now: stepStart, wantStep: step, wantOK: true},
+		{name: "last second of the step", secret: rfcSecret, code: totpCode(key, step), now: stepStart.Add(totpPeriod*time.Second - time.Second), wantStep: step, wantOK: true},
+		{name: "previous step", secret: rfcSecret, code: totpCode(key, step-1), now: stepStart, wantStep: step - 1, wantOK: true},
+		{name: "next step", secret: rfcSecret, code: totpCode(key, step+1), now: stepStart, wantStep: step + 1, wantOK: true},
+		{name: "two steps old", secret: rfcSecret, code: totpCode(key, step-2), now: stepStart},
+		{name: "two steps ahead", secret: rfcSecret, code: totpCode(key, step+2), now: stepStart},
+		{name: "previous step just expired", secret: rfcSecret, code: totpCode(key, step-1), now: stepStart.Add(totpPeriod * time.Second)},
+		{name: "wrong code", secret: rfcSecret, code: "000000", now: stepStart},
+		{name: "invalid secret", secret: "not base32!", code: totpCode(key, step), now: stepStart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := matchTOTP(tt.secret, tt.code, tt.now)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("matchTOTP = (%d, %v), want (%d, %v)", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// memoryTOTPRepo keeps one user's enrollment and recovery codes with the
+// same semantics as the SQL.
+type memoryTOTPRepo struct {
+	TOTPRepository
+
+	lastUsedStep  int64
+	recoveryCodes map[string]bool // hash -> used
+}
+
+func (r *memoryTOTPRepo) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	if step <= r.lastUsedStep {
+		return false, nil
+	}
+	r.lastUsedStep = step
+	return true, nil
+}
+
+func (r *memoryTOTPRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	used, ok := r.recoveryCodes[hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[hash] = true
+	return true, nil
+}
+
+func newMFATestService(t *testing.T) (*AuthService, model.TOTP, []string) {
+	t.Helper()
+
+	key := make([]byte, 32)
+	secret, err := crypto.EncryptString(key, rfcSecret)
+	if err != nil {
+		t.Fatalf("encrypt secret: %v", err)
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatalf("new recovery codes: %v", err)
+	}
+
+	repo := &memoryTOTPRepo{recoveryCodes: make(map[string]bool)}
+	for _, h := range hashes {
+		repo.recoveryCodes[h] = false
+	}
+	s := &AuthService{totpRepo: repo, cryptoKey: key}
+	return s, model.TOTP{UserID: uuid.New(), Secret: secret}, codes
+}
+
+func TestCheckMFACodeRejectsReusedStep(t *testing.T) {
+	s, totp, _ := newMFATestService(t)
+	ctx := context.Background()
+
+	key := []byte("12345678901234567890")
+	current := totpStep(time.Now())
+
+	if err := s.checkMFACode(ctx, totp, totpCode(key, current)); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := s.checkMFACode(ctx, totp, totpCode(key, current)); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("same code again: err = %v, want %v", err, ErrInvalidMFACode)
+	}
+	// Still inside the window, but older than the code just accepted.
+	if err := s.checkMFACode(ctx, totp, totpCode(key, current-1)); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("earlier step: err = %v, want %v", err, ErrInvalidMFACode)
+	}
+	if err := s.checkMFACode(ctx, totp, totpCode(key, current+1)); err != nil {
+		t.Errorf("next step: %v", err)
+	}
+}
+
+func TestCheckMFACodeRecoveryCodeOnce(t *testing.T) {
+	s, totp, codes := newMFATestService(t)
+	ctx := context.Background()
+
+	// Typed in capitals and without dashes.
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	if err := s.checkMFACode(ctx, totp, typed); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := s.checkMFACode(ctx, totp, codes[0]); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("second use: err = %v, want %v", err, ErrInvalidMFACode)
+	}
+	if err := s.checkMFACode(ctx, totp, codes[1]); err != nil {
+		t.Errorf("another code: %v", err)
+	}
+	if err := s.checkMFACode(ctx, totp, "aaaa-bbbb-cccc-dddd"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("unknown code: err = %v, want %v", err, ErrInvalidMFACode)
+	}
+}
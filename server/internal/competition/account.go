@@ -0,0 +1,68 @@
+package competition
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// RecordAccountSnapshot stores the collector's view of a member's account.
+// The earliest snapshot taken at or after the competition start sets the
+// member's account size from its balance, unless the size was set by hand.
+// It reports whether the account size was changed.
+func (s *Service) RecordAccountSnapshot(ctx context.Context, competitionID uuid.UUID, login int64, snapshot model.AccountSnapshot) (bool, error) {
+	if competitionID == uuid.Nil {
+		return false, ErrNotFound
+	}
+	if login <= 0 {
+		return false, ErrInvalidLogin
+	}
+	snapshot.Currency = strings.ToUpper(strings.TrimSpace(snapshot.Currency))
+	if snapshot.Balance <= 0 || snapshot.Leverage <= 0 || snapshot.Currency == "" || len(snapshot.Currency) > 10 {
+		return false, ErrInvalidAccountSnapshot
+	}
+	if snapshot.TakenAt.IsZero() {
+		snapshot.TakenAt = time.Now()
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return false, err
+	}
+	if c.Status == model.StatusFinalized || c.Status == model.StatusArchived {
+		return false, ErrCompetitionClosed
+	}
+
+	if err := s.repo.RecordAccountSnapshot(ctx, competitionID, login, snapshot); err != nil {
+		return false, err
+	}
+	if snapshot.TakenAt.Before(c.StartsAt) {
+		return false, nil
+	}
+
+	applied, err := s.repo.SetAccountSize(ctx, competitionID, login, model.AccountSizeChange{
+		Size:    snapshot.Balance,
+		Source:  model.AccountSizeFromSnapshot,
+		TakenAt: &snapshot.TakenAt,
+		Reason:  "first account snapshot after the start",
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if applied {
+		s.publishLeaderboardChange(ctx, competitionID)
+	}
+	return applied, nil
+}
+
+func (s *Service) ListAccountSizeAudit(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.AccountSizeAuditEntry, error) {
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListAccountSizeAudit(ctx, competitionID, login)
+}
@@ -0,0 +1,298 @@
+package competition
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// RuleCoordinatedTrading marks disqualifications an admin issued from the
+// cheat case queue.
+const RuleCoordinatedTrading = "coordinated_trading"
+
+type CheatDetectionConfig struct {
+	// Window is how far apart two entries may be opened and still count as
+	// the same trade.
+	Window time.Duration
+	// VolumeTolerance is the relative volume difference, against the larger
+	// volume, at which two matched trades still count as similar.
+	VolumeTolerance float64
+	// A pair becomes a case once it has at least MinMatches matched trades
+	// and they cover at least MinRatio of the less active account's trades.
+	MinMatches int
+	MinRatio   float64
+}
+
+var DefaultCheatDetectionConfig = CheatDetectionConfig{
+	Window:          10 * time.Second,
+	VolumeTolerance: 0.1,
+	MinMatches:      5,
+	MinRatio:        0.5,
+}
+
+// ScanForCheating runs the detector over the competition's trades and
+// records every suspicious pair. Cases an admin has already resolved are
+// left as they are.
+func (s *Service) ScanForCheating(ctx context.Context, competitionID uuid.UUID, cfg CheatDetectionConfig) (int, error) {
+	trades, err := s.repo.ListTrades(ctx, competitionID)
+	if err != nil {
+		return 0, err
+	}
+
+	cases := DetectCheating(cfg, trades)
+	if len(cases) == 0 {
+		return 0, nil
+	}
+	if err := s.repo.UpsertCheatCases(ctx, competitionID, cases); err != nil {
+		return 0, err
+	}
+	return len(cases), nil
+}
+
+func (s *Service) ListCheatCases(ctx context.Context, competitionID uuid.UUID, status model.CheatCaseStatus) ([]model.CheatCase, error) {
+	switch status {
+	case "", model.CheatCaseOpen, model.CheatCaseDismissed, model.CheatCaseDisqualified:
+	default:
+		return nil, ErrInvalidCheatCaseStatus
+	}
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListCheatCases(ctx, competitionID, status)
+}
+
+// ResolveCheatCase closes an open case. Resolving it as disqualified
+// disqualifies both members.
+func (s *Service) ResolveCheatCase(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.CheatCaseStatus,
+	resolvedBy uuid.UUID,
+) error {
+	if status != model.CheatCaseDismissed && status != model.CheatCaseDisqualified {
+		return ErrInvalidCheatCaseStatus
+	}
+
+	c, err := s.repo.ResolveCheatCase(ctx, competitionID, id, status, resolvedBy)
+	if err != nil {
+		return err
+	}
+	if status != model.CheatCaseDisqualified {
+		return nil
+	}
+
+	now := time.Now()
+	for _, login := range []int64{c.LoginA, c.LoginB} {
+		other := c.LoginB
+		if login == c.LoginB {
+			other = c.LoginA
+		}
+		breach := model.RuleBreach{
+			Rule:   RuleCoordinatedTrading,
+			Reason: fmt.Sprintf("%s with account %d", c.Kind, other),
+		}
+		if err := s.repo.DisqualifyMember(ctx, competitionID, login, breach, now); err != nil {
+			return err
+		}
+	}
+
+	s.publishLeaderboardChange(ctx, competitionID)
+	return nil
+}
+
+type cheatPair struct {
+	loginA, loginB int64
+	kind           model.CheatKind
+}
+
+type cheatMatches struct {
+	matches []model.CheatMatch
+	// used holds the trades already matched, keyed by login and position.
+	used map[[2]int64]bool
+}
+
+// DetectCheating looks for pairs of accounts that open trades on the same
+// instrument within cfg.Window of each other. Entries on the same side are
+// evidence of copy trading, entries on opposite sides that are held at the
+// same time evidence of hedging. Each trade is matched at most once per
+// pair and kind.
+//
+// The score weighs the share of matched trades at 70% and the share of
+// those with similar volumes at 30%.
+func DetectCheating(cfg CheatDetectionConfig, trades []model.Trade) []model.CheatCase {
+	counts := make(map[int64]int)
+	for _, t := range trades {
+		counts[t.TradingAccountLogin]++
+	}
+
+	sorted := make([]model.Trade, len(trades))
+	copy(sorted, trades)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].OpenTime.Equal(sorted[j].OpenTime) {
+			return sorted[i].PositionID < sorted[j].PositionID
+		}
+		return sorted[i].OpenTime.Before(sorted[j].OpenTime)
+	})
+
+	pairs := make(map[cheatPair]*cheatMatches)
+	for i, first := range sorted {
+		for _, second := range sorted[i+1:] {
+			if second.OpenTime.Sub(first.OpenTime) > cfg.Window {
+				break
+			}
+			if first.TradingAccountLogin == second.TradingAccountLogin || tradeKey(first) != tradeKey(second) {
+				continue
+			}
+
+			kind := model.CheatCopyTrading
+			if first.Side != second.Side {
+				if !first.CloseTime.After(second.OpenTime) {
+					continue
+				}
+				kind = model.CheatHedging
+			}
+
+			a, b := first, second
+			if a.TradingAccountLogin > b.TradingAccountLogin {
+				a, b = b, a
+			}
+			key := cheatPair{loginA: a.TradingAccountLogin, loginB: b.TradingAccountLogin, kind: kind}
+			pm, ok := pairs[key]
+			if !ok {
+				pm = &cheatMatches{used: make(map[[2]int64]bool)}
+				pairs[key] = pm
+			}
+			refA := [2]int64{a.TradingAccountLogin, a.PositionID}
+			refB := [2]int64{b.TradingAccountLogin, b.PositionID}
+			if pm.used[refA] || pm.used[refB] {
+				continue
+			}
+			pm.used[refA] = true
+			pm.used[refB] = true
+
+			pm.matches = append(pm.matches, model.CheatMatch{
+				Symbol:       tradeKey(a),
+				PositionA:    a.PositionID,
+				PositionB:    b.PositionID,
+				SideA:        a.Side,
+				SideB:        b.Side,
+				VolumeA:      a.Volume,
+				VolumeB:      b.Volume,
+				OpenTimeA:    a.OpenTime,
+				OpenTimeB:    b.OpenTime,
+				DelaySeconds: math.Abs(b.OpenTime.Sub(a.OpenTime).Seconds()),
+			})
+		}
+	}
+
+	var cases []model.CheatCase
+	for key, pm := range pairs {
+		matched := len(pm.matches)
+		ratio := float64(matched) / float64(min(counts[key.loginA], counts[key.loginB]))
+		if matched < cfg.MinMatches || ratio < cfg.MinRatio {
+			continue
+		}
+
+		similar := 0
+		for _, m := range pm.matches {
+			if math.Abs(m.VolumeA-m.VolumeB) <= cfg.VolumeTolerance*math.Max(m.VolumeA, m.VolumeB) {
+				similar++
+			}
+		}
+		score := 100 * (0.7*math.Min(ratio, 1) + 0.3*float64(similar)/float64(matched))
+
+		cases = append(cases, model.CheatCase{
+			LoginA:        key.loginA,
+			LoginB:        key.loginB,
+			Kind:          key.kind,
+			Score:         math.Round(score*100) / 100,
+			MatchedTrades: int32(matched),
+			Evidence: model.CheatEvidence{
+				TradesA:       counts[key.loginA],
+				TradesB:       counts[key.loginB],
+				MatchRatio:    ratio,
+				SimilarVolume: similar,
+				Matches:       pm.matches,
+			},
+		})
+	}
+
+	sort.Slice(cases, func(i, j int) bool {
+		if cases[i].Score != cases[j].Score {
+			return cases[i].Score > cases[j].Score
+		}
+		if cases[i].LoginA != cases[j].LoginA {
+			return cases[i].LoginA < cases[j].LoginA
+		}
+		if cases[i].LoginB != cases[j].LoginB {
+			return cases[i].LoginB < cases[j].LoginB
+		}
+		return cases[i].Kind < cases[j].Kind
+	})
+	return cases
+}
+
+// tradeKey is the canonical instrument of a trade, or its raw symbol when
+// it has none, so accounts at different brokers still match.
+func tradeKey(t model.Trade) string {
+	if t.Instrument != "" {
+		return t.Instrument
+	}
+	return t.Symbol
+}
+
+// CheatDetector periodically scans every competition that is still trading
+// or settling for coordinated accounts.
+type CheatDetector struct {
+	service  *Service
+	interval time.Duration
+	cfg      CheatDetectionConfig
+}
+
+func NewCheatDetector(service *Service, interval time.Duration, cfg CheatDetectionConfig) *CheatDetector {
+	return &CheatDetector{service: service, interval: interval, cfg: cfg}
+}
+
+// Run scans every interval until ctx is done.
+func (d *CheatDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.tick(ctx)
+		}
+	}
+}
+
+func (d *CheatDetector) tick(ctx context.Context) {
+	for _, status := range []model.Status{model.StatusRunning, model.StatusSettling} {
+		competitions, err := d.service.ListByStatus(ctx, status)
+		if err != nil {
+			log.Printf("ANTICHEAT: list %s competitions: %v", status, err)
+			continue
+		}
+
+		for _, c := range competitions {
+			n, err := d.service.ScanForCheating(ctx, c.ID, d.cfg)
+			if err != nil {
+				log.Printf("ANTICHEAT: scan competition %s: %v", c.ID, err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("ANTICHEAT: competition %s has %d suspicious pairs", c.ID, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package competition
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// RuleBalanceOperation marks disqualifications an admin issued from the
+// balance review queue.
+const RuleBalanceOperation = "balance_operation"
+
+// InsertBalanceOperations records deposits, withdrawals, credits and bonuses
+// reported by the broker. Operations that add money after the competition has
+// started are flagged for admin review.
+func (s *Service) InsertBalanceOperations(ctx context.Context, competitionID uuid.UUID, login int64, ops []model.BalanceOperation) (model.BalanceIngestResult, error) {
+	if competitionID == uuid.Nil {
+		return model.BalanceIngestResult{}, ErrNotFound
+	}
+	if login <= 0 {
+		return model.BalanceIngestResult{}, ErrInvalidLogin
+	}
+
+	seen := make(map[int64]bool, len(ops))
+	for _, op := range ops {
+		if err := validateBalanceOperation(op); err != nil {
+			return model.BalanceIngestResult{}, err
+		}
+		if seen[op.OperationID] {
+			return model.BalanceIngestResult{}, ErrInvalidBalanceOperation
+		}
+		seen[op.OperationID] = true
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return model.BalanceIngestResult{}, err
+	}
+	if err := checkAcceptsTrades(c.Status); err != nil {
+		return model.BalanceIngestResult{}, err
+	}
+
+	flags := make(map[int64]string)
+	for _, op := range ops {
+		if op.Type != model.BalanceWithdrawal && !op.OccurredAt.Before(c.StartsAt) {
+			flags[op.OperationID] = fmt.Sprintf("%s of %.2f during the competition", op.Type, op.Amount)
+		}
+	}
+
+	result, err := s.repo.InsertBalanceOperations(ctx, competitionID, login, ops, flags)
+	if err != nil {
+		return model.BalanceIngestResult{}, err
+	}
+
+	if result.Inserted > 0 {
+		s.publishLeaderboardChange(ctx, competitionID)
+	}
+	return result, nil
+}
+
+func (s *Service) ListBalanceReviews(ctx context.Context, competitionID uuid.UUID, status model.BalanceReviewStatus) ([]model.BalanceReview, error) {
+	switch status {
+	case "", model.BalanceReviewPending, model.BalanceReviewApproved, model.BalanceReviewDisqualified:
+	default:
+		return nil, ErrInvalidReviewStatus
+	}
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListBalanceReviews(ctx, competitionID, status)
+}
+
+// ResolveBalanceReview closes a pending review. Resolving it as disqualified
+// also disqualifies the member.
+func (s *Service) ResolveBalanceReview(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.BalanceReviewStatus,
+	reviewedBy uuid.UUID,
+) error {
+	if status != model.BalanceReviewApproved && status != model.BalanceReviewDisqualified {
+		return ErrInvalidReviewStatus
+	}
+
+	review, err := s.repo.ResolveBalanceReview(ctx, competitionID, id, status, reviewedBy)
+	if err != nil {
+		return err
+	}
+	if status != model.BalanceReviewDisqualified {
+		return nil
+	}
+
+	breach := model.RuleBreach{Rule: RuleBalanceOperation, Reason: review.Reason}
+	if err := s.repo.DisqualifyMember(ctx, competitionID, review.Operation.TradingAccountLogin, breach, time.Now()); err != nil {
+		return err
+	}
+
+	s.publishLeaderboardChange(ctx, competitionID)
+	return nil
+}
+
+func validateBalanceOperation(op model.BalanceOperation) error {
+	if op.OperationID <= 0 || op.Amount <= 0 || op.OccurredAt.IsZero() {
+		return ErrInvalidBalanceOperation
+	}
+	switch op.Type {
+	case model.BalanceDeposit, model.BalanceWithdrawal, model.BalanceCredit, model.BalanceBonus:
+		return nil
+	default:
+		return ErrInvalidBalanceOperation
+	}
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountSnapshotRequest defaults TakenAt to the time of receipt.
+type AccountSnapshotRequest struct {
+	Balance  float64    `json:"balance"`
+	Equity   float64    `json:"equity"`
+	Currency string     `json:"currency"`
+	Leverage int32      `json:"leverage"`
+	TakenAt  *time.Time `json:"takenAt"`
+}
+
+type AccountSnapshotResponse struct {
+	AccountSizeSet bool `json:"accountSizeSet"`
+}
+
+type AccountSizeAuditResponse struct {
+	ID        int64      `json:"id"`
+	OldSize   float64    `json:"oldSize"`
+	NewSize   float64    `json:"newSize"`
+	Source    string     `json:"source"`
+	ChangedBy *uuid.UUID `json:"changedBy"`
+	APIKeyID  *uuid.UUID `json:"apiKeyId"`
+	Reason    string     `json:"reason"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BalanceOperationDTO struct {
+	OperationID int64     `json:"operationId"`
+	Type        string    `json:"type"`
+	Amount      float64   `json:"amount"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Comment     string    `json:"comment"`
+}
+
+type InsertBalanceOperationsRequest struct {
+	Operations []BalanceOperationDTO `json:"operations"`
+}
+
+type InsertBalanceOperationsResponse struct {
+	Inserted   int `json:"inserted"`
+	Duplicates int `json:"duplicates"`
+	Flagged    int `json:"flagged"`
+}
+
+type BalanceReviewResponse struct {
+	ID                  int64               `json:"id"`
+	TradingAccountLogin int64               `json:"tradingAccountLogin"`
+	Operation           BalanceOperationDTO `json:"operation"`
+	Reason              string              `json:"reason"`
+	Status              string              `json:"status"`
+	CreatedAt           time.Time           `json:"createdAt"`
+	ReviewedBy          *uuid.UUID          `json:"reviewedBy"`
+	ReviewedAt          *time.Time          `json:"reviewedAt"`
+}
+
+type ResolveBalanceReviewRequest struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CheatMatchDTO struct {
+	Symbol       string    `json:"symbol"`
+	PositionA    int64     `json:"positionA"`
+	PositionB    int64     `json:"positionB"`
+	SideA        string    `json:"sideA"`
+	SideB        string    `json:"sideB"`
+	VolumeA      float64   `json:"volumeA"`
+	VolumeB      float64   `json:"volumeB"`
+	OpenTimeA    time.Time `json:"openTimeA"`
+	OpenTimeB    time.Time `json:"openTimeB"`
+	DelaySeconds float64   `json:"delaySeconds"`
+}
+
+type CheatEvidenceDTO struct {
+	TradesA       int             `json:"tradesA"`
+	TradesB       int             `json:"tradesB"`
+	MatchRatio    float64         `json:"matchRatio"`
+	SimilarVolume int             `json:"similarVolume"`
+	Matches       []CheatMatchDTO `json:"matches"`
+}
+
+type CheatCaseResponse struct {
+	ID            int64            `json:"id"`
+	LoginA        int64            `json:"loginA"`
+	LoginB        int64            `json:"loginB"`
+	Kind          string           `json:"kind"`
+	Score         float64          `json:"score"`
+	MatchedTrades int32            `json:"matchedTrades"`
+	Evidence      CheatEvidenceDTO `json:"evidence"`
+	Status        string           `json:"status"`
+	DetectedAt    time.Time        `json:"detectedAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+	ResolvedBy    *uuid.UUID       `json:"resolvedBy"`
+	ResolvedAt    *time.Time       `json:"resolvedAt"`
+}
+
+type ResolveCheatCaseRequest struct {
+	Status string `json:"status"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 	"github.com/google/uuid"
 	"time"
 )
@@ -9,13 +10,30 @@ type CreateCompetitionRequest struct {
 	Name     string    `json:"name"`
 	StartsAt time.Time `json:"startsAt"`
 	EndsAt   time.Time `json:"endsAt"`
+	// WindowMode defaults to close_time.
+	WindowMode model.WindowMode `json:"windowMode"`
 }
 
 type CompetitionResponse struct {
-	ID       uuid.UUID `json:"id"`
-	Name     string    `json:"name"`
-	StartsAt time.Time `json:"startsAt"`
-	EndsAt   time.Time `json:"endsAt"`
+	ID       uuid.UUID    `json:"id"`
+	Name     string       `json:"name"`
+	StartsAt time.Time    `json:"startsAt"`
+	EndsAt   time.Time    `json:"endsAt"`
+	Status   model.Status `json:"status"`
+
+	WindowMode model.WindowMode `json:"windowMode"`
+
+	FinalizedAt *time.Time `json:"finalizedAt,omitempty"`
+	FinalizedBy *uuid.UUID `json:"finalizedBy,omitempty"`
+	ResultsHash string     `json:"resultsHash,omitempty"`
+}
+
+type UpdateStatusRequest struct {
+	Status model.Status `json:"status"`
+}
+
+type UpdateWindowModeRequest struct {
+	WindowMode model.WindowMode `json:"windowMode"`
 }
 
 type JoinCompetitionRequest struct {
@@ -26,6 +44,8 @@ type JoinCompetitionRequest struct {
 
 type UpdateAccountSizeRequest struct {
 	AccountSize float64 `json:"accountSize"`
+	// Reason is required when an admin overrides the size.
+	Reason string `json:"reason"`
 }
 
 type CompetitionUserStateResponse struct {
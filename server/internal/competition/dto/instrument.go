@@ -0,0 +1,11 @@
+package dto
+
+type InstrumentStatsResponse struct {
+	Instrument string  `json:"instrument"`
+	AssetClass string  `json:"assetClass,omitempty"`
+	TradeCount int32   `json:"tradeCount"`
+	Wins       int32   `json:"wins"`
+	Volume     float64 `json:"volume"`
+	NetProfit  float64 `json:"netProfit"`
+	Pips       float64 `json:"pips"`
+}
@@ -1,11 +1,27 @@
 package dto
 
 type LeaderboardEntryResponse struct {
-	TradingAccountLogin int64   `json:"tradingAccountLogin"`
-	Rank                int32   `json:"rank"`
-	Username            string  `json:"username"`
-	AccountSize         float64 `json:"accountSize"`
-	Profit              float64 `json:"profit"`
-	Equity              float64 `json:"equity"`
-	GainPercent         float64 `json:"gainPercent"`
+	TradingAccountLogin    int64           `json:"tradingAccountLogin"`
+	Rank                   int32           `json:"rank"`
+	Username               string          `json:"username"`
+	AccountSize            float64         `json:"accountSize"`
+	Profit                 float64         `json:"profit"`
+	FloatingProfit         float64         `json:"floatingProfit"`
+	NetDeposits            float64         `json:"netDeposits"`
+	Equity                 float64         `json:"equity"`
+	GainPercent            float64         `json:"gainPercent"`
+	Disqualified           bool            `json:"disqualified"`
+	DisqualificationReason string          `json:"disqualificationReason,omitempty"`
+	RankChange             *int32          `json:"rankChange"`
+	GainChange             *float64        `json:"gainChange"`
+	BestRank               int32           `json:"bestRank"`
+	Metrics                MetricsResponse `json:"metrics"`
+	Score                  *float64        `json:"score,omitempty"`
+}
+
+type LeaderboardPageResponse struct {
+	Entries []LeaderboardEntryResponse `json:"entries"`
+	Total   int                        `json:"total"`
+	Limit   int32                      `json:"limit"`
+	Offset  int32                      `json:"offset"`
 }
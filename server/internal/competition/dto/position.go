@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type OpenPositionDTO struct {
+	PositionID   int64     `json:"positionId"`
+	Symbol       string    `json:"symbol"`
+	Side         string    `json:"side"`
+	Volume       float64   `json:"volume"`
+	OpenTime     time.Time `json:"openTime"`
+	OpenPrice    float64   `json:"openPrice"`
+	CurrentPrice float64   `json:"currentPrice"`
+	Profit       float64   `json:"profit"`
+	Commission   float64   `json:"commission"`
+	Swap         float64   `json:"swap"`
+}
+
+// OpenPositionSnapshotRequest defaults TakenAt to the time of receipt.
+type OpenPositionSnapshotRequest struct {
+	TakenAt   *time.Time        `json:"takenAt"`
+	Positions []OpenPositionDTO `json:"positions"`
+}
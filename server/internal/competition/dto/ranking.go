@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CompetitionRankingRequest struct {
+	Mode      string             `json:"mode"`
+	Weights   map[string]float64 `json:"weights"`
+	TieBreaks []string           `json:"tieBreaks"`
+	// EquityMode is "equity" (default) or "realized".
+	EquityMode string `json:"equityMode"`
+}
+
+type CompetitionRankingResponse struct {
+	CompetitionID uuid.UUID          `json:"competitionId"`
+	Mode          string             `json:"mode"`
+	Weights       map[string]float64 `json:"weights"`
+	TieBreaks     []string           `json:"tieBreaks"`
+	EquityMode    string             `json:"equityMode"`
+}
+
+// MetricsResponse leaves Sharpe, Sortino and profit factor null when they are
+// unbounded, e.g. a profit factor with no losing trades.
+type MetricsResponse struct {
+	MaxDrawdownPercent float64  `json:"maxDrawdownPercent"`
+	Sharpe             *float64 `json:"sharpe"`
+	Sortino            *float64 `json:"sortino"`
+	ProfitFactor       *float64 `json:"profitFactor"`
+	WinRate            float64  `json:"winRate"`
+	AverageR           float64  `json:"averageR"`
+	TradeCount         int32    `json:"tradeCount"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CompetitionRulesRequest struct {
+	MaxDrawdownPercent  *float64 `json:"maxDrawdownPercent"`
+	MaxDailyLossPercent *float64 `json:"maxDailyLossPercent"`
+	MaxLotSize          *float64 `json:"maxLotSize"`
+	AllowedSymbols      []string `json:"allowedSymbols"`
+	MinTradingDays      int32    `json:"minTradingDays"`
+}
+
+type CompetitionRulesResponse struct {
+	CompetitionID       uuid.UUID `json:"competitionId"`
+	MaxDrawdownPercent  *float64  `json:"maxDrawdownPercent"`
+	MaxDailyLossPercent *float64  `json:"maxDailyLossPercent"`
+	MaxLotSize          *float64  `json:"maxLotSize"`
+	AllowedSymbols      []string  `json:"allowedSymbols"`
+	MinTradingDays      int32     `json:"minTradingDays"`
+}
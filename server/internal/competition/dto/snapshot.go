@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+type RankHistoryPointResponse struct {
+	TakenAt     time.Time `json:"takenAt"`
+	Rank        int32     `json:"rank"`
+	Profit      float64   `json:"profit"`
+	Equity      float64   `json:"equity"`
+	GainPercent float64   `json:"gainPercent"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+type EquityPointResponse struct {
+	Time       time.Time `json:"time"`
+	PositionID int64     `json:"positionId"`
+	Equity     float64   `json:"equity"`
+}
+
+type DailyPnLResponse struct {
+	Date       string  `json:"date"`
+	NetProfit  float64 `json:"netProfit"`
+	TradeCount int     `json:"tradeCount"`
+}
+
+type SymbolStatsResponse struct {
+	Symbol     string  `json:"symbol"`
+	TradeCount int     `json:"tradeCount"`
+	Wins       int     `json:"wins"`
+	Volume     float64 `json:"volume"`
+	NetProfit  float64 `json:"netProfit"`
+}
+
+type SideStatsResponse struct {
+	TradeCount int     `json:"tradeCount"`
+	Wins       int     `json:"wins"`
+	NetProfit  float64 `json:"netProfit"`
+	WinRate    float64 `json:"winRate"`
+}
+
+type TradeResultResponse struct {
+	PositionID int64     `json:"positionId"`
+	Symbol     string    `json:"symbol"`
+	NetProfit  float64   `json:"netProfit"`
+	CloseTime  time.Time `json:"closeTime"`
+}
+
+type StreaksResponse struct {
+	MaxWins   int `json:"maxWins"`
+	MaxLosses int `json:"maxLosses"`
+	Current   int `json:"current"`
+}
+
+type DrawdownResponse struct {
+	Amount      float64    `json:"amount"`
+	Percent     float64    `json:"percent"`
+	PeakAt      *time.Time `json:"peakAt"`
+	TroughAt    *time.Time `json:"troughAt"`
+	RecoveredAt *time.Time `json:"recoveredAt"`
+}
+
+type MemberStatsResponse struct {
+	TradeCount            int                   `json:"tradeCount"`
+	NetProfit             float64               `json:"netProfit"`
+	EquityCurve           []EquityPointResponse `json:"equityCurve"`
+	DailyPnL              []DailyPnLResponse    `json:"dailyPnl"`
+	Symbols               []SymbolStatsResponse `json:"symbols"`
+	Long                  SideStatsResponse     `json:"long"`
+	Short                 SideStatsResponse     `json:"short"`
+	AverageHoldingSeconds float64               `json:"averageHoldingSeconds"`
+	LargestWin            *TradeResultResponse  `json:"largestWin"`
+	LargestLoss           *TradeResultResponse  `json:"largestLoss"`
+	Streaks               StreaksResponse       `json:"streaks"`
+	MaxDrawdown           DrawdownResponse      `json:"maxDrawdown"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// LeaderboardDiffResponse lists the entries that changed since the previous
+// stream event and the logins that left the leaderboard.
+type LeaderboardDiffResponse struct {
+	Updated []LeaderboardEntryResponse `json:"updated"`
+	Removed []int64                    `json:"removed"`
+}
@@ -1,7 +1,10 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type TradeDTO struct {
@@ -22,3 +25,54 @@ type InsertTradesRequest struct {
 	TradingAccountLogin int64      `json:"accountId"`
 	Trades              []TradeDTO `json:"trades"`
 }
+
+type TradeOutcomeResponse struct {
+	Index      int      `json:"index"`
+	PositionID int64    `json:"positionId"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Version    int32    `json:"version,omitempty"`
+	Changed    []string `json:"changedFields,omitempty"`
+}
+
+type InsertTradesResponse struct {
+	Inserted    int                    `json:"inserted"`
+	Duplicates  int                    `json:"duplicates"`
+	Updated     int                    `json:"updated"`
+	Rejected    int                    `json:"rejected"`
+	Quarantined int                    `json:"quarantined"`
+	Results     []TradeOutcomeResponse `json:"results"`
+}
+
+type TradeFieldChangeResponse struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+type TradeRevisionResponse struct {
+	PositionID int64                               `json:"positionId"`
+	Version    int32                               `json:"version"`
+	Changes    map[string]TradeFieldChangeResponse `json:"changes"`
+	RevisedAt  time.Time                           `json:"revisedAt"`
+}
+
+type ImportStatementResponse struct {
+	DryRun bool `json:"dryRun"`
+	Parsed int  `json:"parsed"`
+	InsertTradesResponse
+}
+
+type QuarantinedTradeResponse struct {
+	ID                  int64      `json:"id"`
+	TradingAccountLogin int64      `json:"tradingAccountLogin"`
+	Trade               TradeDTO   `json:"trade"`
+	Reason              string     `json:"reason"`
+	Status              string     `json:"status"`
+	QuarantinedAt       time.Time  `json:"quarantinedAt"`
+	ReviewedBy          *uuid.UUID `json:"reviewedBy"`
+	ReviewedAt          *time.Time `json:"reviewedAt"`
+}
+
+type ResolveQuarantinedTradeRequest struct {
+	Status string `json:"status"`
+}
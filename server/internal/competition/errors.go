@@ -3,23 +3,51 @@ package competition
 import "errors"
 
 var (
-	ErrNotFound                = errors.New("not found")
-	ErrAlreadyStarted          = errors.New("already started")
-	ErrAlreadyJoined           = errors.New("already joined")
-	ErrMemberNotFound          = errors.New("member not found")
-	ErrNotMember               = errors.New("not member")
-	ErrInvalidName             = errors.New("invalid name")
-	ErrInvalidTimeRange        = errors.New("invalid time range")
-	ErrInvalidAccountSize      = errors.New("invalid account size")
-	ErrAccountSizeNotSet       = errors.New("account size not set")
-	ErrInvalidLogin            = errors.New("invalid login")
-	ErrInvalidPositionID       = errors.New("invalid position id")
-	ErrInvalidSymbol           = errors.New("invalid symbol")
-	ErrInvalidSide             = errors.New("invalid side")
-	ErrInvalidTradeTimeRange   = errors.New("invalid trade time range")
-	ErrInvalidBroker           = errors.New("invalid broker")
-	ErrInvalidInvestorPassword = errors.New("invalid investor password")
-	ErrAccountAlreadyExists    = errors.New("account already exists")
-	ErrLoginTaken              = errors.New("login taken")
-	ErrTradingAccountNotFound  = errors.New("trading account not found")
+	ErrNotFound                 = errors.New("not found")
+	ErrAlreadyStarted           = errors.New("already started")
+	ErrAlreadyJoined            = errors.New("already joined")
+	ErrMemberNotFound           = errors.New("member not found")
+	ErrNotMember                = errors.New("not member")
+	ErrInvalidName              = errors.New("invalid name")
+	ErrInvalidTimeRange         = errors.New("invalid time range")
+	ErrInvalidAccountSize       = errors.New("invalid account size")
+	ErrAccountSizeNotSet        = errors.New("account size not set")
+	ErrInvalidLogin             = errors.New("invalid login")
+	ErrInvalidPositionID        = errors.New("invalid position id")
+	ErrInvalidSymbol            = errors.New("invalid symbol")
+	ErrInvalidSide              = errors.New("invalid side")
+	ErrInvalidTradeTimeRange    = errors.New("invalid trade time range")
+	ErrInvalidBroker            = errors.New("invalid broker")
+	ErrInvalidInvestorPassword  = errors.New("invalid investor password")
+	ErrAccountAlreadyExists     = errors.New("account already exists")
+	ErrLoginTaken               = errors.New("login taken")
+	ErrTradingAccountNotFound   = errors.New("trading account not found")
+	ErrRulesNotSet              = errors.New("rules not set")
+	ErrInvalidRules             = errors.New("invalid rules")
+	ErrInvalidStatus            = errors.New("invalid status")
+	ErrInvalidTransition        = errors.New("invalid status transition")
+	ErrStatusChanged            = errors.New("status changed concurrently")
+	ErrStandingsChanged         = errors.New("standings changed concurrently")
+	ErrRegistrationClosed       = errors.New("registration closed")
+	ErrNotRunning               = errors.New("competition not running")
+	ErrCompetitionClosed        = errors.New("competition closed")
+	ErrAlreadyFinalized         = errors.New("already finalized")
+	ErrRankingNotSet            = errors.New("ranking not set")
+	ErrInvalidRanking           = errors.New("invalid ranking")
+	ErrInvalidLeaderboardFilter = errors.New("invalid leaderboard filter")
+	ErrInvalidVolume            = errors.New("invalid volume")
+	ErrDuplicatePositionID      = errors.New("duplicate position id")
+	ErrStaleOpenPositions       = errors.New("stale open positions snapshot")
+	ErrInvalidBalanceOperation  = errors.New("invalid balance operation")
+	ErrInvalidReviewStatus      = errors.New("invalid review status")
+	ErrBalanceReviewNotFound    = errors.New("balance review not found")
+	ErrReasonRequired           = errors.New("reason required")
+	ErrInvalidAccountSnapshot   = errors.New("invalid account snapshot")
+	ErrInvalidWindowMode        = errors.New("invalid window mode")
+	ErrWindowModeLocked         = errors.New("window mode locked")
+	ErrInvalidQuarantineStatus  = errors.New("invalid quarantine status")
+	ErrQuarantinedTradeNotFound = errors.New("quarantined trade not found")
+	ErrQuarantinedTradeConflict = errors.New("quarantined trade conflict")
+	ErrInvalidCheatCaseStatus   = errors.New("invalid cheat case status")
+	ErrCheatCaseNotFound        = errors.New("cheat case not found")
 )
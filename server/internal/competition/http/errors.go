@@ -16,31 +16,57 @@ type errorMapping struct {
 
 var errorMap = map[error]errorMapping{
 	// Not Found (404)
-	competition.ErrNotFound:               {http.StatusNotFound, "Competition not found"},
-	competition.ErrMemberNotFound:         {http.StatusNotFound, "Competition member not found"},
-	competition.ErrTradingAccountNotFound: {http.StatusNotFound, "Trading account not found"},
+	competition.ErrNotFound:                 {http.StatusNotFound, "Competition not found"},
+	competition.ErrMemberNotFound:           {http.StatusNotFound, "Competition member not found"},
+	competition.ErrTradingAccountNotFound:   {http.StatusNotFound, "Trading account not found"},
+	competition.ErrBalanceReviewNotFound:    {http.StatusNotFound, "Balance review not found or already resolved"},
+	competition.ErrQuarantinedTradeNotFound: {http.StatusNotFound, "Quarantined trade not found or already resolved"},
+	competition.ErrCheatCaseNotFound:        {http.StatusNotFound, "Cheat case not found or already resolved"},
 
 	// Conflict (409)
-	competition.ErrAlreadyStarted:       {http.StatusConflict, "Competition has already started"},
-	competition.ErrAlreadyJoined:        {http.StatusConflict, "You have already joined this competition"},
-	competition.ErrAccountAlreadyExists: {http.StatusConflict, "You already have a trading account"},
-	competition.ErrLoginTaken:           {http.StatusConflict, "This trading account login is already taken"},
+	competition.ErrAlreadyStarted:           {http.StatusConflict, "Competition has already started"},
+	competition.ErrAlreadyJoined:            {http.StatusConflict, "You have already joined this competition"},
+	competition.ErrAccountAlreadyExists:     {http.StatusConflict, "You already have a trading account"},
+	competition.ErrLoginTaken:               {http.StatusConflict, "This trading account login is already taken"},
+	competition.ErrRegistrationClosed:       {http.StatusConflict, "Registration for this competition is not open"},
+	competition.ErrNotRunning:               {http.StatusConflict, "Competition is not running"},
+	competition.ErrCompetitionClosed:        {http.StatusConflict, "Competition has been finalized"},
+	competition.ErrInvalidTransition:        {http.StatusConflict, "Competition cannot move to this status"},
+	competition.ErrAlreadyFinalized:         {http.StatusConflict, "Competition has already been finalized"},
+	competition.ErrStatusChanged:            {http.StatusConflict, "Competition status changed, please retry"},
+	competition.ErrStandingsChanged:         {http.StatusConflict, "Standings changed while finalizing, please retry"},
+	competition.ErrStaleOpenPositions:       {http.StatusConflict, "A newer open positions snapshot has already been recorded"},
+	competition.ErrWindowModeLocked:         {http.StatusConflict, "Window mode can only change before the competition starts"},
+	competition.ErrQuarantinedTradeConflict: {http.StatusConflict, "Quarantined trade cannot be counted"},
 
 	// Forbidden (403)
 	competition.ErrNotMember: {http.StatusForbidden, "You are not a member of this competition"},
 
 	// Bad Request (400)
-	competition.ErrInvalidName:             {http.StatusBadRequest, "Competition name cannot be empty"},
-	competition.ErrInvalidTimeRange:        {http.StatusBadRequest, "End time must be after start time"},
-	competition.ErrInvalidAccountSize:      {http.StatusBadRequest, "Account size must be greater than zero"},
-	competition.ErrAccountSizeNotSet:       {http.StatusBadRequest, "Account size must be set before inserting trades"},
-	competition.ErrInvalidLogin:            {http.StatusBadRequest, "Trading account login must be a positive number"},
-	competition.ErrInvalidPositionID:       {http.StatusBadRequest, "Position ID must be a positive number"},
-	competition.ErrInvalidSymbol:           {http.StatusBadRequest, "Symbol cannot be empty"},
-	competition.ErrInvalidSide:             {http.StatusBadRequest, "Side must be 'buy' or 'sell'"},
-	competition.ErrInvalidTradeTimeRange:   {http.StatusBadRequest, "Trade close time must be after open time"},
-	competition.ErrInvalidBroker:           {http.StatusBadRequest, "Broker cannot be empty"},
-	competition.ErrInvalidInvestorPassword: {http.StatusBadRequest, "Investor password cannot be empty"},
+	competition.ErrInvalidName:              {http.StatusBadRequest, "Competition name cannot be empty"},
+	competition.ErrInvalidTimeRange:         {http.StatusBadRequest, "End time must be after start time"},
+	competition.ErrInvalidAccountSize:       {http.StatusBadRequest, "Account size must be greater than zero"},
+	competition.ErrAccountSizeNotSet:        {http.StatusBadRequest, "Account size must be set before inserting trades"},
+	competition.ErrInvalidLogin:             {http.StatusBadRequest, "Trading account login must be a positive number"},
+	competition.ErrInvalidPositionID:        {http.StatusBadRequest, "Position ID must be a positive number"},
+	competition.ErrInvalidSymbol:            {http.StatusBadRequest, "Symbol cannot be empty"},
+	competition.ErrInvalidSide:              {http.StatusBadRequest, "Side must be 'buy' or 'sell'"},
+	competition.ErrInvalidTradeTimeRange:    {http.StatusBadRequest, "Trade close time must be after open time"},
+	competition.ErrInvalidVolume:            {http.StatusBadRequest, "Volume must be greater than zero"},
+	competition.ErrDuplicatePositionID:      {http.StatusBadRequest, "Each position may appear only once per snapshot"},
+	competition.ErrInvalidBalanceOperation:  {http.StatusBadRequest, "Operations need a unique positive ID, a known type, a positive amount and a time"},
+	competition.ErrInvalidReviewStatus:      {http.StatusBadRequest, "Status must be approved or disqualified"},
+	competition.ErrReasonRequired:           {http.StatusBadRequest, "A reason is required to override the account size"},
+	competition.ErrInvalidAccountSnapshot:   {http.StatusBadRequest, "Snapshot needs a positive balance and leverage and a currency code"},
+	competition.ErrInvalidWindowMode:        {http.StatusBadRequest, "Window mode must be close_time, open_inside or pro_rate"},
+	competition.ErrInvalidQuarantineStatus:  {http.StatusBadRequest, "Status must be approved or dismissed"},
+	competition.ErrInvalidCheatCaseStatus:   {http.StatusBadRequest, "Status must be dismissed or disqualified"},
+	competition.ErrInvalidBroker:            {http.StatusBadRequest, "Broker cannot be empty"},
+	competition.ErrInvalidInvestorPassword:  {http.StatusBadRequest, "Investor password cannot be empty"},
+	competition.ErrInvalidStatus:            {http.StatusBadRequest, "Unknown competition status"},
+	competition.ErrInvalidRules:             {http.StatusBadRequest, "Percent limits must be between 0 and 100, lot size positive and symbols non-empty"},
+	competition.ErrInvalidLeaderboardFilter: {http.StatusBadRequest, "Status must be active or disqualified and minTrades non-negative"},
+	competition.ErrInvalidRanking:           {http.StatusBadRequest, "Unknown ranking mode, metric or equity mode, negative or all-zero weights, or duplicate tie-breaks"},
 
 	// Auth errors
 	auth.ErrUnauthorized: {http.StatusUnauthorized, "Unauthorized"},
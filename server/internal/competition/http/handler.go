@@ -2,42 +2,88 @@ package http
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/filipcvejic/trading_tournament/internal/apikey"
 	"github.com/filipcvejic/trading_tournament/internal/auth"
 	"github.com/filipcvejic/trading_tournament/internal/competition"
 	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
 	"github.com/filipcvejic/trading_tournament/internal/competition/mapper"
 	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/statement"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type Handler struct {
-	service *competition.Service
+	service     *competition.Service
+	broadcaster *competition.Broadcaster
+	apiKeys     *apikey.Service
 }
 
-func NewHandler(service *competition.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *competition.Service, broadcaster *competition.Broadcaster, apiKeys *apikey.Service) *Handler {
+	return &Handler{service: service, broadcaster: broadcaster, apiKeys: apiKeys}
 }
 
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/competitions", func(r chi.Router) {
-		r.Post("/", h.createCompetition)
-		r.Post("/{competitionID}/members/{accountLogin}/account-size", h.updateAccountSize)
-		r.Post("/{competitionID}/trades", h.insertTrades)
+		r.Group(func(r chi.Router) {
+			r.Use(apikey.RequireAPIKey(h.apiKeys, apikey.ScopeTradesWrite))
+			r.Post("/{competitionID}/members/{accountLogin}/account-size", h.updateAccountSize)
+			r.Post("/{competitionID}/trades", h.insertTrades)
+			r.Put("/{competitionID}/members/{accountLogin}/open-positions", h.replaceOpenPositions)
+			r.Post("/{competitionID}/members/{accountLogin}/balance-operations", h.insertBalanceOperations)
+			r.Post("/{competitionID}/members/{accountLogin}/account-snapshots", h.recordAccountSnapshot)
+		})
 
 		r.Group(func(r chi.Router) {
 			r.Use(auth.AuthenticationMiddleware)
 			r.Get("/{competitionID}", h.getCompetitionByID)
 			r.Get("/current", h.getCurrent)
 			r.Get("/{competitionID}/leaderboard", h.getLeaderboard)
-			r.Post("/{competitionID}/join", h.joinCompetition)
+			r.Get("/{competitionID}/leaderboard/history", h.getLeaderboardHistory)
+			r.Get("/{competitionID}/leaderboard/stream", h.streamLeaderboard)
+			r.Get("/{competitionID}/members/{accountLogin}/trades/{positionID}/revisions", h.getTradeRevisions)
+			r.Get("/{competitionID}/members/{accountLogin}/open-positions", h.getOpenPositions)
+			r.Get("/{competitionID}/members/{accountLogin}/instruments", h.getInstrumentStats)
+			r.Get("/{competitionID}/members/{accountLogin}/stats", h.getMemberStats)
 			r.Get("/{competitionID}/me", h.getMe)
+			r.Get("/{competitionID}/rules", h.getRules)
+			r.Get("/{competitionID}/ranking", h.getRanking)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(auth.AuthenticationMiddleware)
+			r.Use(auth.RequireVerifiedEmail)
+			r.Post("/{competitionID}/join", h.joinCompetition)
 			r.Post("/{competitionID}/account-requests", h.requestAccount)
 		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(auth.AuthenticationMiddleware)
+			r.Use(auth.RequireAdmin)
+			r.Post("/", h.createCompetition)
+			r.Put("/{competitionID}/rules", h.setRules)
+			r.Put("/{competitionID}/ranking", h.setRanking)
+			r.Post("/{competitionID}/status", h.updateStatus)
+			r.Post("/{competitionID}/finalize", h.finalize)
+			r.Get("/{competitionID}/balance-reviews", h.listBalanceReviews)
+			r.Post("/{competitionID}/balance-reviews/{reviewID}", h.resolveBalanceReview)
+			r.Put("/{competitionID}/members/{accountLogin}/account-size", h.overrideAccountSize)
+			r.Get("/{competitionID}/members/{accountLogin}/account-size/audit", h.listAccountSizeAudit)
+			r.Post("/{competitionID}/members/{accountLogin}/statement", h.importStatement)
+			r.Put("/{competitionID}/window-mode", h.setWindowMode)
+			r.Get("/{competitionID}/quarantine", h.listQuarantinedTrades)
+			r.Post("/{competitionID}/quarantine/{tradeID}", h.resolveQuarantinedTrade)
+			r.Get("/{competitionID}/cheat-cases", h.listCheatCases)
+			r.Post("/{competitionID}/cheat-cases/{caseID}", h.resolveCheatCase)
+		})
 	})
 }
 
@@ -49,10 +95,12 @@ func (h *Handler) createCompetition(w http.ResponseWriter, r *http.Request) {
 	}
 
 	c := model.Competition{
-		ID:       uuid.New(),
-		Name:     req.Name,
-		StartsAt: req.StartsAt,
-		EndsAt:   req.EndsAt,
+		ID:         uuid.New(),
+		Name:       req.Name,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Status:     model.StatusDraft,
+		WindowMode: req.WindowMode,
 	}
 
 	if err := h.service.Create(r.Context(), c); err != nil {
@@ -60,12 +108,10 @@ func (h *Handler) createCompetition(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusCreated, dto.CompetitionResponse{
-		ID:       c.ID,
-		Name:     c.Name,
-		StartsAt: c.StartsAt,
-		EndsAt:   c.EndsAt,
-	})
+	if c.WindowMode == "" {
+		c.WindowMode = model.WindowCloseTime
+	}
+	httputil.WriteJSON(w, http.StatusCreated, mapper.CompetitionToDTO(c))
 }
 
 func (h *Handler) getCompetitionByID(w http.ResponseWriter, r *http.Request) {
@@ -81,12 +127,7 @@ func (h *Handler) getCompetitionByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, dto.CompetitionResponse{
-		ID:       c.ID,
-		Name:     c.Name,
-		StartsAt: c.StartsAt,
-		EndsAt:   c.EndsAt,
-	})
+	httputil.WriteJSON(w, http.StatusOK, mapper.CompetitionToDTO(c))
 }
 
 func (h *Handler) joinCompetition(w http.ResponseWriter, r *http.Request) {
@@ -135,7 +176,55 @@ func (h *Handler) updateAccountSize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateAccountSize(r.Context(), competitionID, accountLogin, req.AccountSize); err != nil {
+	change := model.AccountSizeChange{
+		Size:   req.AccountSize,
+		Source: model.AccountSizeFromAPI,
+		Reason: req.Reason,
+	}
+	if key, ok := apikey.GetAPIKey(r); ok {
+		change.APIKeyID = &key.ID
+	}
+
+	if err := h.service.UpdateAccountSize(r.Context(), competitionID, accountLogin, change); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) overrideAccountSize(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	var req dto.UpdateAccountSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	change := model.AccountSizeChange{
+		Size:      req.AccountSize,
+		Source:    model.AccountSizeFromAdmin,
+		ChangedBy: &userID,
+		Reason:    req.Reason,
+	}
+	if err := h.service.UpdateAccountSize(r.Context(), competitionID, accountLogin, change); err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
@@ -143,6 +232,66 @@ func (h *Handler) updateAccountSize(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *Handler) listAccountSizeAudit(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	entries, err := h.service.ListAccountSizeAudit(r.Context(), competitionID, accountLogin)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.AccountSizeAuditToDTO(entries))
+}
+
+func (h *Handler) recordAccountSnapshot(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	var req dto.AccountSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	snapshot := model.AccountSnapshot{
+		Balance:  req.Balance,
+		Equity:   req.Equity,
+		Currency: req.Currency,
+		Leverage: req.Leverage,
+	}
+	if req.TakenAt != nil {
+		snapshot.TakenAt = *req.TakenAt
+	}
+
+	applied, err := h.service.RecordAccountSnapshot(r.Context(), competitionID, accountLogin, snapshot)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, dto.AccountSnapshotResponse{AccountSizeSet: applied})
+}
+
 func (h *Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
 	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
 	if err != nil {
@@ -150,33 +299,82 @@ func (h *Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var limit, offset int32
+	query := r.URL.Query()
+	q := model.LeaderboardQuery{
+		UsernamePrefix: query.Get("username"),
+		Status:         model.MemberFilter(query.Get("status")),
+	}
 
-	if v := r.URL.Query().Get("limit"); v != "" {
+	if v := query.Get("limit"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil || n < 0 {
 			httputil.WriteClientError(w, r, "Invalid limit parameter", err)
 			return
 		}
-		limit = int32(n)
+		q.Limit = int32(n)
 	}
 
-	if v := r.URL.Query().Get("offset"); v != "" {
+	if v := query.Get("offset"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil || n < 0 {
 			httputil.WriteClientError(w, r, "Invalid offset parameter", err)
 			return
 		}
-		offset = int32(n)
+		q.Offset = int32(n)
+	}
+
+	if v := query.Get("minTrades"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			httputil.WriteClientError(w, r, "Invalid minTrades parameter", err)
+			return
+		}
+		q.MinTrades = int32(n)
+	}
+
+	switch query.Get("around") {
+	case "":
+	case "me":
+		userID, ok := auth.GetUserID(r)
+		if !ok {
+			httputil.WriteUnauthorized(w, r)
+			return
+		}
+		q.AroundUserID = &userID
+	default:
+		httputil.WriteClientError(w, r, "Invalid around parameter", nil)
+		return
+	}
+
+	page, err := h.service.GetLeaderboard(r.Context(), competitionID, q)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.LeaderboardPageToDTO(page))
+}
+
+func (h *Handler) getLeaderboardHistory(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	login, err := strconv.ParseInt(r.URL.Query().Get("login"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid login parameter", err)
+		return
 	}
 
-	entries, err := h.service.GetLeaderboard(r.Context(), competitionID, limit, offset)
+	points, err := h.service.GetRankHistory(r.Context(), competitionID, login)
 	if err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, mapper.LeaderboardToDTO(entries))
+	httputil.WriteJSON(w, http.StatusOK, mapper.RankHistoryToDTO(points))
 }
 
 func (h *Handler) insertTrades(w http.ResponseWriter, r *http.Request) {
@@ -209,7 +407,68 @@ func (h *Handler) insertTrades(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := h.service.InsertTrades(r.Context(), competitionID, req.TradingAccountLogin, trades); err != nil {
+	outcomes, err := h.service.InsertTrades(r.Context(), competitionID, req.TradingAccountLogin, trades)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.TradeOutcomesToDTO(outcomes))
+}
+
+func (h *Handler) getTradeRevisions(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	positionID, err := strconv.ParseInt(chi.URLParam(r, "positionID"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid position ID format", err)
+		return
+	}
+
+	revisions, err := h.service.GetTradeRevisions(r.Context(), competitionID, accountLogin, positionID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.TradeRevisionsToDTO(revisions))
+}
+
+func (h *Handler) replaceOpenPositions(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	var req dto.OpenPositionSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	snapshot := model.OpenPositionSnapshot{Positions: mapper.OpenPositionsFromDTO(req.Positions)}
+	if req.TakenAt != nil {
+		snapshot.TakenAt = *req.TakenAt
+	}
+
+	if err := h.service.ReplaceOpenPositions(r.Context(), competitionID, accountLogin, snapshot); err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
@@ -217,55 +476,541 @@ func (h *Handler) insertTrades(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *Handler) getMe(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) getOpenPositions(w http.ResponseWriter, r *http.Request) {
 	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
 	if err != nil {
 		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
 		return
 	}
 
-	userID, ok := auth.GetUserID(r)
-	if !ok {
-		httputil.WriteUnauthorized(w, r)
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
 		return
 	}
 
-	state, err := h.service.GetUserCompetitionState(r.Context(), userID, competitionID)
+	positions, err := h.service.GetOpenPositions(r.Context(), competitionID, accountLogin)
 	if err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, state)
+	httputil.WriteJSON(w, http.StatusOK, mapper.OpenPositionsToDTO(positions))
 }
 
-func (h *Handler) getCurrent(w http.ResponseWriter, r *http.Request) {
-	comp, err := h.service.GetCurrentCompetition(r.Context())
+func (h *Handler) getInstrumentStats(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	stats, err := h.service.GetInstrumentStats(r.Context(), competitionID, accountLogin)
 	if err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, comp)
+	httputil.WriteJSON(w, http.StatusOK, mapper.InstrumentStatsToDTO(stats))
 }
 
-func (h *Handler) requestAccount(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) getMemberStats(w http.ResponseWriter, r *http.Request) {
 	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
 	if err != nil {
 		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
 		return
 	}
 
-	userID, ok := auth.GetUserID(r)
-	if !ok {
-		httputil.WriteUnauthorized(w, r)
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
 		return
 	}
 
-	if err := h.service.RequestAccount(r.Context(), userID, competitionID); err != nil {
+	stats, err := h.service.GetMemberStats(r.Context(), competitionID, accountLogin)
+	if err != nil {
 		writeDomainError(w, r, err)
 		return
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	httputil.WriteJSON(w, http.StatusOK, mapper.MemberStatsToDTO(stats))
+}
+
+func (h *Handler) insertBalanceOperations(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	var req dto.InsertBalanceOperationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	ops := mapper.BalanceOperationsFromDTO(accountLogin, req.Operations)
+	result, err := h.service.InsertBalanceOperations(r.Context(), competitionID, accountLogin, ops)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, dto.InsertBalanceOperationsResponse{
+		Inserted:   result.Inserted,
+		Duplicates: result.Duplicates,
+		Flagged:    result.Flagged,
+	})
+}
+
+func (h *Handler) listBalanceReviews(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	status := model.BalanceReviewStatus(r.URL.Query().Get("status"))
+	reviews, err := h.service.ListBalanceReviews(r.Context(), competitionID, status)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.BalanceReviewsToDTO(reviews))
+}
+
+func (h *Handler) resolveBalanceReview(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "reviewID"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid review ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	var req dto.ResolveBalanceReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	status := model.BalanceReviewStatus(req.Status)
+	if err := h.service.ResolveBalanceReview(r.Context(), competitionID, reviewID, status, userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) setWindowMode(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	var req dto.UpdateWindowModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	if err := h.service.SetWindowMode(r.Context(), competitionID, req.WindowMode); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listQuarantinedTrades(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	status := model.QuarantineStatus(r.URL.Query().Get("status"))
+	trades, err := h.service.ListQuarantinedTrades(r.Context(), competitionID, status)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.QuarantinedTradesToDTO(trades))
+}
+
+func (h *Handler) resolveQuarantinedTrade(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	tradeID, err := strconv.ParseInt(chi.URLParam(r, "tradeID"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid quarantined trade ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	var req dto.ResolveQuarantinedTradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	status := model.QuarantineStatus(req.Status)
+	if err := h.service.ResolveQuarantinedTrade(r.Context(), competitionID, tradeID, status, userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const maxStatementSize = 10 << 20
+
+// importStatement backfills a member's trades from an uploaded MetaTrader
+// statement in the "file" form field. The format is taken from the format
+// query parameter, the file extension or the content, in that order, and tz
+// names the broker's server time zone.
+func (h *Handler) importStatement(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	accountLogin, err := strconv.ParseInt(chi.URLParam(r, "accountLogin"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid account login format", err)
+		return
+	}
+
+	q := r.URL.Query()
+	dryRun := q.Get("dryRun") == "true"
+
+	loc := time.UTC
+	if tz := q.Get("tz"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			httputil.WriteClientError(w, r, "Invalid tz parameter", err)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		httputil.WriteClientError(w, r, "Missing statement file", err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Could not read statement file", err)
+		return
+	}
+
+	format := statement.Format(q.Get("format"))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".htm", ".html":
+			format = statement.FormatHTML
+		case ".csv", ".txt":
+			format = statement.FormatCSV
+		}
+	}
+
+	trades, err := statement.Parse(data, format, loc)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Could not parse statement", err)
+		return
+	}
+
+	outcomes, err := h.service.ImportTrades(r.Context(), competitionID, accountLogin, trades, dryRun)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, dto.ImportStatementResponse{
+		DryRun:               dryRun,
+		Parsed:               len(trades),
+		InsertTradesResponse: mapper.TradeOutcomesToDTO(outcomes),
+	})
+}
+
+func (h *Handler) getMe(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	state, err := h.service.GetUserCompetitionState(r.Context(), userID, competitionID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, state)
+}
+
+func (h *Handler) getCurrent(w http.ResponseWriter, r *http.Request) {
+	comp, err := h.service.GetCurrentCompetition(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, comp)
+}
+
+func (h *Handler) requestAccount(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	if err := h.service.RequestAccount(r.Context(), userID, competitionID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+func (h *Handler) getRules(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	rules, err := h.service.GetRules(r.Context(), competitionID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.RulesToDTO(rules))
+}
+
+func (h *Handler) setRules(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	var req dto.CompetitionRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	rules, err := h.service.SetRules(r.Context(), model.Rules{
+		CompetitionID:       competitionID,
+		MaxDrawdownPercent:  req.MaxDrawdownPercent,
+		MaxDailyLossPercent: req.MaxDailyLossPercent,
+		MaxLotSize:          req.MaxLotSize,
+		AllowedSymbols:      req.AllowedSymbols,
+		MinTradingDays:      req.MinTradingDays,
+	})
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.RulesToDTO(rules))
+}
+
+func (h *Handler) getRanking(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	ranking, err := h.service.GetRanking(r.Context(), competitionID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.RankingToDTO(ranking))
+}
+
+func (h *Handler) setRanking(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	var req dto.CompetitionRankingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	ranking := mapper.RankingFromDTO(req)
+	ranking.CompetitionID = competitionID
+
+	ranking, err = h.service.SetRanking(r.Context(), ranking)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.RankingToDTO(ranking))
+}
+
+func (h *Handler) updateStatus(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	var req dto.UpdateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	// Finalizing through the status endpoint still records who did it
+	if req.Status == model.StatusFinalized {
+		h.finalize(w, r)
+		return
+	}
+
+	if err := h.service.Transition(r.Context(), competitionID, req.Status); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) finalize(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	if err := h.service.Finalize(r.Context(), competitionID, &userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	c, err := h.service.GetByID(r.Context(), competitionID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.CompetitionToDTO(c))
+}
+
+func (h *Handler) listCheatCases(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	status := model.CheatCaseStatus(r.URL.Query().Get("status"))
+	cases, err := h.service.ListCheatCases(r.Context(), competitionID, status)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, mapper.CheatCasesToDTO(cases))
+}
+
+func (h *Handler) resolveCheatCase(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	caseID, err := strconv.ParseInt(chi.URLParam(r, "caseID"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid case ID format", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	var req dto.ResolveCheatCaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	status := model.CheatCaseStatus(req.Status)
+	if err := h.service.ResolveCheatCase(r.Context(), competitionID, caseID, status, userID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
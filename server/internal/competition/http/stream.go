@@ -0,0 +1,84 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	reconnectDelay    = 3 * time.Second
+)
+
+func (h *Handler) streamLeaderboard(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := uuid.Parse(chi.URLParam(r, "competitionID"))
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+		return
+	}
+
+	replay, events, cancel, err := h.broadcaster.Subscribe(r.Context(), competitionID, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds()); err != nil {
+		return
+	}
+	for _, e := range replay {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with
+				// Last-Event-ID and catches up from the backlog.
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e competition.StreamEvent) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+	return err
+}
@@ -0,0 +1,105 @@
+package competition
+
+import (
+	"context"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// ImportTrades backfills trades parsed from a broker statement through
+// InsertTrades, so trades outside the competition window are quarantined as
+// usual. With dryRun nothing is written; the outcomes predict what the import
+// would do.
+func (s *Service) ImportTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade, dryRun bool) ([]model.TradeOutcome, error) {
+	if !dryRun {
+		return s.InsertTrades(ctx, competitionID, login, trades)
+	}
+	if competitionID == uuid.Nil {
+		return nil, ErrNotFound
+	}
+	if login <= 0 {
+		return nil, ErrInvalidLogin
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.previewTrades(ctx, c, login, trades)
+}
+
+// previewTrades runs the checks of InsertTrades, including the one against
+// positions recorded in another competition, and compares the valid trades
+// with the stored ones instead of writing them.
+func (s *Service) previewTrades(ctx context.Context, c model.Competition, login int64, trades []model.Trade) ([]model.TradeOutcome, error) {
+	if err := checkAcceptsTrades(c.Status); err != nil {
+		return nil, err
+	}
+
+	size, err := s.repo.GetMemberAccountSize(ctx, c.ID, login)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return nil, ErrAccountSizeNotSet
+	}
+
+	existing, err := s.repo.ListMemberTrades(ctx, c.ID, login)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[int64]model.Trade, len(existing))
+	for _, t := range existing {
+		stored[t.PositionID] = t
+	}
+
+	outcomes, valid, _ := prepareTrades(c, trades)
+	counted := make(map[int64]model.Trade, len(valid))
+	positionIDs := make([]int64, 0, len(valid))
+	for _, t := range valid {
+		counted[t.PositionID] = t
+		positionIDs = append(positionIDs, t.PositionID)
+	}
+
+	foreignIDs, err := s.repo.ListForeignPositions(ctx, c.ID, login, positionIDs)
+	if err != nil {
+		return nil, err
+	}
+	foreign := make(map[int64]bool, len(foreignIDs))
+	for _, id := range foreignIDs {
+		foreign[id] = true
+	}
+
+	for i, t := range trades {
+		if outcomes[i].Status != "" {
+			continue
+		}
+		prev, ok := stored[t.PositionID]
+		switch {
+		case foreign[t.PositionID]:
+			outcomes[i].Status = model.TradeRejected
+			outcomes[i].Reason = foreignPositionReason
+		case !ok:
+			outcomes[i].Status = model.TradeInserted
+		case sameTrade(prev, counted[t.PositionID]):
+			outcomes[i].Status = model.TradeDuplicate
+		default:
+			outcomes[i].Status = model.TradeUpdated
+		}
+	}
+	return outcomes, nil
+}
+
+func sameTrade(a, b model.Trade) bool {
+	return a.Symbol == b.Symbol &&
+		a.Side == b.Side &&
+		a.Volume == b.Volume &&
+		a.OpenTime.Equal(b.OpenTime) &&
+		a.CloseTime.Equal(b.CloseTime) &&
+		a.OpenPrice == b.OpenPrice &&
+		a.ClosePrice == b.ClosePrice &&
+		a.Profit == b.Profit &&
+		a.Commission == b.Commission &&
+		a.Swap == b.Swap
+}
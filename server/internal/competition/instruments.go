@@ -0,0 +1,71 @@
+package competition
+
+import (
+	"context"
+	"sort"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+	"github.com/google/uuid"
+)
+
+// GetInstrumentStats breaks a member's trades down by instrument.
+func (s *Service) GetInstrumentStats(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.InstrumentStats, error) {
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	trades, err := s.repo.ListMemberTrades(ctx, competitionID, login)
+	if err != nil {
+		return nil, err
+	}
+	catalogue, err := s.instruments.Catalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return InstrumentStats(catalogue, trades), nil
+}
+
+// InstrumentStats groups trades by their canonical instrument, or by raw
+// symbol when they have none, most traded first.
+func InstrumentStats(catalogue *instrument.Catalogue, trades []model.Trade) []model.InstrumentStats {
+	byKey := make(map[string]*model.InstrumentStats)
+	for _, t := range trades {
+		key := t.Instrument
+		if key == "" {
+			key = t.Symbol
+		}
+
+		st, ok := byKey[key]
+		if !ok {
+			st = &model.InstrumentStats{Instrument: key}
+			byKey[key] = st
+		}
+
+		net := t.Profit + t.Commission + t.Swap
+		st.TradeCount++
+		if net > 0 {
+			st.Wins++
+		}
+		st.Volume += t.Volume
+		st.NetProfit += net
+
+		if inst, ok := catalogue.Get(t.Instrument); ok {
+			st.AssetClass = string(inst.AssetClass)
+			st.Pips += inst.Pips(t.Side, t.OpenPrice, t.ClosePrice)
+		}
+	}
+
+	out := make([]model.InstrumentStats, 0, len(byKey))
+	for _, st := range byKey {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TradeCount != out[j].TradeCount {
+			return out[i].TradeCount > out[j].TradeCount
+		}
+		return out[i].Instrument < out[j].Instrument
+	})
+	return out
+}
@@ -0,0 +1,49 @@
+package competition
+
+import "github.com/filipcvejic/trading_tournament/internal/competition/model"
+
+// transitions lists the statuses each lifecycle status may move to.
+// Registration can be pulled back to draft; every later step is one-way.
+var transitions = map[model.Status][]model.Status{
+	model.StatusDraft:            {model.StatusRegistrationOpen},
+	model.StatusRegistrationOpen: {model.StatusDraft, model.StatusRunning},
+	model.StatusRunning:          {model.StatusSettling},
+	model.StatusSettling:         {model.StatusFinalized},
+	model.StatusFinalized:        {model.StatusArchived},
+}
+
+func CanTransition(from, to model.Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidStatus(s model.Status) bool {
+	switch s {
+	case model.StatusDraft,
+		model.StatusRegistrationOpen,
+		model.StatusRunning,
+		model.StatusSettling,
+		model.StatusFinalized,
+		model.StatusArchived:
+		return true
+	}
+	return false
+}
+
+// checkAcceptsTrades reports whether trades may be ingested in status s.
+// Settling still accepts trades so late syncs of positions closed before the
+// end can land before results are frozen.
+func checkAcceptsTrades(s model.Status) error {
+	switch s {
+	case model.StatusRunning, model.StatusSettling:
+		return nil
+	case model.StatusFinalized, model.StatusArchived:
+		return ErrCompetitionClosed
+	default:
+		return ErrNotRunning
+	}
+}
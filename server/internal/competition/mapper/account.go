@@ -0,0 +1,45 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func AccountSizeAuditFromDB(rows []sqlc.AccountSizeAudit) []model.AccountSizeAuditEntry {
+	entries := make([]model.AccountSizeAuditEntry, 0, len(rows))
+
+	for _, r := range rows {
+		entries = append(entries, model.AccountSizeAuditEntry{
+			ID:        r.ID,
+			OldSize:   r.OldSize,
+			NewSize:   r.NewSize,
+			Source:    model.AccountSizeSource(r.Source),
+			ChangedBy: r.ChangedBy,
+			APIKeyID:  r.ApiKeyID,
+			Reason:    r.Reason,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	return entries
+}
+
+func AccountSizeAuditToDTO(entries []model.AccountSizeAuditEntry) []dto.AccountSizeAuditResponse {
+	out := make([]dto.AccountSizeAuditResponse, 0, len(entries))
+
+	for _, e := range entries {
+		out = append(out, dto.AccountSizeAuditResponse{
+			ID:        e.ID,
+			OldSize:   e.OldSize,
+			NewSize:   e.NewSize,
+			Source:    string(e.Source),
+			ChangedBy: e.ChangedBy,
+			APIKeyID:  e.APIKeyID,
+			Reason:    e.Reason,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return out
+}
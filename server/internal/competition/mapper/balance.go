@@ -0,0 +1,92 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func BalanceOperationsFromDTO(login int64, items []dto.BalanceOperationDTO) []model.BalanceOperation {
+	ops := make([]model.BalanceOperation, 0, len(items))
+
+	for _, it := range items {
+		ops = append(ops, model.BalanceOperation{
+			TradingAccountLogin: login,
+			OperationID:         it.OperationID,
+			Type:                model.BalanceOperationType(it.Type),
+			Amount:              it.Amount,
+			OccurredAt:          it.OccurredAt,
+			Comment:             it.Comment,
+		})
+	}
+
+	return ops
+}
+
+func BalanceReviewsFromDB(rows []sqlc.ListBalanceReviewsRow) []model.BalanceReview {
+	reviews := make([]model.BalanceReview, 0, len(rows))
+
+	for _, r := range rows {
+		reviews = append(reviews, model.BalanceReview{
+			ID: r.ID,
+			Operation: model.BalanceOperation{
+				TradingAccountLogin: r.TradingAccountLogin,
+				OperationID:         r.OperationID,
+				Type:                model.BalanceOperationType(r.Type),
+				Amount:              r.Amount,
+				OccurredAt:          r.OccurredAt,
+			},
+			Reason:     r.Reason,
+			Status:     model.BalanceReviewStatus(r.Status),
+			CreatedAt:  r.CreatedAt,
+			ReviewedBy: r.ReviewedBy,
+			ReviewedAt: r.ReviewedAt,
+		})
+	}
+
+	return reviews
+}
+
+func BalanceReviewFromDB(row sqlc.BalanceReview) model.BalanceReview {
+	return model.BalanceReview{
+		ID: row.ID,
+		Operation: model.BalanceOperation{
+			TradingAccountLogin: row.TradingAccountLogin,
+			OperationID:         row.OperationID,
+		},
+		Reason:     row.Reason,
+		Status:     model.BalanceReviewStatus(row.Status),
+		CreatedAt:  row.CreatedAt,
+		ReviewedBy: row.ReviewedBy,
+		ReviewedAt: row.ReviewedAt,
+	}
+}
+
+func BalanceReviewsToDTO(reviews []model.BalanceReview) []dto.BalanceReviewResponse {
+	out := make([]dto.BalanceReviewResponse, 0, len(reviews))
+
+	for _, r := range reviews {
+		out = append(out, BalanceReviewToDTO(r))
+	}
+
+	return out
+}
+
+func BalanceReviewToDTO(r model.BalanceReview) dto.BalanceReviewResponse {
+	return dto.BalanceReviewResponse{
+		ID:                  r.ID,
+		TradingAccountLogin: r.Operation.TradingAccountLogin,
+		Operation: dto.BalanceOperationDTO{
+			OperationID: r.Operation.OperationID,
+			Type:        string(r.Operation.Type),
+			Amount:      r.Operation.Amount,
+			OccurredAt:  r.Operation.OccurredAt,
+			Comment:     r.Operation.Comment,
+		},
+		Reason:     r.Reason,
+		Status:     string(r.Status),
+		CreatedAt:  r.CreatedAt,
+		ReviewedBy: r.ReviewedBy,
+		ReviewedAt: r.ReviewedAt,
+	}
+}
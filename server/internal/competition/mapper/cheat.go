@@ -0,0 +1,114 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+func CheatCaseToUpsertParams(competitionID uuid.UUID, c model.CheatCase) (sqlc.UpsertCheatCaseParams, error) {
+	evidence, err := json.Marshal(c.Evidence)
+	if err != nil {
+		return sqlc.UpsertCheatCaseParams{}, fmt.Errorf("encode cheat evidence: %w", err)
+	}
+
+	return sqlc.UpsertCheatCaseParams{
+		CompetitionID: competitionID,
+		LoginA:        c.LoginA,
+		LoginB:        c.LoginB,
+		Kind:          string(c.Kind),
+		Score:         c.Score,
+		MatchedTrades: c.MatchedTrades,
+		Evidence:      evidence,
+	}, nil
+}
+
+func CheatCasesFromDB(rows []sqlc.CheatCase) ([]model.CheatCase, error) {
+	cases := make([]model.CheatCase, 0, len(rows))
+
+	for _, r := range rows {
+		c, err := CheatCaseFromDB(r)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+func CheatCaseFromDB(row sqlc.CheatCase) (model.CheatCase, error) {
+	var evidence model.CheatEvidence
+	if err := json.Unmarshal(row.Evidence, &evidence); err != nil {
+		return model.CheatCase{}, fmt.Errorf("decode cheat case %d evidence: %w", row.ID, err)
+	}
+
+	return model.CheatCase{
+		ID:            row.ID,
+		CompetitionID: row.CompetitionID,
+		LoginA:        row.LoginA,
+		LoginB:        row.LoginB,
+		Kind:          model.CheatKind(row.Kind),
+		Score:         row.Score,
+		MatchedTrades: row.MatchedTrades,
+		Evidence:      evidence,
+		Status:        model.CheatCaseStatus(row.Status),
+		DetectedAt:    row.DetectedAt,
+		UpdatedAt:     row.UpdatedAt,
+		ResolvedBy:    row.ResolvedBy,
+		ResolvedAt:    row.ResolvedAt,
+	}, nil
+}
+
+func CheatCasesToDTO(cases []model.CheatCase) []dto.CheatCaseResponse {
+	out := make([]dto.CheatCaseResponse, 0, len(cases))
+
+	for _, c := range cases {
+		out = append(out, CheatCaseToDTO(c))
+	}
+
+	return out
+}
+
+func CheatCaseToDTO(c model.CheatCase) dto.CheatCaseResponse {
+	matches := make([]dto.CheatMatchDTO, 0, len(c.Evidence.Matches))
+	for _, m := range c.Evidence.Matches {
+		matches = append(matches, dto.CheatMatchDTO{
+			Symbol:       m.Symbol,
+			PositionA:    m.PositionA,
+			PositionB:    m.PositionB,
+			SideA:        m.SideA,
+			SideB:        m.SideB,
+			VolumeA:      m.VolumeA,
+			VolumeB:      m.VolumeB,
+			OpenTimeA:    m.OpenTimeA,
+			OpenTimeB:    m.OpenTimeB,
+			DelaySeconds: m.DelaySeconds,
+		})
+	}
+
+	return dto.CheatCaseResponse{
+		ID:            c.ID,
+		LoginA:        c.LoginA,
+		LoginB:        c.LoginB,
+		Kind:          string(c.Kind),
+		Score:         c.Score,
+		MatchedTrades: c.MatchedTrades,
+		Evidence: dto.CheatEvidenceDTO{
+			TradesA:       c.Evidence.TradesA,
+			TradesB:       c.Evidence.TradesB,
+			MatchRatio:    c.Evidence.MatchRatio,
+			SimilarVolume: c.Evidence.SimilarVolume,
+			Matches:       matches,
+		},
+		Status:     string(c.Status),
+		DetectedAt: c.DetectedAt,
+		UpdatedAt:  c.UpdatedAt,
+		ResolvedBy: c.ResolvedBy,
+		ResolvedAt: c.ResolvedAt,
+	}
+}
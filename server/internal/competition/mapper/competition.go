@@ -2,24 +2,36 @@ package mapper
 
 import (
 	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
 	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 )
 
 func CompetitionFromDB(row sqlc.Competition) model.Competition {
 	return model.Competition{
-		ID:        row.ID,
-		Name:      row.Name,
-		StartsAt:  row.StartsAt,
-		EndsAt:    row.EndsAt,
-		CreatedAt: row.CreatedAt,
+		ID:              row.ID,
+		Name:            row.Name,
+		StartsAt:        row.StartsAt,
+		EndsAt:          row.EndsAt,
+		Status:          model.Status(row.Status),
+		StatusChangedAt: row.StatusChangedAt,
+		FinalizedAt:     row.FinalizedAt,
+		FinalizedBy:     row.FinalizedBy,
+		ResultsHash:     row.ResultsHash.String,
+		WindowMode:      model.WindowMode(row.WindowMode),
+		CreatedAt:       row.CreatedAt,
 	}
 }
 
-//func CompetitionToDTO(c model.Competition) dto.CompetitionResponse {
-//	return dto.CompetitionResponse{
-//		ID:       c.ID.String(),
-//		Name:     c.Name,
-//		StartsAt: c.StartsAt.Format(time.RFC3339),
-//		EndsAt:   c.EndsAt.Format(time.RFC3339),
-//	}
-//}
+func CompetitionToDTO(c model.Competition) dto.CompetitionResponse {
+	return dto.CompetitionResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		StartsAt:    c.StartsAt,
+		EndsAt:      c.EndsAt,
+		Status:      c.Status,
+		WindowMode:  c.WindowMode,
+		FinalizedAt: c.FinalizedAt,
+		FinalizedBy: c.FinalizedBy,
+		ResultsHash: c.ResultsHash,
+	}
+}
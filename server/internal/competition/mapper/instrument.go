@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func InstrumentStatsToDTO(stats []model.InstrumentStats) []dto.InstrumentStatsResponse {
+	out := make([]dto.InstrumentStatsResponse, 0, len(stats))
+
+	for _, st := range stats {
+		out = append(out, dto.InstrumentStatsResponse{
+			Instrument: st.Instrument,
+			AssetClass: st.AssetClass,
+			TradeCount: st.TradeCount,
+			Wins:       st.Wins,
+			Volume:     st.Volume,
+			NetProfit:  st.NetProfit,
+			Pips:       st.Pips,
+		})
+	}
+
+	return out
+}
@@ -6,18 +6,32 @@ import (
 	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 )
 
-func LeaderboardFromDB(rows []sqlc.GetCompetitionLeaderboardRow) []model.LeaderboardEntry {
+func LeaderboardFromResults(rows []sqlc.CompetitionResult) []model.LeaderboardEntry {
 	entries := make([]model.LeaderboardEntry, 0, len(rows))
 
 	for _, r := range rows {
 		entries = append(entries, model.LeaderboardEntry{
-			TradingAccountLogin: r.TradingAccountLogin,
-			Rank:                r.Rank,
-			Username:            r.Username,
-			AccountSize:         r.AccountSize,
-			Profit:              r.Profit,
-			Equity:              r.Equity,
-			GainPercent:         r.GainPercent,
+			TradingAccountLogin:    r.TradingAccountLogin,
+			Rank:                   r.Rank,
+			Username:               r.Username,
+			AccountSize:            r.AccountSize,
+			Profit:                 r.Profit,
+			FloatingProfit:         r.FloatingProfit,
+			NetDeposits:            r.NetDeposits,
+			Equity:                 r.Equity,
+			GainPercent:            r.GainPercent,
+			DisqualifiedAt:         r.DisqualifiedAt,
+			DisqualificationReason: r.DisqualificationReason,
+			Metrics: model.Metrics{
+				MaxDrawdownPercent: r.MaxDrawdownPercent,
+				Sharpe:             r.Sharpe,
+				Sortino:            r.Sortino,
+				ProfitFactor:       r.ProfitFactor,
+				WinRate:            r.WinRate,
+				AverageR:           r.AverageR,
+				TradeCount:         r.TradeCount,
+			},
+			Score: r.Score,
 		})
 	}
 
@@ -29,15 +43,33 @@ func LeaderboardToDTO(entries []model.LeaderboardEntry) []dto.LeaderboardEntryRe
 
 	for _, e := range entries {
 		out = append(out, dto.LeaderboardEntryResponse{
-			TradingAccountLogin: e.TradingAccountLogin,
-			Rank:                e.Rank,
-			Username:            e.Username,
-			AccountSize:         e.AccountSize,
-			Profit:              e.Profit,
-			Equity:              e.Equity,
-			GainPercent:         e.GainPercent,
+			TradingAccountLogin:    e.TradingAccountLogin,
+			Rank:                   e.Rank,
+			Username:               e.Username,
+			AccountSize:            e.AccountSize,
+			Profit:                 e.Profit,
+			FloatingProfit:         e.FloatingProfit,
+			NetDeposits:            e.NetDeposits,
+			Equity:                 e.Equity,
+			GainPercent:            e.GainPercent,
+			Disqualified:           e.DisqualifiedAt != nil,
+			DisqualificationReason: e.DisqualificationReason,
+			RankChange:             e.RankChange,
+			GainChange:             e.GainChange,
+			BestRank:               e.BestRank,
+			Metrics:                MetricsToDTO(e.Metrics),
+			Score:                  e.Score,
 		})
 	}
 
 	return out
 }
+
+func LeaderboardPageToDTO(page model.LeaderboardPage) dto.LeaderboardPageResponse {
+	return dto.LeaderboardPageResponse{
+		Entries: LeaderboardToDTO(page.Entries),
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	}
+}
@@ -0,0 +1,70 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func OpenPositionsFromDB(rows []sqlc.OpenPosition) []model.OpenPosition {
+	positions := make([]model.OpenPosition, 0, len(rows))
+
+	for _, r := range rows {
+		positions = append(positions, model.OpenPosition{
+			PositionID:   r.PositionID,
+			Symbol:       r.Symbol,
+			Side:         r.Side,
+			Volume:       r.Volume,
+			OpenTime:     r.OpenTime,
+			OpenPrice:    r.OpenPrice,
+			CurrentPrice: r.CurrentPrice,
+			Profit:       r.Profit,
+			Commission:   r.Commission,
+			Swap:         r.Swap,
+		})
+	}
+
+	return positions
+}
+
+func OpenPositionsFromDTO(items []dto.OpenPositionDTO) []model.OpenPosition {
+	positions := make([]model.OpenPosition, 0, len(items))
+
+	for _, it := range items {
+		positions = append(positions, model.OpenPosition{
+			PositionID:   it.PositionID,
+			Symbol:       it.Symbol,
+			Side:         it.Side,
+			Volume:       it.Volume,
+			OpenTime:     it.OpenTime,
+			OpenPrice:    it.OpenPrice,
+			CurrentPrice: it.CurrentPrice,
+			Profit:       it.Profit,
+			Commission:   it.Commission,
+			Swap:         it.Swap,
+		})
+	}
+
+	return positions
+}
+
+func OpenPositionsToDTO(positions []model.OpenPosition) []dto.OpenPositionDTO {
+	out := make([]dto.OpenPositionDTO, 0, len(positions))
+
+	for _, p := range positions {
+		out = append(out, dto.OpenPositionDTO{
+			PositionID:   p.PositionID,
+			Symbol:       p.Symbol,
+			Side:         p.Side,
+			Volume:       p.Volume,
+			OpenTime:     p.OpenTime,
+			OpenPrice:    p.OpenPrice,
+			CurrentPrice: p.CurrentPrice,
+			Profit:       p.Profit,
+			Commission:   p.Commission,
+			Swap:         p.Swap,
+		})
+	}
+
+	return out
+}
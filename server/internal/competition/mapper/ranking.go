@@ -0,0 +1,114 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func RankingFromDB(row sqlc.CompetitionRanking) (model.Ranking, error) {
+	var weights map[model.Metric]float64
+	if err := json.Unmarshal(row.Weights, &weights); err != nil {
+		return model.Ranking{}, fmt.Errorf("decode ranking weights: %w", err)
+	}
+
+	tieBreaks := make([]model.Metric, 0, len(row.TieBreaks))
+	for _, m := range row.TieBreaks {
+		tieBreaks = append(tieBreaks, model.Metric(m))
+	}
+
+	return model.Ranking{
+		CompetitionID: row.CompetitionID,
+		Mode:          model.RankingMode(row.Mode),
+		Weights:       weights,
+		TieBreaks:     tieBreaks,
+		EquityMode:    model.EquityMode(row.EquityMode),
+		UpdatedAt:     row.UpdatedAt,
+	}, nil
+}
+
+func RankingToUpsertParams(r model.Ranking) (sqlc.UpsertCompetitionRankingParams, error) {
+	weights := r.Weights
+	if weights == nil {
+		weights = map[model.Metric]float64{}
+	}
+	encoded, err := json.Marshal(weights)
+	if err != nil {
+		return sqlc.UpsertCompetitionRankingParams{}, fmt.Errorf("encode ranking weights: %w", err)
+	}
+
+	tieBreaks := make([]string, 0, len(r.TieBreaks))
+	for _, m := range r.TieBreaks {
+		tieBreaks = append(tieBreaks, string(m))
+	}
+
+	return sqlc.UpsertCompetitionRankingParams{
+		CompetitionID: r.CompetitionID,
+		Mode:          string(r.Mode),
+		Weights:       encoded,
+		TieBreaks:     tieBreaks,
+		EquityMode:    string(r.EquityMode),
+	}, nil
+}
+
+func RankingFromDTO(req dto.CompetitionRankingRequest) model.Ranking {
+	weights := make(map[model.Metric]float64, len(req.Weights))
+	for m, w := range req.Weights {
+		weights[model.Metric(m)] = w
+	}
+
+	tieBreaks := make([]model.Metric, 0, len(req.TieBreaks))
+	for _, m := range req.TieBreaks {
+		tieBreaks = append(tieBreaks, model.Metric(m))
+	}
+
+	return model.Ranking{
+		Mode:       model.RankingMode(req.Mode),
+		Weights:    weights,
+		TieBreaks:  tieBreaks,
+		EquityMode: model.EquityMode(req.EquityMode),
+	}
+}
+
+func RankingToDTO(r model.Ranking) dto.CompetitionRankingResponse {
+	weights := make(map[string]float64, len(r.Weights))
+	for m, w := range r.Weights {
+		weights[string(m)] = w
+	}
+
+	tieBreaks := make([]string, 0, len(r.TieBreaks))
+	for _, m := range r.TieBreaks {
+		tieBreaks = append(tieBreaks, string(m))
+	}
+
+	return dto.CompetitionRankingResponse{
+		CompetitionID: r.CompetitionID,
+		Mode:          string(r.Mode),
+		Weights:       weights,
+		TieBreaks:     tieBreaks,
+		EquityMode:    string(r.EquityMode),
+	}
+}
+
+func MetricsToDTO(m model.Metrics) dto.MetricsResponse {
+	return dto.MetricsResponse{
+		MaxDrawdownPercent: m.MaxDrawdownPercent,
+		Sharpe:             finite(m.Sharpe),
+		Sortino:            finite(m.Sortino),
+		ProfitFactor:       finite(m.ProfitFactor),
+		WinRate:            m.WinRate,
+		AverageR:           m.AverageR,
+		TradeCount:         m.TradeCount,
+	}
+}
+
+func finite(v float64) *float64 {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
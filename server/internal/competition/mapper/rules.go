@@ -0,0 +1,35 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func RulesFromDB(row sqlc.CompetitionRule) model.Rules {
+	return model.Rules{
+		CompetitionID:       row.CompetitionID,
+		MaxDrawdownPercent:  row.MaxDrawdownPercent,
+		MaxDailyLossPercent: row.MaxDailyLossPercent,
+		MaxLotSize:          row.MaxLotSize,
+		AllowedSymbols:      row.AllowedSymbols,
+		MinTradingDays:      row.MinTradingDays,
+		UpdatedAt:           row.UpdatedAt,
+	}
+}
+
+func RulesToDTO(r model.Rules) dto.CompetitionRulesResponse {
+	symbols := r.AllowedSymbols
+	if symbols == nil {
+		symbols = []string{}
+	}
+
+	return dto.CompetitionRulesResponse{
+		CompetitionID:       r.CompetitionID,
+		MaxDrawdownPercent:  r.MaxDrawdownPercent,
+		MaxDailyLossPercent: r.MaxDailyLossPercent,
+		MaxLotSize:          r.MaxLotSize,
+		AllowedSymbols:      symbols,
+		MinTradingDays:      r.MinTradingDays,
+	}
+}
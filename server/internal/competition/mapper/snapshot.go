@@ -0,0 +1,53 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func SnapshotEntriesFromDB(rows []sqlc.GetLatestLeaderboardSnapshotEntriesRow) []model.SnapshotEntry {
+	entries := make([]model.SnapshotEntry, 0, len(rows))
+
+	for _, r := range rows {
+		entries = append(entries, model.SnapshotEntry{
+			TradingAccountLogin: r.TradingAccountLogin,
+			Rank:                r.Rank,
+			GainPercent:         r.GainPercent,
+		})
+	}
+
+	return entries
+}
+
+func RankHistoryFromDB(rows []sqlc.ListMemberRankHistoryRow) []model.RankHistoryPoint {
+	points := make([]model.RankHistoryPoint, 0, len(rows))
+
+	for _, r := range rows {
+		points = append(points, model.RankHistoryPoint{
+			TakenAt:     r.TakenAt,
+			Rank:        r.Rank,
+			Profit:      r.Profit,
+			Equity:      r.Equity,
+			GainPercent: r.GainPercent,
+		})
+	}
+
+	return points
+}
+
+func RankHistoryToDTO(points []model.RankHistoryPoint) []dto.RankHistoryPointResponse {
+	out := make([]dto.RankHistoryPointResponse, 0, len(points))
+
+	for _, p := range points {
+		out = append(out, dto.RankHistoryPointResponse{
+			TakenAt:     p.TakenAt,
+			Rank:        p.Rank,
+			Profit:      p.Profit,
+			Equity:      p.Equity,
+			GainPercent: p.GainPercent,
+		})
+	}
+
+	return out
+}
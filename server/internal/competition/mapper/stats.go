@@ -0,0 +1,87 @@
+package mapper
+
+import (
+	"github.com/filipcvejic/trading_tournament/internal/analytics"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+)
+
+func MemberStatsToDTO(r analytics.Report) dto.MemberStatsResponse {
+	resp := dto.MemberStatsResponse{
+		TradeCount:            r.TradeCount,
+		NetProfit:             r.NetProfit,
+		EquityCurve:           make([]dto.EquityPointResponse, 0, len(r.EquityCurve)),
+		DailyPnL:              make([]dto.DailyPnLResponse, 0, len(r.DailyPnL)),
+		Symbols:               make([]dto.SymbolStatsResponse, 0, len(r.Symbols)),
+		Long:                  sideStatsToDTO(r.Long),
+		Short:                 sideStatsToDTO(r.Short),
+		AverageHoldingSeconds: r.AverageHolding.Seconds(),
+		LargestWin:            tradeResultToDTO(r.LargestWin),
+		LargestLoss:           tradeResultToDTO(r.LargestLoss),
+		Streaks: dto.StreaksResponse{
+			MaxWins:   r.Streaks.MaxWins,
+			MaxLosses: r.Streaks.MaxLosses,
+			Current:   r.Streaks.Current,
+		},
+		MaxDrawdown: dto.DrawdownResponse{
+			Amount:      r.Drawdown.Amount,
+			Percent:     r.Drawdown.Percent,
+			PeakAt:      r.Drawdown.PeakAt,
+			RecoveredAt: r.Drawdown.RecoveredAt,
+		},
+	}
+
+	if !r.Drawdown.TroughAt.IsZero() {
+		troughAt := r.Drawdown.TroughAt
+		resp.MaxDrawdown.TroughAt = &troughAt
+	}
+
+	for _, p := range r.EquityCurve {
+		resp.EquityCurve = append(resp.EquityCurve, dto.EquityPointResponse{
+			Time:       p.Time,
+			PositionID: p.PositionID,
+			Equity:     p.Equity,
+		})
+	}
+
+	for _, d := range r.DailyPnL {
+		resp.DailyPnL = append(resp.DailyPnL, dto.DailyPnLResponse{
+			Date:       d.Date,
+			NetProfit:  d.NetProfit,
+			TradeCount: d.TradeCount,
+		})
+	}
+
+	for _, s := range r.Symbols {
+		resp.Symbols = append(resp.Symbols, dto.SymbolStatsResponse{
+			Symbol:     s.Symbol,
+			TradeCount: s.TradeCount,
+			Wins:       s.Wins,
+			Volume:     s.Volume,
+			NetProfit:  s.NetProfit,
+		})
+	}
+
+	return resp
+}
+
+func sideStatsToDTO(s analytics.SideStats) dto.SideStatsResponse {
+	return dto.SideStatsResponse{
+		TradeCount: s.TradeCount,
+		Wins:       s.Wins,
+		NetProfit:  s.NetProfit,
+		WinRate:    s.WinRate,
+	}
+}
+
+func tradeResultToDTO(t *analytics.TradeResult) *dto.TradeResultResponse {
+	if t == nil {
+		return nil
+	}
+
+	return &dto.TradeResultResponse{
+		PositionID: t.PositionID,
+		Symbol:     t.Symbol,
+		NetProfit:  t.NetProfit,
+		CloseTime:  t.CloseTime,
+	}
+}
@@ -0,0 +1,179 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func TradesFromDB(rows []sqlc.Trade) []model.Trade {
+	trades := make([]model.Trade, 0, len(rows))
+
+	for _, r := range rows {
+		trades = append(trades, model.Trade{
+			TradingAccountLogin: r.TradingAccountLogin,
+			CompetitionID:       r.CompetitionID,
+			PositionID:          r.PositionID,
+			Symbol:              r.Symbol,
+			Side:                r.Side,
+			Volume:              r.Volume,
+			OpenTime:            r.OpenTime,
+			CloseTime:           r.CloseTime,
+			OpenPrice:           r.OpenPrice,
+			ClosePrice:          r.ClosePrice,
+			Profit:              r.Profit,
+			Commission:          r.Commission,
+			Swap:                r.Swap,
+			Version:             r.Version,
+			Instrument:          r.Instrument.String,
+		})
+	}
+
+	return trades
+}
+
+func TradeOutcomesToDTO(outcomes []model.TradeOutcome) dto.InsertTradesResponse {
+	resp := dto.InsertTradesResponse{Results: make([]dto.TradeOutcomeResponse, 0, len(outcomes))}
+
+	for _, o := range outcomes {
+		switch o.Status {
+		case model.TradeInserted:
+			resp.Inserted++
+		case model.TradeDuplicate:
+			resp.Duplicates++
+		case model.TradeUpdated:
+			resp.Updated++
+		case model.TradeRejected:
+			resp.Rejected++
+		case model.TradeQuarantined:
+			resp.Quarantined++
+		}
+
+		resp.Results = append(resp.Results, dto.TradeOutcomeResponse{
+			Index:      o.Index,
+			PositionID: o.PositionID,
+			Status:     string(o.Status),
+			Reason:     o.Reason,
+			Version:    o.Version,
+			Changed:    o.ChangedFields,
+		})
+	}
+
+	return resp
+}
+
+func TradeRevisionsFromDB(rows []sqlc.TradeRevision) ([]model.TradeRevision, error) {
+	revisions := make([]model.TradeRevision, 0, len(rows))
+
+	for _, r := range rows {
+		var changes map[string]model.TradeFieldChange
+		if err := json.Unmarshal(r.Changes, &changes); err != nil {
+			return nil, fmt.Errorf("decode trade revision %d: %w", r.ID, err)
+		}
+
+		revisions = append(revisions, model.TradeRevision{
+			PositionID: r.PositionID,
+			Version:    r.Version,
+			Changes:    changes,
+			RevisedAt:  r.RevisedAt,
+		})
+	}
+
+	return revisions, nil
+}
+
+func TradeRevisionsToDTO(revisions []model.TradeRevision) []dto.TradeRevisionResponse {
+	out := make([]dto.TradeRevisionResponse, 0, len(revisions))
+
+	for _, r := range revisions {
+		changes := make(map[string]dto.TradeFieldChangeResponse, len(r.Changes))
+		for field, c := range r.Changes {
+			changes[field] = dto.TradeFieldChangeResponse{From: c.From, To: c.To}
+		}
+
+		out = append(out, dto.TradeRevisionResponse{
+			PositionID: r.PositionID,
+			Version:    r.Version,
+			Changes:    changes,
+			RevisedAt:  r.RevisedAt,
+		})
+	}
+
+	return out
+}
+
+func QuarantinedTradesFromDB(rows []sqlc.TradeQuarantine) []model.QuarantinedTrade {
+	out := make([]model.QuarantinedTrade, 0, len(rows))
+
+	for _, r := range rows {
+		out = append(out, QuarantinedTradeFromDB(r))
+	}
+
+	return out
+}
+
+func QuarantinedTradeFromDB(row sqlc.TradeQuarantine) model.QuarantinedTrade {
+	return model.QuarantinedTrade{
+		ID: row.ID,
+		Trade: model.Trade{
+			TradingAccountLogin: row.TradingAccountLogin,
+			CompetitionID:       row.CompetitionID,
+			PositionID:          row.PositionID,
+			Symbol:              row.Symbol,
+			Side:                row.Side,
+			Volume:              row.Volume,
+			OpenTime:            row.OpenTime,
+			CloseTime:           row.CloseTime,
+			OpenPrice:           row.OpenPrice,
+			ClosePrice:          row.ClosePrice,
+			Profit:              row.Profit,
+			Commission:          row.Commission,
+			Swap:                row.Swap,
+			Instrument:          row.Instrument.String,
+		},
+		Reason:        row.Reason,
+		Status:        model.QuarantineStatus(row.Status),
+		QuarantinedAt: row.QuarantinedAt,
+		ReviewedBy:    row.ReviewedBy,
+		ReviewedAt:    row.ReviewedAt,
+	}
+}
+
+func QuarantinedTradesToDTO(trades []model.QuarantinedTrade) []dto.QuarantinedTradeResponse {
+	out := make([]dto.QuarantinedTradeResponse, 0, len(trades))
+
+	for _, q := range trades {
+		out = append(out, QuarantinedTradeToDTO(q))
+	}
+
+	return out
+}
+
+func QuarantinedTradeToDTO(q model.QuarantinedTrade) dto.QuarantinedTradeResponse {
+	t := q.Trade
+	return dto.QuarantinedTradeResponse{
+		ID:                  q.ID,
+		TradingAccountLogin: t.TradingAccountLogin,
+		Trade: dto.TradeDTO{
+			PositionID: t.PositionID,
+			Symbol:     t.Symbol,
+			Side:       t.Side,
+			Volume:     t.Volume,
+			OpenTime:   t.OpenTime,
+			CloseTime:  t.CloseTime,
+			OpenPrice:  t.OpenPrice,
+			ClosePrice: t.ClosePrice,
+			Profit:     t.Profit,
+			Commission: t.Commission,
+			Swap:       t.Swap,
+		},
+		Reason:        q.Reason,
+		Status:        string(q.Status),
+		QuarantinedAt: q.QuarantinedAt,
+		ReviewedBy:    q.ReviewedBy,
+		ReviewedAt:    q.ReviewedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountSizeSource records who set a member's account size.
+type AccountSizeSource string
+
+const (
+	AccountSizeFromSnapshot AccountSizeSource = "snapshot"
+	AccountSizeFromAPI      AccountSizeSource = "api"
+	AccountSizeFromAdmin    AccountSizeSource = "admin"
+)
+
+// AccountSnapshot is the state of a member's trading account as reported by
+// the collector.
+type AccountSnapshot struct {
+	Balance  float64
+	Equity   float64
+	Currency string
+	Leverage int32
+	TakenAt  time.Time
+}
+
+// AccountSizeChange is a request to set a member's account size. TakenAt is
+// only set for changes coming from a snapshot.
+type AccountSizeChange struct {
+	Size      float64
+	Source    AccountSizeSource
+	TakenAt   *time.Time
+	ChangedBy *uuid.UUID
+	APIKeyID  *uuid.UUID
+	Reason    string
+}
+
+type AccountSizeAuditEntry struct {
+	ID        int64
+	OldSize   float64
+	NewSize   float64
+	Source    AccountSizeSource
+	ChangedBy *uuid.UUID
+	APIKeyID  *uuid.UUID
+	Reason    string
+	CreatedAt time.Time
+}
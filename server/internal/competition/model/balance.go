@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BalanceOperationType string
+
+const (
+	BalanceDeposit    BalanceOperationType = "deposit"
+	BalanceWithdrawal BalanceOperationType = "withdrawal"
+	BalanceCredit     BalanceOperationType = "credit"
+	BalanceBonus      BalanceOperationType = "bonus"
+)
+
+// BalanceOperation is a non-trading change to a member's balance reported by
+// the broker. Amount is always positive; Type gives the direction.
+type BalanceOperation struct {
+	TradingAccountLogin int64
+	OperationID         int64
+	Type                BalanceOperationType
+	Amount              float64
+	OccurredAt          time.Time
+	Comment             string
+}
+
+type BalanceIngestResult struct {
+	Inserted   int
+	Duplicates int
+	Flagged    int
+}
+
+type BalanceReviewStatus string
+
+const (
+	BalanceReviewPending      BalanceReviewStatus = "pending"
+	BalanceReviewApproved     BalanceReviewStatus = "approved"
+	BalanceReviewDisqualified BalanceReviewStatus = "disqualified"
+)
+
+// BalanceReview is an unexpected balance operation waiting for, or resolved
+// by, an admin.
+type BalanceReview struct {
+	ID         int64
+	Operation  BalanceOperation
+	Reason     string
+	Status     BalanceReviewStatus
+	CreatedAt  time.Time
+	ReviewedBy *uuid.UUID
+	ReviewedAt *time.Time
+}
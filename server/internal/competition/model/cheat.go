@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CheatKind string
+
+const (
+	// CheatCopyTrading is two accounts opening the same trades at the same
+	// time.
+	CheatCopyTrading CheatKind = "copy_trading"
+	// CheatHedging is two accounts holding opposite positions, so one of
+	// them wins whichever way the market moves.
+	CheatHedging CheatKind = "hedging"
+)
+
+type CheatCaseStatus string
+
+const (
+	CheatCaseOpen         CheatCaseStatus = "open"
+	CheatCaseDismissed    CheatCaseStatus = "dismissed"
+	CheatCaseDisqualified CheatCaseStatus = "disqualified"
+)
+
+// CheatCase is a pair of members whose trading looks coordinated, waiting
+// for or resolved by an admin. LoginA is always the lower login.
+type CheatCase struct {
+	ID            int64
+	CompetitionID uuid.UUID
+	LoginA        int64
+	LoginB        int64
+	Kind          CheatKind
+	// Score is between 0 and 100; higher is more suspicious.
+	Score         float64
+	MatchedTrades int32
+	Evidence      CheatEvidence
+	Status        CheatCaseStatus
+	DetectedAt    time.Time
+	UpdatedAt     time.Time
+	ResolvedBy    *uuid.UUID
+	ResolvedAt    *time.Time
+}
+
+// CheatEvidence is stored with the case so a reviewer sees what the detector
+// saw, even after trades are corrected.
+type CheatEvidence struct {
+	TradesA       int          `json:"tradesA"`
+	TradesB       int          `json:"tradesB"`
+	MatchRatio    float64      `json:"matchRatio"`
+	SimilarVolume int          `json:"similarVolume"`
+	Matches       []CheatMatch `json:"matches"`
+}
+
+// CheatMatch pairs a trade of each account that were opened within the
+// detection window on the same instrument.
+type CheatMatch struct {
+	Symbol       string    `json:"symbol"`
+	PositionA    int64     `json:"positionA"`
+	PositionB    int64     `json:"positionB"`
+	SideA        string    `json:"sideA"`
+	SideB        string    `json:"sideB"`
+	VolumeA      float64   `json:"volumeA"`
+	VolumeB      float64   `json:"volumeB"`
+	OpenTimeA    time.Time `json:"openTimeA"`
+	OpenTimeB    time.Time `json:"openTimeB"`
+	DelaySeconds float64   `json:"delaySeconds"`
+}
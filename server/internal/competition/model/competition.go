@@ -5,10 +5,42 @@ import (
 	"time"
 )
 
+type Status string
+
+const (
+	StatusDraft            Status = "draft"
+	StatusRegistrationOpen Status = "registration_open"
+	StatusRunning          Status = "running"
+	StatusSettling         Status = "settling"
+	StatusFinalized        Status = "finalized"
+	StatusArchived         Status = "archived"
+)
+
+// WindowMode decides which trades count towards a competition.
+type WindowMode string
+
+const (
+	// WindowCloseTime counts trades closed inside the window.
+	WindowCloseTime WindowMode = "close_time"
+	// WindowOpenInside counts only trades both opened and closed inside the
+	// window.
+	WindowOpenInside WindowMode = "open_inside"
+	// WindowProRate counts every trade overlapping the window, with its
+	// profit, commission and swap scaled by the share of its duration spent
+	// inside it.
+	WindowProRate WindowMode = "pro_rate"
+)
+
 type Competition struct {
-	ID        uuid.UUID
-	Name      string
-	StartsAt  time.Time
-	EndsAt    time.Time
-	CreatedAt time.Time
+	ID              uuid.UUID
+	Name            string
+	StartsAt        time.Time
+	EndsAt          time.Time
+	Status          Status
+	StatusChangedAt time.Time
+	FinalizedAt     *time.Time
+	FinalizedBy     *uuid.UUID
+	ResultsHash     string
+	WindowMode      WindowMode
+	CreatedAt       time.Time
 }
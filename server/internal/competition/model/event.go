@@ -0,0 +1,14 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Event describes a competition moving from one lifecycle status to another.
+type Event struct {
+	CompetitionID uuid.UUID
+	From          Status
+	To            Status
+	At            time.Time
+}
@@ -0,0 +1,14 @@
+package model
+
+// InstrumentStats sums a member's trades on one instrument. Trades whose
+// symbol is not in the catalogue are grouped under the raw symbol, without
+// an asset class or pips.
+type InstrumentStats struct {
+	Instrument string
+	AssetClass string
+	TradeCount int32
+	Wins       int32
+	Volume     float64
+	NetProfit  float64
+	Pips       float64
+}
@@ -1,11 +1,60 @@
 package model
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type LeaderboardEntry struct {
-	TradingAccountLogin int64
-	Rank                int32
-	Username            string
-	AccountSize         float64
-	Profit              float64
-	Equity              float64
-	GainPercent         float64
+	TradingAccountLogin    int64
+	Rank                   int32
+	Username               string
+	AccountSize            float64
+	Profit                 float64
+	FloatingProfit         float64
+	Equity                 float64
+	GainPercent            float64
+	DisqualifiedAt         *time.Time
+	DisqualificationReason string
+
+	// NetDeposits sums balance operations made since the competition
+	// started.
+	NetDeposits float64
+
+	// Movement since the previous leaderboard snapshot; nil when the member
+	// was not in it.
+	RankChange *int32
+	GainChange *float64
+	BestRank   int32
+
+	Metrics Metrics
+	// Score is set only when the competition ranks by a weighted score.
+	Score *float64
+}
+
+type MemberFilter string
+
+const (
+	MemberFilterAll          MemberFilter = ""
+	MemberFilterActive       MemberFilter = "active"
+	MemberFilterDisqualified MemberFilter = "disqualified"
+)
+
+type LeaderboardQuery struct {
+	Limit          int32
+	Offset         int32
+	UsernamePrefix string
+	Status         MemberFilter
+	MinTrades      int32
+	// AroundUserID, when set, replaces Offset with a window centred on that
+	// user's best ranked account.
+	AroundUserID *uuid.UUID
+}
+
+type LeaderboardPage struct {
+	Entries []LeaderboardEntry
+	Total   int
+	Limit   int32
+	Offset  int32
 }
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// OpenPosition is a position still open at the broker. Profit, Commission and
+// Swap are the floating amounts at the time of the snapshot.
+type OpenPosition struct {
+	PositionID   int64
+	Symbol       string
+	Side         string
+	Volume       float64
+	OpenTime     time.Time
+	OpenPrice    float64
+	CurrentPrice float64
+	Profit       float64
+	Commission   float64
+	Swap         float64
+}
+
+// OpenPositionSnapshot is the full set of a member's open positions at
+// TakenAt. It replaces the previous snapshot.
+type OpenPositionSnapshot struct {
+	TakenAt   time.Time
+	Positions []OpenPosition
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metric names a per-member statistic a competition can be ranked by.
+type Metric string
+
+const (
+	MetricGain         Metric = "gain"
+	MetricMaxDrawdown  Metric = "max_drawdown"
+	MetricSharpe       Metric = "sharpe"
+	MetricSortino      Metric = "sortino"
+	MetricProfitFactor Metric = "profit_factor"
+	MetricWinRate      Metric = "win_rate"
+	MetricAverageR     Metric = "average_r"
+	MetricTradeCount   Metric = "trade_count"
+)
+
+// RankingMode is either a single Metric or RankingWeighted.
+type RankingMode string
+
+const RankingWeighted RankingMode = "weighted"
+
+// EquityMode decides whether unrealized P&L of open positions counts towards
+// a member's equity and gain.
+type EquityMode string
+
+const (
+	EquityModeEquity   EquityMode = "equity"
+	EquityModeRealized EquityMode = "realized"
+)
+
+type Ranking struct {
+	CompetitionID uuid.UUID
+	Mode          RankingMode
+	// Weights are only used in weighted mode.
+	Weights map[Metric]float64
+	// TieBreaks are applied in order when the primary values are equal.
+	TieBreaks  []Metric
+	EquityMode EquityMode
+	UpdatedAt  time.Time
+}
+
+type Metrics struct {
+	MaxDrawdownPercent float64
+	Sharpe             float64
+	Sortino            float64
+	// ProfitFactor is +Inf when there are winning trades but no losing ones.
+	ProfitFactor float64
+	WinRate      float64
+	AverageR     float64
+	TradeCount   int32
+}
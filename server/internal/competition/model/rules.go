@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Rules struct {
+	CompetitionID       uuid.UUID
+	MaxDrawdownPercent  *float64
+	MaxDailyLossPercent *float64
+	MaxLotSize          *float64
+	AllowedSymbols      []string
+	MinTradingDays      int32
+	UpdatedAt           time.Time
+}
+
+type RuleBreach struct {
+	Rule   string
+	Reason string
+	// PositionID is the trade that caused the breach, or 0 when the breach
+	// is not tied to a single trade (e.g. too few trading days).
+	PositionID int64
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type SnapshotEntry struct {
+	TradingAccountLogin int64
+	Rank                int32
+	GainPercent         float64
+}
+
+type RankHistoryPoint struct {
+	TakenAt     time.Time
+	Rank        int32
+	Profit      float64
+	Equity      float64
+	GainPercent float64
+}
@@ -1,8 +1,10 @@
 package model
 
 import (
-	"github.com/google/uuid"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Trade struct {
@@ -19,4 +21,66 @@ type Trade struct {
 	Profit              float64
 	Commission          float64
 	Swap                float64
+	Version             int32
+	// Instrument is the canonical symbol, empty when the broker's symbol is
+	// not in the instrument catalogue.
+	Instrument string
+}
+
+type TradeOutcomeStatus string
+
+const (
+	TradeInserted  TradeOutcomeStatus = "inserted"
+	TradeDuplicate TradeOutcomeStatus = "duplicate"
+	TradeUpdated   TradeOutcomeStatus = "updated"
+	TradeRejected  TradeOutcomeStatus = "rejected"
+	// TradeQuarantined trades fall outside the competition window and wait
+	// for an admin instead of counting.
+	TradeQuarantined TradeOutcomeStatus = "quarantined"
+)
+
+// TradeOutcome reports what happened to one trade of an ingested batch.
+// Index is the trade's position in the submitted batch. Version and
+// ChangedFields are set for inserted and updated trades.
+type TradeOutcome struct {
+	Index         int
+	PositionID    int64
+	Status        TradeOutcomeStatus
+	Reason        string
+	Version       int32
+	ChangedFields []string
+}
+
+// TradeFieldChange holds the JSON-encoded values of one corrected field.
+type TradeFieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// TradeRevision records a correction that produced Version of a trade.
+type TradeRevision struct {
+	PositionID int64
+	Version    int32
+	Changes    map[string]TradeFieldChange
+	RevisedAt  time.Time
+}
+
+type QuarantineStatus string
+
+const (
+	QuarantinePending   QuarantineStatus = "pending"
+	QuarantineApproved  QuarantineStatus = "approved"
+	QuarantineDismissed QuarantineStatus = "dismissed"
+)
+
+// QuarantinedTrade is a trade held back because it falls outside the
+// competition window. Approving it counts the trade in full.
+type QuarantinedTrade struct {
+	ID            int64
+	Trade         Trade
+	Reason        string
+	Status        QuarantineStatus
+	QuarantinedAt time.Time
+	ReviewedBy    *uuid.UUID
+	ReviewedAt    *time.Time
 }
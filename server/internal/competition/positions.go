@@ -0,0 +1,78 @@
+package competition
+
+import (
+	"context"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// ReplaceOpenPositions records the member's current open positions and their
+// floating P&L, which counts towards equity and gain unless the competition
+// ranks on realized profit only.
+func (s *Service) ReplaceOpenPositions(ctx context.Context, competitionID uuid.UUID, login int64, snapshot model.OpenPositionSnapshot) error {
+	if competitionID == uuid.Nil {
+		return ErrNotFound
+	}
+	if login <= 0 {
+		return ErrInvalidLogin
+	}
+
+	seen := make(map[int64]bool, len(snapshot.Positions))
+	for _, p := range snapshot.Positions {
+		if err := validateOpenPosition(p); err != nil {
+			return err
+		}
+		if seen[p.PositionID] {
+			return ErrDuplicatePositionID
+		}
+		seen[p.PositionID] = true
+	}
+	if snapshot.TakenAt.IsZero() {
+		snapshot.TakenAt = time.Now()
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	if err := checkAcceptsTrades(c.Status); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReplaceOpenPositions(ctx, competitionID, login, snapshot); err != nil {
+		return err
+	}
+
+	s.publishLeaderboardChange(ctx, competitionID)
+	return nil
+}
+
+func (s *Service) GetOpenPositions(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.OpenPosition, error) {
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == model.StatusDraft {
+		return nil, ErrNotFound
+	}
+
+	return s.repo.ListOpenPositions(ctx, competitionID, login)
+}
+
+func validateOpenPosition(p model.OpenPosition) error {
+	if p.PositionID <= 0 {
+		return ErrInvalidPositionID
+	}
+	if p.Symbol == "" {
+		return ErrInvalidSymbol
+	}
+	if p.Side != "buy" && p.Side != "sell" {
+		return ErrInvalidSide
+	}
+	if p.Volume <= 0 {
+		return ErrInvalidVolume
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package competition
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+var rankingMetrics = []model.Metric{
+	model.MetricGain,
+	model.MetricMaxDrawdown,
+	model.MetricSharpe,
+	model.MetricSortino,
+	model.MetricProfitFactor,
+	model.MetricWinRate,
+	model.MetricAverageR,
+	model.MetricTradeCount,
+}
+
+// defaultTieBreaks apply when a competition does not configure its own.
+var defaultTieBreaks = []model.Metric{model.MetricGain, model.MetricMaxDrawdown}
+
+func validMetric(m model.Metric) bool {
+	for _, known := range rankingMetrics {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultRanking(competitionID uuid.UUID) model.Ranking {
+	return model.Ranking{
+		CompetitionID: competitionID,
+		Mode:          model.RankingMode(model.MetricGain),
+		EquityMode:    model.EquityModeEquity,
+	}
+}
+
+func (s *Service) GetRanking(ctx context.Context, competitionID uuid.UUID) (model.Ranking, error) {
+	if competitionID == uuid.Nil {
+		return model.Ranking{}, ErrNotFound
+	}
+
+	ranking, err := s.repo.GetRanking(ctx, competitionID)
+	if err == nil {
+		return ranking, nil
+	}
+	if !errors.Is(err, ErrRankingNotSet) {
+		return model.Ranking{}, err
+	}
+
+	// No ranking configured yet: existing competitions rank by gain.
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return model.Ranking{}, err
+	}
+	return defaultRanking(competitionID), nil
+}
+
+func (s *Service) SetRanking(ctx context.Context, ranking model.Ranking) (model.Ranking, error) {
+	if ranking.CompetitionID == uuid.Nil {
+		return model.Ranking{}, ErrNotFound
+	}
+	if ranking.EquityMode == "" {
+		ranking.EquityMode = model.EquityModeEquity
+	}
+	if err := validateRanking(ranking); err != nil {
+		return model.Ranking{}, err
+	}
+	if ranking.Mode != model.RankingWeighted {
+		ranking.Weights = nil
+	}
+
+	return s.repo.UpsertRanking(ctx, ranking)
+}
+
+func validateRanking(r model.Ranking) error {
+	if r.Mode != model.RankingWeighted && !validMetric(model.Metric(r.Mode)) {
+		return ErrInvalidRanking
+	}
+	if r.EquityMode != model.EquityModeEquity && r.EquityMode != model.EquityModeRealized {
+		return ErrInvalidRanking
+	}
+
+	if r.Mode == model.RankingWeighted {
+		var total float64
+		for m, w := range r.Weights {
+			if !validMetric(m) || w < 0 {
+				return ErrInvalidRanking
+			}
+			total += w
+		}
+		if total == 0 {
+			return ErrInvalidRanking
+		}
+	}
+
+	seen := make(map[model.Metric]bool, len(r.TieBreaks))
+	for _, m := range r.TieBreaks {
+		if !validMetric(m) || seen[m] {
+			return ErrInvalidRanking
+		}
+		seen[m] = true
+	}
+	return nil
+}
+
+// standings returns the complete live leaderboard of c, ranked the way the
+// competition is configured.
+func (s *Service) standings(ctx context.Context, c model.Competition) ([]model.LeaderboardEntry, error) {
+	ranking, err := s.GetRanking(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.repo.GetLeaderboard(ctx, c.ID, ranking, model.LeaderboardQuery{Limit: math.MaxInt32})
+	if err != nil {
+		return nil, err
+	}
+	return page.Entries, nil
+}
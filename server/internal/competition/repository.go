@@ -10,20 +10,58 @@ import (
 	"github.com/filipcvejic/trading_tournament/internal/competition/mapper"
 	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"time"
 )
 
 type Repository interface {
 	Create(ctx context.Context, c model.Competition) error
 	GetByID(ctx context.Context, id uuid.UUID) (model.Competition, error)
+	SetWindowMode(ctx context.Context, id uuid.UUID, mode model.WindowMode) error
 	JoinWithTradingAccount(ctx context.Context, competitionID uuid.UUID, userID uuid.UUID, login int64, broker string, investorPasswordEncrypted string) error
-	UpdateAccountSize(ctx context.Context, competitionID uuid.UUID, login int64, accountSize float64) error
+	SetAccountSize(ctx context.Context, competitionID uuid.UUID, login int64, change model.AccountSizeChange) (bool, error)
+	RecordAccountSnapshot(ctx context.Context, competitionID uuid.UUID, login int64, snapshot model.AccountSnapshot) error
+	ListAccountSizeAudit(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.AccountSizeAuditEntry, error)
 	GetMemberAccountSize(ctx context.Context, competitionID uuid.UUID, login int64) (float64, error)
-	GetLeaderboard(ctx context.Context, competitionID uuid.UUID, limit, offset int32) ([]model.LeaderboardEntry, error)
-	InsertTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade) error
+	GetBroker(ctx context.Context, login int64) (string, error)
+	GetLeaderboard(ctx context.Context, competitionID uuid.UUID, ranking model.Ranking, q model.LeaderboardQuery) (model.LeaderboardPage, error)
+	InsertTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade) (map[int64]model.TradeOutcome, error)
+	QuarantineTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.QuarantinedTrade) error
+	ListQuarantinedTrades(ctx context.Context, competitionID uuid.UUID, status model.QuarantineStatus) ([]model.QuarantinedTrade, error)
+	ResolveQuarantinedTrade(ctx context.Context, competitionID uuid.UUID, id int64, status model.QuarantineStatus, reviewedBy uuid.UUID) (model.QuarantinedTrade, error)
 	GetUserCompetitionState(ctx context.Context, userID, competitionID uuid.UUID) (sqlc.GetCompetitionUserStateRow, error)
 	GetCurrent(ctx context.Context) (sqlc.Competition, error)
 	CreateAccountRequest(ctx context.Context, userID, competitionID uuid.UUID) error
+	GetRules(ctx context.Context, competitionID uuid.UUID) (model.Rules, error)
+	UpsertRules(ctx context.Context, rules model.Rules) (model.Rules, error)
+	ListMemberTrades(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.Trade, error)
+	ListForeignPositions(ctx context.Context, competitionID uuid.UUID, login int64, positionIDs []int64) ([]int64, error)
+	ListTradeRevisions(ctx context.Context, competitionID uuid.UUID, login, positionID int64) ([]model.TradeRevision, error)
+	ReplaceOpenPositions(ctx context.Context, competitionID uuid.UUID, login int64, snapshot model.OpenPositionSnapshot) error
+	ListOpenPositions(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.OpenPosition, error)
+	InsertBalanceOperations(ctx context.Context, competitionID uuid.UUID, login int64, ops []model.BalanceOperation, flags map[int64]string) (model.BalanceIngestResult, error)
+	ListBalanceReviews(ctx context.Context, competitionID uuid.UUID, status model.BalanceReviewStatus) ([]model.BalanceReview, error)
+	ResolveBalanceReview(ctx context.Context, competitionID uuid.UUID, id int64, status model.BalanceReviewStatus, reviewedBy uuid.UUID) (model.BalanceReview, error)
+	DisqualifyMember(ctx context.Context, competitionID uuid.UUID, login int64, breach model.RuleBreach, at time.Time) error
+	ListByStatus(ctx context.Context, status model.Status) ([]model.Competition, error)
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to model.Status) error
+	Finalize(ctx context.Context, id uuid.UUID, ranking model.Ranking, resultsHash string, finalizedBy *uuid.UUID) error
+	ListResults(ctx context.Context, competitionID uuid.UUID, q model.LeaderboardQuery) (model.LeaderboardPage, error)
+	CreateSnapshot(ctx context.Context, competitionID uuid.UUID, entries []model.LeaderboardEntry) error
+	GetLatestSnapshot(ctx context.Context, competitionID uuid.UUID) ([]model.SnapshotEntry, error)
+	GetBestRanks(ctx context.Context, competitionID uuid.UUID) (map[int64]int32, error)
+	ListRankHistory(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.RankHistoryPoint, error)
+	GetRanking(ctx context.Context, competitionID uuid.UUID) (model.Ranking, error)
+	UpsertRanking(ctx context.Context, ranking model.Ranking) (model.Ranking, error)
+	ListTrades(ctx context.Context, competitionID uuid.UUID) ([]model.Trade, error)
+	RebuildMemberStats(ctx context.Context, competitionID uuid.UUID) (int64, error)
+	ListIDs(ctx context.Context) ([]uuid.UUID, error)
+	UpsertCheatCases(ctx context.Context, competitionID uuid.UUID, cases []model.CheatCase) error
+	ListCheatCases(ctx context.Context, competitionID uuid.UUID, status model.CheatCaseStatus) ([]model.CheatCase, error)
+	ResolveCheatCase(ctx context.Context, competitionID uuid.UUID, id int64, status model.CheatCaseStatus, resolvedBy uuid.UUID) (model.CheatCase, error)
 }
 
 type PostgresRepository struct {
@@ -36,14 +74,31 @@ func NewPostgresRepository(database *db.DB) *PostgresRepository {
 
 func (r *PostgresRepository) Create(ctx context.Context, c model.Competition) error {
 	_, err := r.db.Query.CreateCompetition(ctx, sqlc.CreateCompetitionParams{
-		ID:       c.ID,
-		Name:     c.Name,
-		StartsAt: c.StartsAt,
-		EndsAt:   c.EndsAt,
+		ID:         c.ID,
+		Name:       c.Name,
+		StartsAt:   c.StartsAt,
+		EndsAt:     c.EndsAt,
+		WindowMode: string(c.WindowMode),
 	})
 	return err
 }
 
+// SetWindowMode changes how trades are matched against the competition
+// window. It fails with ErrWindowModeLocked once the competition is running.
+func (r *PostgresRepository) SetWindowMode(ctx context.Context, id uuid.UUID, mode model.WindowMode) error {
+	n, err := r.db.Query.UpdateCompetitionWindowMode(ctx, sqlc.UpdateCompetitionWindowModeParams{
+		WindowMode: string(mode),
+		ID:         id,
+	})
+	if err != nil {
+		return fmt.Errorf("update window mode: %w", err)
+	}
+	if n == 0 {
+		return ErrWindowModeLocked
+	}
+	return nil
+}
+
 func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (model.Competition, error) {
 	row, err := r.db.Query.GetCompetitionByID(ctx, id)
 	if err != nil {
@@ -101,35 +156,144 @@ func (r *PostgresRepository) JoinWithTradingAccount(
 			return ErrAlreadyJoined
 		}
 
-		// No rows => either not found OR already started (jer WHERE uslov nije prošao)
+		// No rows => not found, registration not open yet OR already started (jer WHERE uslov nije prošao)
 		if errors.Is(err, sql.ErrNoRows) {
-			_, err2 := q.GetCompetitionStartTime(ctx, competitionID)
-			if err2 == nil {
-				return ErrAlreadyStarted
+			c, err2 := q.GetCompetitionByID(ctx, competitionID)
+			if err2 != nil {
+				if errors.Is(err2, sql.ErrNoRows) {
+					return ErrNotFound
+				}
+				return err2
 			}
-			if errors.Is(err2, sql.ErrNoRows) {
-				return ErrNotFound
+			if model.Status(c.Status) == model.StatusDraft {
+				return ErrRegistrationClosed
 			}
-			return err2
+			return ErrAlreadyStarted
 		}
 
 		return err
 	})
 }
 
-func (r *PostgresRepository) UpdateAccountSize(ctx context.Context, competitionID uuid.UUID, login int64, accountSize float64) error {
-	err := r.db.Query.UpdateCompetitionMemberAccountSize(ctx, sqlc.UpdateCompetitionMemberAccountSizeParams{
+// SetAccountSize applies change and records it in the audit trail. A change
+// from a snapshot only applies while the size has not been set by hand and
+// no earlier snapshot has set it; false is returned when it was skipped.
+func (r *PostgresRepository) SetAccountSize(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	login int64,
+	change model.AccountSizeChange,
+) (bool, error) {
+	applied := false
+
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenCompetition(ctx, q, competitionID); err != nil {
+			return err
+		}
+
+		current, err := q.LockCompetitionMemberAccountSize(ctx, sqlc.LockCompetitionMemberAccountSizeParams{
+			CompetitionID:       competitionID,
+			TradingAccountLogin: login,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return fmt.Errorf("lock account size: %w", err)
+		}
+
+		if change.Source == model.AccountSizeFromSnapshot && current.AccountSizeSource.Valid {
+			if model.AccountSizeSource(current.AccountSizeSource.String) != model.AccountSizeFromSnapshot {
+				return nil
+			}
+			if current.AccountSizeTakenAt != nil && !change.TakenAt.Before(*current.AccountSizeTakenAt) {
+				return nil
+			}
+		}
+
+		err = q.UpdateCompetitionMemberAccountSize(ctx, sqlc.UpdateCompetitionMemberAccountSizeParams{
+			CompetitionID:       competitionID,
+			TradingAccountLogin: login,
+			AccountSize:         change.Size,
+			AccountSizeSource:   pgtype.Text{String: string(change.Source), Valid: true},
+			AccountSizeTakenAt:  change.TakenAt,
+		})
+		if err != nil {
+			return fmt.Errorf("update account size: %w", err)
+		}
+
+		err = q.InsertAccountSizeAudit(ctx, sqlc.InsertAccountSizeAuditParams{
+			CompetitionID:       competitionID,
+			TradingAccountLogin: login,
+			OldSize:             current.AccountSize,
+			NewSize:             change.Size,
+			Source:              string(change.Source),
+			ChangedBy:           change.ChangedBy,
+			ApiKeyID:            change.APIKeyID,
+			Reason:              change.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("insert account size audit: %w", err)
+		}
+
+		// Drawdown is measured against the account size.
+		err = q.RefreshCompetitionMemberDrawdown(ctx, sqlc.RefreshCompetitionMemberDrawdownParams{
+			CompetitionID:       competitionID,
+			TradingAccountLogin: pgtype.Int8{Int64: login, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("refresh member drawdown: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+func (r *PostgresRepository) RecordAccountSnapshot(ctx context.Context, competitionID uuid.UUID, login int64, snapshot model.AccountSnapshot) error {
+	err := r.db.Query.InsertAccountSnapshot(ctx, sqlc.InsertAccountSnapshotParams{
 		CompetitionID:       competitionID,
 		TradingAccountLogin: login,
-		AccountSize:         accountSize,
+		Balance:             snapshot.Balance,
+		Equity:              snapshot.Equity,
+		Currency:            snapshot.Currency,
+		Leverage:            snapshot.Leverage,
+		TakenAt:             snapshot.TakenAt,
 	})
-	if err == nil {
-		return nil
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("insert account snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListAccountSizeAudit(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.AccountSizeAuditEntry, error) {
+	rows, err := r.db.Query.ListAccountSizeAudit(ctx, sqlc.ListAccountSizeAuditParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list account size audit: %w", err)
 	}
-	if errors.Is(err, sql.ErrNoRows) {
-		return ErrMemberNotFound
+	return mapper.AccountSizeAuditFromDB(rows), nil
+}
+
+func (r *PostgresRepository) GetBroker(ctx context.Context, login int64) (string, error) {
+	account, err := r.db.Query.GetTradingAccountByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrTradingAccountNotFound
+		}
+		return "", fmt.Errorf("get trading account: %w", err)
 	}
-	return fmt.Errorf("update account size: %w", err)
+	return account.Broker, nil
 }
 
 func (r *PostgresRepository) GetMemberAccountSize(ctx context.Context, competitionID uuid.UUID, login int64) (float64, error) {
@@ -146,53 +310,675 @@ func (r *PostgresRepository) GetMemberAccountSize(ctx context.Context, competiti
 	return size, nil
 }
 
-func (r *PostgresRepository) GetLeaderboard(ctx context.Context, competitionID uuid.UUID, limit, offset int32) ([]model.LeaderboardEntry, error) {
-	rows, err := r.db.Query.GetCompetitionLeaderboard(ctx, sqlc.GetCompetitionLeaderboardParams{
-		CompetitionID: competitionID,
-		Limit:         limit,
-		Offset:        offset,
+// GetLeaderboard returns one page of the live standings. Metrics, ranking,
+// filtering and paging all run in the database; see standingsQuery.
+func (r *PostgresRepository) GetLeaderboard(ctx context.Context, competitionID uuid.UUID, ranking model.Ranking, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	return readStandings(ctx, r.db.Pool, competitionID, ranking, q)
+}
+
+// readStandings runs the standings query on db, which is the pool or a
+// transaction.
+func readStandings(ctx context.Context, db sqlc.DBTX, competitionID uuid.UUID, ranking model.Ranking, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	query, args := standingsQuery(competitionID, ranking, q)
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return model.LeaderboardPage{}, fmt.Errorf("get leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	page := model.LeaderboardPage{Limit: q.Limit}
+	var offset *int64
+	for rows.Next() {
+		var (
+			total int64
+			login *int64
+			e     model.LeaderboardEntry
+		)
+		if err := rows.Scan(
+			&total,
+			&offset,
+			&login,
+			&e.Rank,
+			&e.Username,
+			&e.AccountSize,
+			&e.Profit,
+			&e.FloatingProfit,
+			&e.NetDeposits,
+			&e.Equity,
+			&e.GainPercent,
+			&e.DisqualifiedAt,
+			&e.DisqualificationReason,
+			&e.Metrics.TradeCount,
+			&e.Metrics.MaxDrawdownPercent,
+			&e.Metrics.Sharpe,
+			&e.Metrics.Sortino,
+			&e.Metrics.ProfitFactor,
+			&e.Metrics.WinRate,
+			&e.Metrics.AverageR,
+			&e.Score,
+		); err != nil {
+			return model.LeaderboardPage{}, fmt.Errorf("scan leaderboard: %w", err)
+		}
+		page.Total = int(total)
+		if login == nil {
+			continue
+		}
+		e.TradingAccountLogin = *login
+		page.Entries = append(page.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return model.LeaderboardPage{}, fmt.Errorf("get leaderboard: %w", err)
+	}
+	if offset == nil {
+		return model.LeaderboardPage{}, ErrMemberNotFound
+	}
+	page.Offset = int32(*offset)
+	return page, nil
+}
+
+const createTradeStaging = `
+CREATE TEMP TABLE trade_staging (
+    position_id BIGINT NOT NULL,
+    symbol TEXT NOT NULL,
+    side TEXT NOT NULL,
+    volume NUMERIC NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    close_time TIMESTAMPTZ NOT NULL,
+    open_price NUMERIC NOT NULL,
+    close_price NUMERIC NOT NULL,
+    profit NUMERIC NOT NULL,
+    commission NUMERIC NOT NULL,
+    swap NUMERIC NOT NULL,
+    instrument TEXT
+) ON COMMIT DROP`
+
+var tradeStagingColumns = []string{
+	"position_id", "symbol", "side", "volume", "open_time", "close_time",
+	"open_price", "close_price", "profit", "commission", "swap", "instrument",
+}
+
+// foreignPositionReason rejects a position the account already has in another
+// competition.
+const foreignPositionReason = "position already recorded in another competition"
+
+// dropForeignStagedTrades removes staged positions the account already has
+// in another competition, which must not be overwritten.
+const dropForeignStagedTrades = `
+DELETE FROM trade_staging s
+USING trades t
+WHERE t.trading_account_login = $1
+AND t.position_id = s.position_id
+AND t.competition_id <> $2
+RETURNING s.position_id`
+
+// mergeStagedTrades inserts new positions and applies corrections to changed
+// ones. All CTEs see the table as it was before the insert, so existing
+// carries the old values needed for the revision row every correction leaves
+// behind and for adjusting the member's stats and daily P&L: the old values
+// of a corrected trade are taken out and the new ones added. Unchanged
+// positions are not returned at all. The instrument is derived from the
+// symbol, so it is refreshed with a correction but never causes one.
+const mergeStagedTrades = `
+WITH existing AS (
+    SELECT
+        t.position_id, t.symbol, t.side, t.volume, t.open_time, t.close_time,
+        t.open_price, t.close_price, t.profit, t.commission, t.swap,
+        t.profit + t.commission + t.swap AS net
+    FROM trades t
+    JOIN trade_staging s ON s.position_id = t.position_id
+    WHERE t.trading_account_login = $1
+), merged AS (
+    INSERT INTO trades (
+        trading_account_login, competition_id, position_id, symbol, side, volume, open_time, close_time, open_price, close_price, profit, commission, swap, instrument
+    )
+    SELECT
+        $1, $2, s.position_id, s.symbol, s.side, s.volume, s.open_time, s.close_time, s.open_price, s.close_price, s.profit, s.commission, s.swap, s.instrument
+    FROM trade_staging s
+    ON CONFLICT (trading_account_login, position_id) DO UPDATE
+    SET symbol = EXCLUDED.symbol,
+        side = EXCLUDED.side,
+        volume = EXCLUDED.volume,
+        open_time = EXCLUDED.open_time,
+        close_time = EXCLUDED.close_time,
+        open_price = EXCLUDED.open_price,
+        close_price = EXCLUDED.close_price,
+        profit = EXCLUDED.profit,
+        commission = EXCLUDED.commission,
+        swap = EXCLUDED.swap,
+        instrument = EXCLUDED.instrument,
+        version = trades.version + 1
+    WHERE (
+        trades.symbol, trades.side, trades.volume, trades.open_time, trades.close_time,
+        trades.open_price, trades.close_price, trades.profit, trades.commission, trades.swap
+    ) IS DISTINCT FROM (
+        EXCLUDED.symbol, EXCLUDED.side, EXCLUDED.volume, EXCLUDED.open_time, EXCLUDED.close_time,
+        EXCLUDED.open_price, EXCLUDED.close_price, EXCLUDED.profit, EXCLUDED.commission, EXCLUDED.swap
+    )
+    RETURNING
+        position_id, symbol, side, volume, open_time, close_time,
+        open_price, close_price, profit, commission, swap, version, (xmax = 0) AS inserted,
+        profit + commission + swap AS net
+), revised AS (
+    INSERT INTO trade_revisions (
+        trading_account_login, position_id, competition_id, version, changes
+    )
+    SELECT $1, m.position_id, $2, m.version, jsonb_strip_nulls(jsonb_build_object(
+        'symbol', CASE WHEN e.symbol IS DISTINCT FROM m.symbol THEN jsonb_build_object('from', e.symbol, 'to', m.symbol) END,
+        'side', CASE WHEN e.side IS DISTINCT FROM m.side THEN jsonb_build_object('from', e.side, 'to', m.side) END,
+        'volume', CASE WHEN e.volume IS DISTINCT FROM m.volume THEN jsonb_build_object('from', e.volume, 'to', m.volume) END,
+        'open_time', CASE WHEN e.open_time IS DISTINCT FROM m.open_time THEN jsonb_build_object('from', e.open_time, 'to', m.open_time) END,
+        'close_time', CASE WHEN e.close_time IS DISTINCT FROM m.close_time THEN jsonb_build_object('from', e.close_time, 'to', m.close_time) END,
+        'open_price', CASE WHEN e.open_price IS DISTINCT FROM m.open_price THEN jsonb_build_object('from', e.open_price, 'to', m.open_price) END,
+        'close_price', CASE WHEN e.close_price IS DISTINCT FROM m.close_price THEN jsonb_build_object('from', e.close_price, 'to', m.close_price) END,
+        'profit', CASE WHEN e.profit IS DISTINCT FROM m.profit THEN jsonb_build_object('from', e.profit, 'to', m.profit) END,
+        'commission', CASE WHEN e.commission IS DISTINCT FROM m.commission THEN jsonb_build_object('from', e.commission, 'to', m.commission) END,
+        'swap', CASE WHEN e.swap IS DISTINCT FROM m.swap THEN jsonb_build_object('from', e.swap, 'to', m.swap) END
+    ))
+    FROM merged m
+    JOIN existing e ON e.position_id = m.position_id
+    WHERE NOT m.inserted
+    RETURNING position_id, changes
+), stats AS (
+    INSERT INTO competition_member_stats (
+        competition_id, trading_account_login, realized_profit, commission, swap, trade_count,
+        wins, losses, gross_profit, gross_loss, last_trade_at
+    )
+    SELECT
+        $2, $1,
+        SUM(m.profit - COALESCE(e.profit, 0)),
+        SUM(m.commission - COALESCE(e.commission, 0)),
+        SUM(m.swap - COALESCE(e.swap, 0)),
+        COUNT(*) FILTER (WHERE m.inserted),
+        COUNT(*) FILTER (WHERE m.net > 0) - COUNT(*) FILTER (WHERE e.net > 0),
+        COUNT(*) FILTER (WHERE m.net < 0) - COUNT(*) FILTER (WHERE e.net < 0),
+        SUM(GREATEST(m.net, 0) - GREATEST(COALESCE(e.net, 0), 0)),
+        SUM(GREATEST(-m.net, 0) - GREATEST(-COALESCE(e.net, 0), 0)),
+        MAX(m.close_time)
+    FROM merged m
+    LEFT JOIN existing e ON e.position_id = m.position_id
+    HAVING COUNT(*) > 0
+    ON CONFLICT (competition_id, trading_account_login) DO UPDATE
+    SET realized_profit = competition_member_stats.realized_profit + EXCLUDED.realized_profit,
+        commission = competition_member_stats.commission + EXCLUDED.commission,
+        swap = competition_member_stats.swap + EXCLUDED.swap,
+        trade_count = competition_member_stats.trade_count + EXCLUDED.trade_count,
+        wins = competition_member_stats.wins + EXCLUDED.wins,
+        losses = competition_member_stats.losses + EXCLUDED.losses,
+        gross_profit = competition_member_stats.gross_profit + EXCLUDED.gross_profit,
+        gross_loss = competition_member_stats.gross_loss + EXCLUDED.gross_loss,
+        last_trade_at = GREATEST(competition_member_stats.last_trade_at, EXCLUDED.last_trade_at),
+        updated_at = now()
+), daily AS (
+    INSERT INTO competition_member_daily_pnl (
+        competition_id, trading_account_login, day, net_profit
+    )
+    SELECT $2, $1, d.day, SUM(d.net)
+    FROM (
+        SELECT (m.close_time AT TIME ZONE 'UTC')::DATE AS day, m.net
+        FROM merged m
+        UNION ALL
+        SELECT (e.close_time AT TIME ZONE 'UTC')::DATE, -e.net
+        FROM merged m
+        JOIN existing e ON e.position_id = m.position_id
+    ) d
+    GROUP BY d.day
+    ON CONFLICT (competition_id, trading_account_login, day) DO UPDATE
+    SET net_profit = competition_member_daily_pnl.net_profit + EXCLUDED.net_profit
+)
+SELECT
+    m.position_id,
+    m.inserted,
+    m.version,
+    ARRAY(SELECT k FROM jsonb_object_keys(r.changes) AS k ORDER BY k)::TEXT[]
+FROM merged m
+LEFT JOIN revised r ON r.position_id = m.position_id`
+
+// InsertTrades writes a batch atomically: the trades are copied into a
+// staging table and merged into trades in one statement that also adjusts
+// the member's stats, after which their drawdown is recomputed in the same
+// transaction. A position whose fields differ from the stored ones is
+// corrected in place, its version bumped and the change recorded in
+// trade_revisions. Position IDs must be unique within the batch. The outcome of every trade is returned keyed by position ID.
+func (r *PostgresRepository) InsertTrades(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	login int64,
+	trades []model.Trade,
+) (map[int64]model.TradeOutcome, error) {
+	outcomes := make(map[int64]model.TradeOutcome, len(trades))
+	if len(trades) == 0 {
+		return outcomes, nil
+	}
+
+	err := r.db.WithPgxTx(ctx, func(tx pgx.Tx, q *sqlc.Queries) error {
+		return mergeTrades(ctx, tx, q, competitionID, login, trades, outcomes)
 	})
 	if err != nil {
-		return nil, fmt.Errorf("get leaderboard: %w", err)
+		return nil, err
 	}
-	return mapper.LeaderboardFromDB(rows), nil
+
+	for _, t := range trades {
+		if _, ok := outcomes[t.PositionID]; !ok {
+			outcomes[t.PositionID] = model.TradeOutcome{PositionID: t.PositionID, Status: model.TradeDuplicate}
+		}
+	}
+	return outcomes, nil
 }
 
-func (r *PostgresRepository) InsertTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade) error {
+// mergeTrades does the work of InsertTrades inside tx, recording the outcome
+// of every written or rejected trade in outcomes. Pending quarantine entries
+// of the written positions are dropped since the trades now count.
+func mergeTrades(
+	ctx context.Context,
+	tx pgx.Tx,
+	q *sqlc.Queries,
+	competitionID uuid.UUID,
+	login int64,
+	trades []model.Trade,
+	outcomes map[int64]model.TradeOutcome,
+) error {
+	if err := lockOpenCompetition(ctx, q, competitionID); err != nil {
+		return err
+	}
+
+	// The stats deltas are computed from the trades as they were when the
+	// merge started, so two imports of the same positions must not overlap
+	// or both would count them.
+	err := q.LockCompetitionMember(ctx, sqlc.LockCompetitionMemberParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return fmt.Errorf("lock member: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, createTradeStaging); err != nil {
+		return fmt.Errorf("create trade staging: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"trade_staging"}, tradeStagingColumns,
+		pgx.CopyFromSlice(len(trades), func(i int) ([]any, error) {
+			t := trades[i]
+			return []any{
+				t.PositionID, t.Symbol, t.Side, t.Volume, t.OpenTime, t.CloseTime,
+				t.OpenPrice, t.ClosePrice, t.Profit, t.Commission, t.Swap,
+				pgtype.Text{String: t.Instrument, Valid: t.Instrument != ""},
+			}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("copy trades: %w", err)
+	}
+
+	foreign, err := tx.Query(ctx, dropForeignStagedTrades, login, competitionID)
+	if err != nil {
+		return fmt.Errorf("drop foreign trades: %w", err)
+	}
+	positionIDs, err := pgx.CollectRows(foreign, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("drop foreign trades: %w", err)
+	}
+	for _, id := range positionIDs {
+		outcomes[id] = model.TradeOutcome{
+			PositionID: id,
+			Status:     model.TradeRejected,
+			Reason:     foreignPositionReason,
+		}
+	}
+
+	rows, err := tx.Query(ctx, mergeStagedTrades, login, competitionID)
+	if err != nil {
+		return mapTradeWriteError(err)
+	}
+	defer rows.Close()
+
+	merged := 0
+	for rows.Next() {
+		var (
+			positionID    int64
+			inserted      bool
+			version       int32
+			changedFields []string
+		)
+		if err := rows.Scan(&positionID, &inserted, &version, &changedFields); err != nil {
+			return fmt.Errorf("scan merged trade: %w", err)
+		}
+
+		status := model.TradeUpdated
+		if inserted {
+			status = model.TradeInserted
+		}
+		outcomes[positionID] = model.TradeOutcome{
+			PositionID:    positionID,
+			Status:        status,
+			Version:       version,
+			ChangedFields: changedFields,
+		}
+		merged++
+	}
+	if err := rows.Err(); err != nil {
+		return mapTradeWriteError(err)
+	}
+
+	if merged > 0 {
+		err = q.RefreshCompetitionMemberDrawdown(ctx, sqlc.RefreshCompetitionMemberDrawdownParams{
+			CompetitionID:       competitionID,
+			TradingAccountLogin: pgtype.Int8{Int64: login, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("refresh member drawdown: %w", err)
+		}
+	}
+
+	// A closed trade's floating P&L must not be counted twice until the
+	// next open positions snapshot arrives.
+	closed := make([]int64, 0, len(trades))
 	for _, t := range trades {
-		err := r.db.Query.InsertTrade(ctx, sqlc.InsertTradeParams{
+		closed = append(closed, t.PositionID)
+	}
+	err = q.CloseOpenPositions(ctx, sqlc.CloseOpenPositionsParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+		PositionIds:         closed,
+	})
+	if err != nil {
+		return fmt.Errorf("close open positions: %w", err)
+	}
+
+	err = q.ReleaseQuarantinedTrades(ctx, sqlc.ReleaseQuarantinedTradesParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+		PositionIds:         closed,
+	})
+	if err != nil {
+		return fmt.Errorf("release quarantined trades: %w", err)
+	}
+	return nil
+}
+
+// QuarantineTrades stores trades that fall outside the competition window
+// for review. A position that is already quarantined and still pending is
+// overwritten with the latest values; reviewed ones are left alone.
+func (r *PostgresRepository) QuarantineTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.QuarantinedTrade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		for _, qt := range trades {
+			t := qt.Trade
+			err := q.QuarantineTrade(ctx, sqlc.QuarantineTradeParams{
+				CompetitionID:       competitionID,
+				TradingAccountLogin: login,
+				PositionID:          t.PositionID,
+				Symbol:              t.Symbol,
+				Side:                t.Side,
+				Volume:              t.Volume,
+				OpenTime:            t.OpenTime,
+				CloseTime:           t.CloseTime,
+				OpenPrice:           t.OpenPrice,
+				ClosePrice:          t.ClosePrice,
+				Profit:              t.Profit,
+				Commission:          t.Commission,
+				Swap:                t.Swap,
+				Reason:              qt.Reason,
+				Instrument:          pgtype.Text{String: t.Instrument, Valid: t.Instrument != ""},
+			})
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+					return ErrNotMember
+				}
+				return fmt.Errorf("quarantine trade %d: %w", t.PositionID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) ListQuarantinedTrades(ctx context.Context, competitionID uuid.UUID, status model.QuarantineStatus) ([]model.QuarantinedTrade, error) {
+	rows, err := r.db.Query.ListQuarantinedTrades(ctx, sqlc.ListQuarantinedTradesParams{
+		CompetitionID: competitionID,
+		Status:        pgtype.Text{String: string(status), Valid: status != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list quarantined trades: %w", err)
+	}
+	return mapper.QuarantinedTradesFromDB(rows), nil
+}
+
+// ResolveQuarantinedTrade closes a pending quarantine entry. An approved
+// trade is merged into trades, unscaled, in the same transaction.
+func (r *PostgresRepository) ResolveQuarantinedTrade(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.QuarantineStatus,
+	reviewedBy uuid.UUID,
+) (model.QuarantinedTrade, error) {
+	var resolved model.QuarantinedTrade
+	err := r.db.WithPgxTx(ctx, func(tx pgx.Tx, q *sqlc.Queries) error {
+		row, err := q.ResolveQuarantinedTrade(ctx, sqlc.ResolveQuarantinedTradeParams{
+			Status:        string(status),
+			ReviewedBy:    &reviewedBy,
+			ID:            id,
+			CompetitionID: competitionID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrQuarantinedTradeNotFound
+			}
+			return fmt.Errorf("resolve quarantined trade: %w", err)
+		}
+		resolved = mapper.QuarantinedTradeFromDB(row)
+
+		if status != model.QuarantineApproved {
+			return nil
+		}
+		outcomes := make(map[int64]model.TradeOutcome, 1)
+		err = mergeTrades(ctx, tx, q, competitionID, row.TradingAccountLogin, []model.Trade{resolved.Trade}, outcomes)
+		if err != nil {
+			return err
+		}
+		if o := outcomes[row.PositionID]; o.Status == model.TradeRejected {
+			return fmt.Errorf("%w: %s", ErrQuarantinedTradeConflict, o.Reason)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.QuarantinedTrade{}, err
+	}
+	return resolved, nil
+}
+
+// ReplaceOpenPositions swaps the member's open positions for snapshot and
+// stores their floating P&L. A snapshot older than the stored one is rejected
+// with ErrStaleOpenPositions.
+func (r *PostgresRepository) ReplaceOpenPositions(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	login int64,
+	snapshot model.OpenPositionSnapshot,
+) error {
+	var floating float64
+	for _, p := range snapshot.Positions {
+		floating += p.Profit + p.Commission + p.Swap
+	}
+
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenCompetition(ctx, q, competitionID); err != nil {
+			return err
+		}
+
+		n, err := q.UpsertCompetitionMemberFloating(ctx, sqlc.UpsertCompetitionMemberFloatingParams{
+			CompetitionID:       competitionID,
 			TradingAccountLogin: login,
+			FloatingProfit:      floating,
+			OpenPositionCount:   int32(len(snapshot.Positions)),
+			SnapshotAt:          snapshot.TakenAt,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrNotMember
+			}
+			return fmt.Errorf("upsert floating profit: %w", err)
+		}
+		if n == 0 {
+			return ErrStaleOpenPositions
+		}
+
+		err = q.DeleteMemberOpenPositions(ctx, sqlc.DeleteMemberOpenPositionsParams{
 			CompetitionID:       competitionID,
-			PositionID:          t.PositionID,
-			Symbol:              t.Symbol,
-			Side:                t.Side,
-			Volume:              t.Volume,
-			OpenTime:            t.OpenTime,
-			CloseTime:           t.CloseTime,
-			OpenPrice:           t.OpenPrice,
-			ClosePrice:          t.ClosePrice,
-			Profit:              t.Profit,
-			Commission:          t.Commission,
-			Swap:                t.Swap,
+			TradingAccountLogin: login,
 		})
-		if err == nil {
-			continue
+		if err != nil {
+			return fmt.Errorf("delete open positions: %w", err)
 		}
 
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			switch pgErr.ConstraintName {
-			case "trades_competition_id_fkey":
-				return ErrNotFound
-			case "trades_trading_account_login_fkey":
-				return ErrTradingAccountNotFound
-			case "trades_member_fkey":
-				return ErrNotMember
+		for _, p := range snapshot.Positions {
+			err := q.InsertOpenPosition(ctx, sqlc.InsertOpenPositionParams{
+				CompetitionID:       competitionID,
+				TradingAccountLogin: login,
+				PositionID:          p.PositionID,
+				Symbol:              p.Symbol,
+				Side:                p.Side,
+				Volume:              p.Volume,
+				OpenTime:            p.OpenTime,
+				OpenPrice:           p.OpenPrice,
+				CurrentPrice:        p.CurrentPrice,
+				Profit:              p.Profit,
+				Commission:          p.Commission,
+				Swap:                p.Swap,
+			})
+			if err != nil {
+				return fmt.Errorf("insert open position: %w", err)
 			}
 		}
-		return fmt.Errorf("insert trade (position_id=%d): %w", t.PositionID, err)
+		return nil
+	})
+}
+
+func (r *PostgresRepository) ListOpenPositions(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.OpenPosition, error) {
+	rows, err := r.db.Query.ListMemberOpenPositions(ctx, sqlc.ListMemberOpenPositionsParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list open positions: %w", err)
 	}
-	return nil
+	return mapper.OpenPositionsFromDB(rows), nil
+}
+
+// InsertBalanceOperations stores new operations, skipping ones already
+// recorded, and opens a review for every new operation that has a reason in
+// flags.
+func (r *PostgresRepository) InsertBalanceOperations(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	login int64,
+	ops []model.BalanceOperation,
+	flags map[int64]string,
+) (model.BalanceIngestResult, error) {
+	var result model.BalanceIngestResult
+
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenCompetition(ctx, q, competitionID); err != nil {
+			return err
+		}
+
+		result = model.BalanceIngestResult{}
+		for _, op := range ops {
+			n, err := q.InsertBalanceOperation(ctx, sqlc.InsertBalanceOperationParams{
+				CompetitionID:       competitionID,
+				TradingAccountLogin: login,
+				OperationID:         op.OperationID,
+				Type:                string(op.Type),
+				Amount:              op.Amount,
+				OccurredAt:          op.OccurredAt,
+				Comment:             op.Comment,
+			})
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+					return ErrNotMember
+				}
+				return fmt.Errorf("insert balance operation: %w", err)
+			}
+			if n == 0 {
+				result.Duplicates++
+				continue
+			}
+			result.Inserted++
+
+			reason, ok := flags[op.OperationID]
+			if !ok {
+				continue
+			}
+			err = q.CreateBalanceReview(ctx, sqlc.CreateBalanceReviewParams{
+				CompetitionID:       competitionID,
+				TradingAccountLogin: login,
+				OperationID:         op.OperationID,
+				Reason:              reason,
+			})
+			if err != nil {
+				return fmt.Errorf("create balance review: %w", err)
+			}
+			result.Flagged++
+		}
+		return nil
+	})
+	if err != nil {
+		return model.BalanceIngestResult{}, err
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) ListBalanceReviews(ctx context.Context, competitionID uuid.UUID, status model.BalanceReviewStatus) ([]model.BalanceReview, error) {
+	rows, err := r.db.Query.ListBalanceReviews(ctx, sqlc.ListBalanceReviewsParams{
+		CompetitionID: competitionID,
+		Status:        pgtype.Text{String: string(status), Valid: status != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list balance reviews: %w", err)
+	}
+	return mapper.BalanceReviewsFromDB(rows), nil
+}
+
+func (r *PostgresRepository) ResolveBalanceReview(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.BalanceReviewStatus,
+	reviewedBy uuid.UUID,
+) (model.BalanceReview, error) {
+	row, err := r.db.Query.ResolveBalanceReview(ctx, sqlc.ResolveBalanceReviewParams{
+		Status:        string(status),
+		ReviewedBy:    &reviewedBy,
+		ID:            id,
+		CompetitionID: competitionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.BalanceReview{}, ErrBalanceReviewNotFound
+		}
+		return model.BalanceReview{}, fmt.Errorf("resolve balance review: %w", err)
+	}
+	return mapper.BalanceReviewFromDB(row), nil
+}
+
+func mapTradeWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		switch pgErr.ConstraintName {
+		case "trades_competition_id_fkey":
+			return ErrNotFound
+		case "trades_trading_account_login_fkey":
+			return ErrTradingAccountNotFound
+		case "trades_member_fkey":
+			return ErrNotMember
+		}
+	}
+	return fmt.Errorf("merge trades: %w", err)
 }
 
 func (r *PostgresRepository) GetUserCompetitionState(ctx context.Context, userID, competitionID uuid.UUID) (sqlc.GetCompetitionUserStateRow, error) {
@@ -227,3 +1013,462 @@ func (r *PostgresRepository) CreateAccountRequest(ctx context.Context, userID, c
 	}
 	return nil
 }
+
+func (r *PostgresRepository) GetRules(ctx context.Context, competitionID uuid.UUID) (model.Rules, error) {
+	row, err := r.db.Query.GetCompetitionRules(ctx, competitionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Rules{}, ErrRulesNotSet
+		}
+		return model.Rules{}, fmt.Errorf("get rules: %w", err)
+	}
+	return mapper.RulesFromDB(row), nil
+}
+
+func (r *PostgresRepository) UpsertRules(ctx context.Context, rules model.Rules) (model.Rules, error) {
+	row, err := r.db.Query.UpsertCompetitionRules(ctx, sqlc.UpsertCompetitionRulesParams{
+		CompetitionID:       rules.CompetitionID,
+		MaxDrawdownPercent:  rules.MaxDrawdownPercent,
+		MaxDailyLossPercent: rules.MaxDailyLossPercent,
+		MaxLotSize:          rules.MaxLotSize,
+		AllowedSymbols:      rules.AllowedSymbols,
+		MinTradingDays:      rules.MinTradingDays,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return model.Rules{}, ErrNotFound
+		}
+		return model.Rules{}, fmt.Errorf("upsert rules: %w", err)
+	}
+	return mapper.RulesFromDB(row), nil
+}
+
+func (r *PostgresRepository) ListMemberTrades(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.Trade, error) {
+	rows, err := r.db.Query.ListCompetitionMemberTrades(ctx, sqlc.ListCompetitionMemberTradesParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list member trades: %w", err)
+	}
+	return mapper.TradesFromDB(rows), nil
+}
+
+// ListForeignPositions returns which of positionIDs the account already has
+// in another competition.
+func (r *PostgresRepository) ListForeignPositions(ctx context.Context, competitionID uuid.UUID, login int64, positionIDs []int64) ([]int64, error) {
+	ids, err := r.db.Query.ListForeignTradePositions(ctx, sqlc.ListForeignTradePositionsParams{
+		TradingAccountLogin: login,
+		CompetitionID:       competitionID,
+		PositionIds:         positionIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list foreign positions: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresRepository) ListTradeRevisions(ctx context.Context, competitionID uuid.UUID, login, positionID int64) ([]model.TradeRevision, error) {
+	rows, err := r.db.Query.ListTradeRevisions(ctx, sqlc.ListTradeRevisionsParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+		PositionID:          positionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list trade revisions: %w", err)
+	}
+	return mapper.TradeRevisionsFromDB(rows)
+}
+
+func (r *PostgresRepository) DisqualifyMember(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	login int64,
+	breach model.RuleBreach,
+	at time.Time,
+) error {
+	_, err := r.db.Query.DisqualifyCompetitionMember(ctx, sqlc.DisqualifyCompetitionMemberParams{
+		DisqualifiedAt:      at,
+		Reason:              breach.Reason,
+		PositionID:          pgtype.Int8{Int64: breach.PositionID, Valid: breach.PositionID > 0},
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return fmt.Errorf("disqualify member: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListByStatus(ctx context.Context, status model.Status) ([]model.Competition, error) {
+	rows, err := r.db.Query.ListCompetitionsByStatus(ctx, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("list competitions by status: %w", err)
+	}
+
+	competitions := make([]model.Competition, 0, len(rows))
+	for _, row := range rows {
+		competitions = append(competitions, mapper.CompetitionFromDB(row))
+	}
+	return competitions, nil
+}
+
+// TransitionStatus moves the competition from one status to another and records
+// the event. It fails with ErrStatusChanged if the competition is no longer in from.
+func (r *PostgresRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to model.Status) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.UpdateCompetitionStatus(ctx, sqlc.UpdateCompetitionStatusParams{
+			ToStatus:   string(to),
+			ID:         id,
+			FromStatus: string(from),
+		})
+		if err != nil {
+			return fmt.Errorf("update competition status: %w", err)
+		}
+		if n == 0 {
+			return ErrStatusChanged
+		}
+
+		if err := q.CreateCompetitionEvent(ctx, sqlc.CreateCompetitionEventParams{
+			CompetitionID: id,
+			FromStatus:    string(from),
+			ToStatus:      string(to),
+		}); err != nil {
+			return fmt.Errorf("create competition event: %w", err)
+		}
+		return nil
+	})
+}
+
+// Finalize stores the results snapshot and marks the competition finalized in
+// one transaction. The standings are read inside it, with the competition row
+// locked so no writer can change them before the results are stored; they
+// must still hash to resultsHash, the hash of the standings the caller checked
+// the rules against, or it fails with ErrStandingsChanged. It fails with
+// ErrStatusChanged if the competition is no longer settling.
+func (r *PostgresRepository) Finalize(
+	ctx context.Context,
+	id uuid.UUID,
+	ranking model.Ranking,
+	resultsHash string,
+	finalizedBy *uuid.UUID,
+) error {
+	return r.db.WithPgxTx(ctx, func(tx pgx.Tx, q *sqlc.Queries) error {
+		if err := q.LockCompetition(ctx, id); err != nil {
+			return fmt.Errorf("lock competition: %w", err)
+		}
+
+		page, err := readStandings(ctx, tx, id, ranking, model.LeaderboardQuery{Limit: math.MaxInt32})
+		if err != nil {
+			return err
+		}
+		entries := page.Entries
+		if ResultsHash(entries) != resultsHash {
+			return ErrStandingsChanged
+		}
+
+		n, err := q.FinalizeCompetition(ctx, sqlc.FinalizeCompetitionParams{
+			FinalizedBy: finalizedBy,
+			ResultsHash: resultsHash,
+			ID:          id,
+		})
+		if err != nil {
+			return fmt.Errorf("finalize competition: %w", err)
+		}
+		if n == 0 {
+			return ErrStatusChanged
+		}
+
+		if err := q.CreateCompetitionEvent(ctx, sqlc.CreateCompetitionEventParams{
+			CompetitionID: id,
+			FromStatus:    string(model.StatusSettling),
+			ToStatus:      string(model.StatusFinalized),
+		}); err != nil {
+			return fmt.Errorf("create competition event: %w", err)
+		}
+
+		for _, e := range entries {
+			if err := q.CreateCompetitionResult(ctx, sqlc.CreateCompetitionResultParams{
+				CompetitionID:          id,
+				TradingAccountLogin:    e.TradingAccountLogin,
+				Rank:                   e.Rank,
+				Username:               e.Username,
+				AccountSize:            e.AccountSize,
+				Profit:                 e.Profit,
+				Equity:                 e.Equity,
+				GainPercent:            e.GainPercent,
+				FloatingProfit:         e.FloatingProfit,
+				NetDeposits:            e.NetDeposits,
+				TradeCount:             e.Metrics.TradeCount,
+				MaxDrawdownPercent:     e.Metrics.MaxDrawdownPercent,
+				Sharpe:                 e.Metrics.Sharpe,
+				Sortino:                e.Metrics.Sortino,
+				ProfitFactor:           e.Metrics.ProfitFactor,
+				WinRate:                e.Metrics.WinRate,
+				AverageR:               e.Metrics.AverageR,
+				Score:                  e.Score,
+				DisqualifiedAt:         e.DisqualifiedAt,
+				DisqualificationReason: e.DisqualificationReason,
+			}); err != nil {
+				return fmt.Errorf("create result (login=%d): %w", e.TradingAccountLogin, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListResults returns one page of the frozen results, filtered like the
+// live leaderboard.
+func (r *PostgresRepository) ListResults(ctx context.Context, competitionID uuid.UUID, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	total, err := r.db.Query.CountCompetitionResults(ctx, sqlc.CountCompetitionResultsParams{
+		CompetitionID:  competitionID,
+		UsernamePrefix: q.UsernamePrefix,
+		Status:         string(q.Status),
+		MinTrades:      q.MinTrades,
+	})
+	if err != nil {
+		return model.LeaderboardPage{}, fmt.Errorf("count results: %w", err)
+	}
+
+	offset := q.Offset
+	if q.AroundUserID != nil {
+		position, err := r.db.Query.GetCompetitionResultPosition(ctx, sqlc.GetCompetitionResultPositionParams{
+			CompetitionID:  competitionID,
+			UsernamePrefix: q.UsernamePrefix,
+			Status:         string(q.Status),
+			MinTrades:      q.MinTrades,
+			UserID:         *q.AroundUserID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.LeaderboardPage{}, ErrMemberNotFound
+			}
+			return model.LeaderboardPage{}, fmt.Errorf("get result position: %w", err)
+		}
+		offset = max(0, int32(position)-1-q.Limit/2)
+	}
+
+	rows, err := r.db.Query.ListCompetitionResults(ctx, sqlc.ListCompetitionResultsParams{
+		CompetitionID:  competitionID,
+		UsernamePrefix: q.UsernamePrefix,
+		Status:         string(q.Status),
+		MinTrades:      q.MinTrades,
+		RowLimit:       q.Limit,
+		RowOffset:      offset,
+	})
+	if err != nil {
+		return model.LeaderboardPage{}, fmt.Errorf("list results: %w", err)
+	}
+	return model.LeaderboardPage{
+		Entries: mapper.LeaderboardFromResults(rows),
+		Total:   int(total),
+		Limit:   q.Limit,
+		Offset:  offset,
+	}, nil
+}
+
+func (r *PostgresRepository) CreateSnapshot(ctx context.Context, competitionID uuid.UUID, entries []model.LeaderboardEntry) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		snapshotID, err := q.CreateLeaderboardSnapshot(ctx, competitionID)
+		if err != nil {
+			return fmt.Errorf("create leaderboard snapshot: %w", err)
+		}
+
+		for _, e := range entries {
+			if err := q.CreateLeaderboardSnapshotEntry(ctx, sqlc.CreateLeaderboardSnapshotEntryParams{
+				SnapshotID:          snapshotID,
+				TradingAccountLogin: e.TradingAccountLogin,
+				Rank:                e.Rank,
+				Profit:              e.Profit,
+				Equity:              e.Equity,
+				GainPercent:         e.GainPercent,
+			}); err != nil {
+				return fmt.Errorf("create snapshot entry (login=%d): %w", e.TradingAccountLogin, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) GetLatestSnapshot(ctx context.Context, competitionID uuid.UUID) ([]model.SnapshotEntry, error) {
+	rows, err := r.db.Query.GetLatestLeaderboardSnapshotEntries(ctx, competitionID)
+	if err != nil {
+		return nil, fmt.Errorf("get latest snapshot: %w", err)
+	}
+	return mapper.SnapshotEntriesFromDB(rows), nil
+}
+
+func (r *PostgresRepository) GetBestRanks(ctx context.Context, competitionID uuid.UUID) (map[int64]int32, error) {
+	rows, err := r.db.Query.GetBestLeaderboardRanks(ctx, competitionID)
+	if err != nil {
+		return nil, fmt.Errorf("get best ranks: %w", err)
+	}
+
+	best := make(map[int64]int32, len(rows))
+	for _, row := range rows {
+		best[row.TradingAccountLogin] = row.BestRank
+	}
+	return best, nil
+}
+
+func (r *PostgresRepository) ListRankHistory(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.RankHistoryPoint, error) {
+	rows, err := r.db.Query.ListMemberRankHistory(ctx, sqlc.ListMemberRankHistoryParams{
+		CompetitionID:       competitionID,
+		TradingAccountLogin: login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list rank history: %w", err)
+	}
+	return mapper.RankHistoryFromDB(rows), nil
+}
+
+func (r *PostgresRepository) GetRanking(ctx context.Context, competitionID uuid.UUID) (model.Ranking, error) {
+	row, err := r.db.Query.GetCompetitionRanking(ctx, competitionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Ranking{}, ErrRankingNotSet
+		}
+		return model.Ranking{}, fmt.Errorf("get ranking: %w", err)
+	}
+	return mapper.RankingFromDB(row)
+}
+
+func (r *PostgresRepository) UpsertRanking(ctx context.Context, ranking model.Ranking) (model.Ranking, error) {
+	params, err := mapper.RankingToUpsertParams(ranking)
+	if err != nil {
+		return model.Ranking{}, err
+	}
+
+	row, err := r.db.Query.UpsertCompetitionRanking(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return model.Ranking{}, ErrNotFound
+		}
+		return model.Ranking{}, fmt.Errorf("upsert ranking: %w", err)
+	}
+	return mapper.RankingFromDB(row)
+}
+
+func (r *PostgresRepository) ListTrades(ctx context.Context, competitionID uuid.UUID) ([]model.Trade, error) {
+	rows, err := r.db.Query.ListCompetitionTrades(ctx, competitionID)
+	if err != nil {
+		return nil, fmt.Errorf("list trades: %w", err)
+	}
+	return mapper.TradesFromDB(rows), nil
+}
+
+// RebuildMemberStats recomputes the competition's member stats and daily P&L
+// from its trades and returns how many members have stats. The competition
+// row is locked so trades cannot be inserted halfway through.
+func (r *PostgresRepository) RebuildMemberStats(ctx context.Context, competitionID uuid.UUID) (int64, error) {
+	var rebuilt int64
+	err := r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.LockCompetition(ctx, competitionID); err != nil {
+			return fmt.Errorf("lock competition: %w", err)
+		}
+		if err := q.DeleteCompetitionMemberStats(ctx, competitionID); err != nil {
+			return fmt.Errorf("delete member stats: %w", err)
+		}
+
+		n, err := q.RebuildCompetitionMemberStats(ctx, competitionID)
+		if err != nil {
+			return fmt.Errorf("rebuild member stats: %w", err)
+		}
+		rebuilt = n
+
+		if err := q.DeleteCompetitionMemberDailyPnl(ctx, competitionID); err != nil {
+			return fmt.Errorf("delete member daily pnl: %w", err)
+		}
+		if err := q.RebuildCompetitionMemberDailyPnl(ctx, competitionID); err != nil {
+			return fmt.Errorf("rebuild member daily pnl: %w", err)
+		}
+
+		err = q.RefreshCompetitionMemberDrawdown(ctx, sqlc.RefreshCompetitionMemberDrawdownParams{
+			CompetitionID: competitionID,
+		})
+		if err != nil {
+			return fmt.Errorf("refresh member drawdown: %w", err)
+		}
+		return nil
+	})
+	return rebuilt, err
+}
+
+// lockOpenCompetition is taken by every transaction that changes the
+// standings. It holds off Finalize, which locks the row for update, until the
+// transaction ends, and fails with ErrCompetitionClosed once the results are
+// frozen.
+func lockOpenCompetition(ctx context.Context, q *sqlc.Queries, competitionID uuid.UUID) error {
+	status, err := q.LockCompetitionForWrite(ctx, competitionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("lock competition: %w", err)
+	}
+	switch model.Status(status) {
+	case model.StatusFinalized, model.StatusArchived:
+		return ErrCompetitionClosed
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ids, err := r.db.Query.ListCompetitionIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list competition ids: %w", err)
+	}
+	return ids, nil
+}
+
+// UpsertCheatCases records detected cases, refreshing the evidence of cases
+// that are still open.
+func (r *PostgresRepository) UpsertCheatCases(ctx context.Context, competitionID uuid.UUID, cases []model.CheatCase) error {
+	return r.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		for _, c := range cases {
+			params, err := mapper.CheatCaseToUpsertParams(competitionID, c)
+			if err != nil {
+				return err
+			}
+			if err := q.UpsertCheatCase(ctx, params); err != nil {
+				return fmt.Errorf("upsert cheat case: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) ListCheatCases(ctx context.Context, competitionID uuid.UUID, status model.CheatCaseStatus) ([]model.CheatCase, error) {
+	rows, err := r.db.Query.ListCheatCases(ctx, sqlc.ListCheatCasesParams{
+		CompetitionID: competitionID,
+		Status:        pgtype.Text{String: string(status), Valid: status != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list cheat cases: %w", err)
+	}
+	return mapper.CheatCasesFromDB(rows)
+}
+
+func (r *PostgresRepository) ResolveCheatCase(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.CheatCaseStatus,
+	resolvedBy uuid.UUID,
+) (model.CheatCase, error) {
+	row, err := r.db.Query.ResolveCheatCase(ctx, sqlc.ResolveCheatCaseParams{
+		Status:        string(status),
+		ResolvedBy:    &resolvedBy,
+		ID:            id,
+		CompetitionID: competitionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CheatCase{}, ErrCheatCaseNotFound
+		}
+		return model.CheatCase{}, fmt.Errorf("resolve cheat case: %w", err)
+	}
+	return mapper.CheatCaseFromDB(row)
+}
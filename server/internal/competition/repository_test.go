@@ -0,0 +1,208 @@
+package competition
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/filipcvejic/trading_tournament/internal/testdb"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// seedMember creates a running competition with login as its only member and
+// returns the competition's ID.
+func seedMember(t *testing.T, pool *pgxpool.Pool, login int64) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	var userID, competitionID uuid.UUID
+	err := pool.QueryRow(ctx, `
+INSERT INTO users (email, username, discord_username, password_hash)
+VALUES ('trader@example.com', 'trader', 'trader', 'hash')
+RETURNING id`).Scan(&userID)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	_, err = pool.Exec(ctx, `
+INSERT INTO trading_accounts (login, user_id, broker, investor_password_encrypted)
+VALUES ($1, $2, 'broker', 'encrypted')`, login, userID)
+	if err != nil {
+		t.Fatalf("create trading account: %v", err)
+	}
+	err = pool.QueryRow(ctx, `
+INSERT INTO competitions (name, starts_at, ends_at, status)
+VALUES ('Test', now() - INTERVAL '7 days', now() + INTERVAL '7 days', 'running')
+RETURNING id`).Scan(&competitionID)
+	if err != nil {
+		t.Fatalf("create competition: %v", err)
+	}
+	_, err = pool.Exec(ctx, `
+INSERT INTO competition_members (competition_id, trading_account_login, account_size)
+VALUES ($1, $2, 10000)`, competitionID, login)
+	if err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	return competitionID
+}
+
+func TestInsertTradesConcurrentImports(t *testing.T) {
+	database := testdb.New(t)
+	repo, pool := NewPostgresRepository(database), database.Pool
+	ctx := context.Background()
+
+	const login = 1001
+	competitionID := seedMember(t, pool, login)
+
+	// Every round imports new positions and, at the same time, a correction
+	// of them, as two overlapping syncs of one account would. Whichever runs
+	// second must adjust the stats by the difference, not add its trades
+	// again.
+	const rounds, perRound = 10, 5
+	closeTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	for round := range rounds {
+		batches := make([][]model.Trade, 2)
+		for b := range batches {
+			batches[b] = make([]model.Trade, perRound)
+			for i := range batches[b] {
+				batches[b][i] = model.Trade{
+					PositionID: int64(round*perRound + i + 1),
+					Symbol:     "EURUSD",
+					Side:       "buy",
+					Volume:     1,
+					OpenTime:   closeTime.Add(-time.Hour),
+					CloseTime:  closeTime,
+					OpenPrice:  1.1,
+					ClosePrice: 1.1,
+					Profit:     float64(10*round + i - 20 + 5*b),
+				}
+			}
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(batches))
+		for b, trades := range batches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[b] = repo.InsertTrades(ctx, competitionID, login, trades)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatalf("round %d: insert trades: %v", round, err)
+			}
+		}
+	}
+
+	var (
+		tradeCount, wantCount int32
+		profit, wantProfit    float64
+		dailyPnL              float64
+	)
+	err := pool.QueryRow(ctx, `
+SELECT
+    s.trade_count,
+    s.realized_profit::FLOAT8,
+    (SELECT SUM(net_profit) FROM competition_member_daily_pnl
+     WHERE competition_id = $1 AND trading_account_login = $2)::FLOAT8,
+    (SELECT COUNT(*) FROM trades
+     WHERE competition_id = $1 AND trading_account_login = $2)::INT,
+    (SELECT SUM(profit) FROM trades
+     WHERE competition_id = $1 AND trading_account_login = $2)::FLOAT8
+FROM competition_member_stats s
+WHERE s.competition_id = $1
+AND s.trading_account_login = $2`, competitionID, login).Scan(&tradeCount, &profit, &dailyPnL, &wantCount, &wantProfit)
+	if err != nil {
+		t.Fatalf("get member stats: %v", err)
+	}
+
+	if wantCount != rounds*perRound {
+		t.Fatalf("stored %d trades, want %d", wantCount, rounds*perRound)
+	}
+	if tradeCount != wantCount {
+		t.Errorf("trade_count = %d, want %d", tradeCount, wantCount)
+	}
+	if profit != wantProfit {
+		t.Errorf("realized_profit = %v, want %v", profit, wantProfit)
+	}
+	if dailyPnL != wantProfit {
+		t.Errorf("daily net profit = %v, want %v", dailyPnL, wantProfit)
+	}
+}
+
+func TestFinalizeHoldsOffImports(t *testing.T) {
+	database := testdb.New(t)
+	repo, pool := NewPostgresRepository(database), database.Pool
+	ctx := context.Background()
+
+	const login = 1001
+	competitionID := seedMember(t, pool, login)
+	if _, err := pool.Exec(ctx, `UPDATE competitions SET status = 'settling' WHERE id = $1`, competitionID); err != nil {
+		t.Fatalf("settle competition: %v", err)
+	}
+	ranking := defaultRanking(competitionID)
+
+	standingsHash := func() string {
+		t.Helper()
+		page, err := repo.GetLeaderboard(ctx, competitionID, ranking, model.LeaderboardQuery{Limit: math.MaxInt32})
+		if err != nil {
+			t.Fatalf("get leaderboard: %v", err)
+		}
+		return ResultsHash(page.Entries)
+	}
+	trade := func(id int64, profit float64) []model.Trade {
+		closeTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		return []model.Trade{{
+			PositionID: id, Symbol: "EURUSD", Side: "buy", Volume: 1,
+			OpenTime: closeTime.Add(-time.Hour), CloseTime: closeTime,
+			OpenPrice: 1.1, ClosePrice: 1.1, Profit: profit,
+		}}
+	}
+
+	// Standings checked before a late import landed are not frozen.
+	checked := standingsHash()
+	if _, err := repo.InsertTrades(ctx, competitionID, login, trade(1, 100)); err != nil {
+		t.Fatalf("insert trades: %v", err)
+	}
+	if err := repo.Finalize(ctx, competitionID, ranking, checked, nil); !errors.Is(err, ErrStandingsChanged) {
+		t.Fatalf("finalize stale standings: err = %v, want %v", err, ErrStandingsChanged)
+	}
+
+	// An import in flight holds finalization off until it commits.
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := lockOpenCompetition(ctx, sqlc.New(tx), competitionID); err != nil {
+		t.Fatalf("lock competition: %v", err)
+	}
+	checked = standingsHash()
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.Finalize(ctx, competitionID, ranking, checked, nil)
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("finalize did not wait for the import: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+
+	// Once the results are frozen, imports are turned away.
+	if _, err := repo.InsertTrades(ctx, competitionID, login, trade(2, 50)); !errors.Is(err, ErrCompetitionClosed) {
+		t.Errorf("insert trades after finalize: err = %v, want %v", err, ErrCompetitionClosed)
+	}
+}
@@ -0,0 +1,159 @@
+package competition
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// finalizeAttempts bounds how often Finalize starts over when imports keep
+// changing the standings while it checks them.
+const finalizeAttempts = 3
+
+// Finalize freezes the standings of a settling competition into an immutable
+// results snapshot. Every member is checked against the rules one last time
+// first, since some, like minimum trading days, can only be judged once the
+// competition is over. finalizedBy is nil when the scheduler finalizes on its
+// own.
+func (s *Service) Finalize(ctx context.Context, competitionID uuid.UUID, finalizedBy *uuid.UUID) error {
+	c, err := s.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+
+	switch c.Status {
+	case model.StatusSettling:
+	case model.StatusFinalized, model.StatusArchived:
+		return ErrAlreadyFinalized
+	default:
+		return ErrInvalidTransition
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = s.finalize(ctx, c, finalizedBy)
+		if !errors.Is(err, ErrStandingsChanged) || attempt == finalizeAttempts {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.emit(model.Event{
+		CompetitionID: competitionID,
+		From:          model.StatusSettling,
+		To:            model.StatusFinalized,
+		At:            time.Now(),
+	})
+	return nil
+}
+
+// finalize checks the rules against the current standings and stores them as
+// the results. The repository only stores them if they are still the
+// standings that were checked once it holds the competition row; otherwise it
+// fails with ErrStandingsChanged and finalize can be run again.
+func (s *Service) finalize(ctx context.Context, c model.Competition, finalizedBy *uuid.UUID) error {
+	ranking, err := s.GetRanking(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.standings(ctx, c)
+	if err != nil {
+		return err
+	}
+	disqualified, err := s.enforceFinalRules(ctx, c, entries)
+	if err != nil {
+		return err
+	}
+	if disqualified {
+		// The disqualified members drop to the bottom.
+		if entries, err = s.standings(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Finalize(ctx, c.ID, ranking, ResultsHash(entries), finalizedBy)
+}
+
+// enforceFinalRules evaluates every member who is still in the running
+// against the rules of the ended competition and reports whether any of them
+// was disqualified.
+func (s *Service) enforceFinalRules(ctx context.Context, c model.Competition, entries []model.LeaderboardEntry) (bool, error) {
+	rules, err := s.repo.GetRules(ctx, c.ID)
+	if err != nil {
+		if errors.Is(err, ErrRulesNotSet) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	now := time.Now()
+	disqualified := false
+	for _, e := range entries {
+		if e.DisqualifiedAt != nil {
+			continue
+		}
+
+		trades, err := s.repo.ListMemberTrades(ctx, c.ID, e.TradingAccountLogin)
+		if err != nil {
+			return false, err
+		}
+		breach := EvaluateRules(rules, e.AccountSize, trades, true)
+		if breach == nil {
+			continue
+		}
+
+		if err := s.repo.DisqualifyMember(ctx, c.ID, e.TradingAccountLogin, *breach, now); err != nil {
+			return false, err
+		}
+		disqualified = true
+	}
+	return disqualified, nil
+}
+
+// ResultsHash fingerprints final standings, so a published result set can be
+// checked against the stored hash later. Every stored column is covered,
+// metrics and score included.
+func ResultsHash(entries []model.LeaderboardEntry) string {
+	h := sha256.New()
+	for _, e := range entries {
+		score := ""
+		if e.Score != nil {
+			score = formatAmount(*e.Score)
+		}
+		fmt.Fprintf(h, "%d|%d|%s|%s|%s|%s|%s|%s|%s|%d|%s|%s|%s|%s|%s|%s|%s|%t|%s\n",
+			e.Rank,
+			e.TradingAccountLogin,
+			e.Username,
+			formatAmount(e.AccountSize),
+			formatAmount(e.Profit),
+			formatAmount(e.FloatingProfit),
+			formatAmount(e.NetDeposits),
+			formatAmount(e.Equity),
+			formatAmount(e.GainPercent),
+			e.Metrics.TradeCount,
+			formatAmount(e.Metrics.MaxDrawdownPercent),
+			formatAmount(e.Metrics.Sharpe),
+			formatAmount(e.Metrics.Sortino),
+			formatAmount(e.Metrics.ProfitFactor),
+			formatAmount(e.Metrics.WinRate),
+			formatAmount(e.Metrics.AverageR),
+			score,
+			e.DisqualifiedAt != nil,
+			e.DisqualificationReason,
+		)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,194 @@
+package competition
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// finalizeRepo is an in-memory Repository with just enough behaviour for
+// Finalize. Any other method panics.
+type finalizeRepo struct {
+	Repository
+
+	competition model.Competition
+	rules       model.Rules
+	entries     []model.LeaderboardEntry
+	trades      map[int64][]model.Trade
+
+	breaches  map[int64]model.RuleBreach
+	finalized []model.LeaderboardEntry
+
+	// importDuring, if set, runs as Finalize takes the lock, standing in for
+	// an import that committed after the rules were checked.
+	importDuring func(r *finalizeRepo)
+	attempts     int
+}
+
+func (r *finalizeRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Competition, error) {
+	return r.competition, nil
+}
+
+func (r *finalizeRepo) GetRanking(ctx context.Context, competitionID uuid.UUID) (model.Ranking, error) {
+	return model.Ranking{}, ErrRankingNotSet
+}
+
+func (r *finalizeRepo) GetRules(ctx context.Context, competitionID uuid.UUID) (model.Rules, error) {
+	return r.rules, nil
+}
+
+func (r *finalizeRepo) ListMemberTrades(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.Trade, error) {
+	return r.trades[login], nil
+}
+
+func (r *finalizeRepo) DisqualifyMember(ctx context.Context, competitionID uuid.UUID, login int64, breach model.RuleBreach, at time.Time) error {
+	r.breaches[login] = breach
+	for i := range r.entries {
+		if r.entries[i].TradingAccountLogin == login {
+			r.entries[i].DisqualifiedAt = &at
+			r.entries[i].DisqualificationReason = breach.Reason
+		}
+	}
+	return nil
+}
+
+// GetLeaderboard ranks the members by gain with the disqualified ones last,
+// as the standings query does.
+func (r *finalizeRepo) GetLeaderboard(ctx context.Context, competitionID uuid.UUID, ranking model.Ranking, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	entries := append([]model.LeaderboardEntry(nil), r.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.DisqualifiedAt == nil) != (b.DisqualifiedAt == nil) {
+			return a.DisqualifiedAt == nil
+		}
+		return a.GainPercent > b.GainPercent
+	})
+	for i := range entries {
+		entries[i].Rank = int32(i + 1)
+	}
+	return model.LeaderboardPage{Entries: entries, Total: len(entries), Limit: q.Limit}, nil
+}
+
+func (r *finalizeRepo) Finalize(ctx context.Context, id uuid.UUID, ranking model.Ranking, resultsHash string, finalizedBy *uuid.UUID) error {
+	r.attempts++
+	if r.importDuring != nil {
+		r.importDuring(r)
+	}
+
+	page, err := r.GetLeaderboard(ctx, id, ranking, model.LeaderboardQuery{})
+	if err != nil {
+		return err
+	}
+	if ResultsHash(page.Entries) != resultsHash {
+		return ErrStandingsChanged
+	}
+	r.finalized = page.Entries
+	return nil
+}
+
+func TestFinalizeEnforcesMinTradingDays(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tradeOn := func(id int64, day int) model.Trade {
+		open := start.AddDate(0, 0, day).Add(10 * time.Hour)
+		return model.Trade{PositionID: id, Symbol: "EURUSD", Volume: 1, OpenTime: open, CloseTime: open.Add(time.Hour), Profit: 100}
+	}
+
+	repo := &finalizeRepo{
+		competition: model.Competition{
+			ID:       uuid.New(),
+			Status:   model.StatusSettling,
+			StartsAt: start,
+			EndsAt:   start.AddDate(0, 0, 14),
+		},
+		rules: model.Rules{MinTradingDays: 3},
+		entries: []model.LeaderboardEntry{
+			// The best gain, but from trades opened on only two days.
+			{TradingAccountLogin: 1, AccountSize: 10000, GainPercent: 20},
+			{TradingAccountLogin: 2, AccountSize: 10000, GainPercent: 5},
+		},
+		trades: map[int64][]model.Trade{
+			1: {tradeOn(1, 0), tradeOn(2, 0), tradeOn(3, 1)},
+			2: {tradeOn(4, 0), tradeOn(5, 1), tradeOn(6, 2)},
+		},
+		breaches: make(map[int64]model.RuleBreach),
+	}
+	s := &Service{repo: repo}
+
+	if err := s.Finalize(context.Background(), repo.competition.ID, nil); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+
+	if got := repo.breaches[1].Rule; got != RuleMinTradingDays {
+		t.Errorf("member 1 breach = %q, want %q", got, RuleMinTradingDays)
+	}
+	if _, ok := repo.breaches[2]; ok {
+		t.Errorf("member 2 disqualified: %+v", repo.breaches[2])
+	}
+
+	if len(repo.finalized) != 2 {
+		t.Fatalf("finalized %d entries, want 2", len(repo.finalized))
+	}
+	winner, last := repo.finalized[0], repo.finalized[1]
+	if winner.TradingAccountLogin != 2 || winner.DisqualifiedAt != nil {
+		t.Errorf("first place = %+v, want member 2", winner)
+	}
+	if last.TradingAccountLogin != 1 || last.DisqualifiedAt == nil {
+		t.Errorf("last place = %+v, want disqualified member 1", last)
+	}
+}
+
+func newStandingsRepo() *finalizeRepo {
+	return &finalizeRepo{
+		competition: model.Competition{ID: uuid.New(), Status: model.StatusSettling},
+		entries: []model.LeaderboardEntry{
+			{TradingAccountLogin: 1, AccountSize: 10000, GainPercent: 20},
+			{TradingAccountLogin: 2, AccountSize: 10000, GainPercent: 5},
+		},
+		breaches: make(map[int64]model.RuleBreach),
+	}
+}
+
+func TestFinalizeRetriesWhenStandingsChange(t *testing.T) {
+	repo := newStandingsRepo()
+	repo.importDuring = func(r *finalizeRepo) {
+		// A late sync puts member 2 ahead, once.
+		r.importDuring = nil
+		r.entries[1].GainPercent = 30
+	}
+	s := &Service{repo: repo}
+
+	if err := s.Finalize(context.Background(), repo.competition.ID, nil); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+
+	if repo.attempts != 2 {
+		t.Errorf("attempts = %d, want 2", repo.attempts)
+	}
+	if len(repo.finalized) != 2 || repo.finalized[0].TradingAccountLogin != 2 || repo.finalized[0].GainPercent != 30 {
+		t.Errorf("finalized %+v, want member 2 first with the late trades", repo.finalized)
+	}
+}
+
+func TestFinalizeGivesUpWhenStandingsKeepChanging(t *testing.T) {
+	repo := newStandingsRepo()
+	repo.importDuring = func(r *finalizeRepo) {
+		r.entries[1].GainPercent++
+	}
+	s := &Service{repo: repo}
+
+	err := s.Finalize(context.Background(), repo.competition.ID, nil)
+	if !errors.Is(err, ErrStandingsChanged) {
+		t.Fatalf("Finalize: err = %v, want %v", err, ErrStandingsChanged)
+	}
+	if repo.attempts != finalizeAttempts {
+		t.Errorf("attempts = %d, want %d", repo.attempts, finalizeAttempts)
+	}
+	if repo.finalized != nil {
+		t.Errorf("finalized %+v, want nothing", repo.finalized)
+	}
+}
@@ -0,0 +1,112 @@
+package competition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+const (
+	RuleMaxDrawdown    = "max_drawdown"
+	RuleMaxDailyLoss   = "max_daily_loss"
+	RuleMaxLotSize     = "max_lot_size"
+	RuleAllowedSymbols = "allowed_symbols"
+	RuleMinTradingDays = "min_trading_days"
+)
+
+// EvaluateRules replays a member's closed trades in close time order and
+// returns the first rule they break, or nil if none is broken.
+//
+// Drawdown is measured from the highest equity reached so far, daily loss
+// against the starting account size per UTC day. A trade belongs to the UTC day
+// it closed on, both for daily loss and for counting trading days. Minimum
+// trading days can only be judged once the competition is over, so it is
+// checked only when ended is true.
+func EvaluateRules(rules model.Rules, accountSize float64, trades []model.Trade, ended bool) *model.RuleBreach {
+	sorted := make([]model.Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CloseTime.Equal(sorted[j].CloseTime) {
+			return sorted[i].PositionID < sorted[j].PositionID
+		}
+		return sorted[i].CloseTime.Before(sorted[j].CloseTime)
+	})
+
+	equity := accountSize
+	peak := accountSize
+	dailyPnL := make(map[string]float64)
+	tradingDays := make(map[string]struct{})
+
+	for _, t := range sorted {
+		if rules.MaxLotSize != nil && t.Volume > *rules.MaxLotSize {
+			return &model.RuleBreach{
+				Rule:       RuleMaxLotSize,
+				Reason:     fmt.Sprintf("volume %.2f exceeds max lot size %.2f", t.Volume, *rules.MaxLotSize),
+				PositionID: t.PositionID,
+			}
+		}
+
+		if len(rules.AllowedSymbols) > 0 && !symbolAllowed(rules.AllowedSymbols, t) {
+			return &model.RuleBreach{
+				Rule:       RuleAllowedSymbols,
+				Reason:     fmt.Sprintf("symbol %s is not allowed", t.Symbol),
+				PositionID: t.PositionID,
+			}
+		}
+
+		net := t.Profit + t.Commission + t.Swap
+		equity += net
+		if equity > peak {
+			peak = equity
+		}
+
+		day := t.CloseTime.UTC().Format(time.DateOnly)
+		dailyPnL[day] += net
+		tradingDays[day] = struct{}{}
+
+		if rules.MaxDrawdownPercent != nil && peak > 0 {
+			drawdown := (peak - equity) / peak * 100
+			if drawdown > *rules.MaxDrawdownPercent {
+				return &model.RuleBreach{
+					Rule:       RuleMaxDrawdown,
+					Reason:     fmt.Sprintf("drawdown %.2f%% exceeds max drawdown %.2f%%", drawdown, *rules.MaxDrawdownPercent),
+					PositionID: t.PositionID,
+				}
+			}
+		}
+
+		if rules.MaxDailyLossPercent != nil && accountSize > 0 && dailyPnL[day] < 0 {
+			loss := -dailyPnL[day] / accountSize * 100
+			if loss > *rules.MaxDailyLossPercent {
+				return &model.RuleBreach{
+					Rule:       RuleMaxDailyLoss,
+					Reason:     fmt.Sprintf("daily loss %.2f%% on %s exceeds max daily loss %.2f%%", loss, day, *rules.MaxDailyLossPercent),
+					PositionID: t.PositionID,
+				}
+			}
+		}
+	}
+
+	if ended && rules.MinTradingDays > 0 && len(tradingDays) < int(rules.MinTradingDays) {
+		return &model.RuleBreach{
+			Rule:   RuleMinTradingDays,
+			Reason: fmt.Sprintf("traded on %d days, minimum is %d", len(tradingDays), rules.MinTradingDays),
+		}
+	}
+
+	return nil
+}
+
+// symbolAllowed matches the allowed list against the trade's raw symbol and
+// its canonical instrument, so XAUUSD also allows XAUUSD.m.
+func symbolAllowed(allowed []string, t model.Trade) bool {
+	for _, s := range allowed {
+		if strings.EqualFold(s, t.Symbol) || (t.Instrument != "" && strings.EqualFold(s, t.Instrument)) {
+			return true
+		}
+	}
+	return false
+}
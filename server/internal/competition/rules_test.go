@@ -0,0 +1,63 @@
+package competition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func TestEvaluateRulesOvernightPosition(t *testing.T) {
+	maxDailyLoss := 5.0
+	rules := model.Rules{MaxDailyLossPercent: &maxDailyLoss, MinTradingDays: 2}
+	day := func(d int, hour int) time.Time {
+		return time.Date(2024, time.March, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	// Opened late on the 4th and closed early on the 5th.
+	overnight := model.Trade{PositionID: 1, Volume: 1, OpenTime: day(4, 23), CloseTime: day(5, 1), Profit: -300}
+	earlier := model.Trade{PositionID: 2, Volume: 1, OpenTime: day(4, 9), CloseTime: day(4, 10), Profit: 100}
+	later := model.Trade{PositionID: 3, Volume: 1, OpenTime: day(5, 9), CloseTime: day(5, 10), Profit: -300}
+	laterWin := later
+	laterWin.Profit = 100
+
+	tests := []struct {
+		name     string
+		trades   []model.Trade
+		wantRule string
+		wantPos  int64
+	}{
+		{
+			// Both losses count against the 5th.
+			name:     "daily loss on the close day",
+			trades:   []model.Trade{overnight, later},
+			wantRule: RuleMaxDailyLoss,
+			wantPos:  3,
+		},
+		{
+			// Opened on two days, but closed on the 5th only.
+			name:     "one close day",
+			trades:   []model.Trade{overnight, laterWin},
+			wantRule: RuleMinTradingDays,
+		},
+		{
+			// Opened on the 4th only, but closed on two days.
+			name:   "two close days",
+			trades: []model.Trade{earlier, overnight},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breach := EvaluateRules(rules, 10000, tt.trades, true)
+			switch {
+			case tt.wantRule == "" && breach != nil:
+				t.Errorf("breach = %+v, want none", breach)
+			case tt.wantRule != "" && breach == nil:
+				t.Errorf("no breach, want %s", tt.wantRule)
+			case tt.wantRule != "" && (breach.Rule != tt.wantRule || breach.PositionID != tt.wantPos):
+				t.Errorf("breach = %+v, want %s on position %d", breach, tt.wantRule, tt.wantPos)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package competition
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+type SchedulerConfig struct {
+	// Interval is how often the scheduler looks for due transitions.
+	Interval time.Duration
+	// SettlementPeriod is how long a competition stays in settling after it ends.
+	SettlementPeriod time.Duration
+	// ArchiveAfter is how long after the end a finalized competition is archived.
+	ArchiveAfter time.Duration
+}
+
+// Scheduler moves competitions through their lifecycle as their start, end,
+// settlement and archive times pass.
+type Scheduler struct {
+	service *Service
+	cfg     SchedulerConfig
+}
+
+type scheduledTransition struct {
+	from model.Status
+	to   model.Status
+	due  func(c model.Competition) time.Time
+}
+
+func NewScheduler(service *Service, cfg SchedulerConfig) *Scheduler {
+	return &Scheduler{service: service, cfg: cfg}
+}
+
+// Run checks for due transitions immediately and then every Interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick applies the transitions in lifecycle order, so a competition that is
+// several steps behind catches up within a single tick.
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	for _, t := range s.transitions() {
+		competitions, err := s.service.ListByStatus(ctx, t.from)
+		if err != nil {
+			log.Printf("SCHEDULER: list %s competitions: %v", t.from, err)
+			continue
+		}
+
+		for _, c := range competitions {
+			if now.Before(t.due(c)) {
+				continue
+			}
+
+			err := s.service.Transition(ctx, c.ID, t.to)
+			if err != nil && !errors.Is(err, ErrStatusChanged) {
+				log.Printf("SCHEDULER: move competition %s from %s to %s: %v", c.ID, t.from, t.to, err)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) transitions() []scheduledTransition {
+	return []scheduledTransition{
+		{
+			from: model.StatusRegistrationOpen,
+			to:   model.StatusRunning,
+			due:  func(c model.Competition) time.Time { return c.StartsAt },
+		},
+		{
+			from: model.StatusRunning,
+			to:   model.StatusSettling,
+			due:  func(c model.Competition) time.Time { return c.EndsAt },
+		},
+		{
+			from: model.StatusSettling,
+			to:   model.StatusFinalized,
+			due:  func(c model.Competition) time.Time { return c.EndsAt.Add(s.cfg.SettlementPeriod) },
+		},
+		{
+			from: model.StatusFinalized,
+			to:   model.StatusArchived,
+			due:  func(c model.Competition) time.Time { return c.EndsAt.Add(s.cfg.ArchiveAfter) },
+		},
+	}
+}
@@ -3,22 +3,33 @@ package competition
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/filipcvejic/trading_tournament/internal/auth"
 	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
 	"github.com/filipcvejic/trading_tournament/internal/competition/model"
 	"github.com/filipcvejic/trading_tournament/internal/crypto"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+	"github.com/filipcvejic/trading_tournament/internal/pubsub"
 	"github.com/google/uuid"
 )
 
 type Service struct {
-	repo      Repository
-	cryptoKey []byte
+	repo        Repository
+	cryptoKey   []byte
+	hub         pubsub.Hub
+	instruments *instrument.Service
+
+	mu        sync.RWMutex
+	listeners []func(model.Event)
 }
 
-func NewService(repo Repository, cryptoKeyBase64 string) (*Service, error) {
+func NewService(repo Repository, cryptoKeyBase64 string, hub pubsub.Hub, instruments *instrument.Service) (*Service, error) {
 	key, err := base64.StdEncoding.DecodeString(cryptoKeyBase64)
 	if err != nil {
 		return nil, fmt.Errorf("decode crypto key: %w", err)
@@ -26,7 +37,7 @@ func NewService(repo Repository, cryptoKeyBase64 string) (*Service, error) {
 	if len(key) != 32 {
 		return nil, crypto.ErrInvalidKeyLength
 	}
-	return &Service{repo: repo, cryptoKey: key}, nil
+	return &Service{repo: repo, cryptoKey: key, hub: hub, instruments: instruments}, nil
 }
 
 func (s *Service) Create(ctx context.Context, c model.Competition) error {
@@ -43,6 +54,13 @@ func (s *Service) Create(ctx context.Context, c model.Competition) error {
 		return ErrInvalidTimeRange
 	}
 
+	if c.WindowMode == "" {
+		c.WindowMode = model.WindowCloseTime
+	}
+	if !validWindowMode(c.WindowMode) {
+		return ErrInvalidWindowMode
+	}
+
 	if err := s.repo.Create(ctx, c); err != nil {
 		return fmt.Errorf("create competition: %w", err)
 	}
@@ -87,74 +105,328 @@ func (s *Service) JoinWithTradingAccount(
 	return s.repo.JoinWithTradingAccount(ctx, competitionID, userID, login, broker, encrypted)
 }
 
-func (s *Service) UpdateAccountSize(ctx context.Context, competitionID uuid.UUID, login int64, accountSize float64) error {
+// UpdateAccountSize sets a member's account size by hand. Admin overrides
+// must give a reason, which is kept in the audit trail.
+func (s *Service) UpdateAccountSize(ctx context.Context, competitionID uuid.UUID, login int64, change model.AccountSizeChange) error {
 	if competitionID == uuid.Nil {
 		return ErrNotFound
 	}
 	if login <= 0 {
 		return ErrInvalidLogin
 	}
-	if accountSize <= 0 {
+	if change.Size <= 0 {
 		return ErrInvalidAccountSize
 	}
+	change.Reason = strings.TrimSpace(change.Reason)
+	if change.Source == model.AccountSizeFromAdmin && change.Reason == "" {
+		return ErrReasonRequired
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	if c.Status == model.StatusFinalized || c.Status == model.StatusArchived {
+		return ErrCompetitionClosed
+	}
 
-	return s.repo.UpdateAccountSize(ctx, competitionID, login, accountSize)
+	if _, err := s.repo.SetAccountSize(ctx, competitionID, login, change); err != nil {
+		return err
+	}
+
+	s.publishLeaderboardChange(ctx, competitionID)
+	return nil
 }
 
-//const (
-//	defaultLeaderboardLimit int32 = 50
-//	maxLeaderboardLimit     int32 = 200
-//)
+const (
+	defaultLeaderboardLimit int32 = 50
+	maxLeaderboardLimit     int32 = 200
+)
+
+// GetLeaderboard returns one page of the filtered leaderboard. Total counts
+// the entries matching the filters, not just those on the page.
+func (s *Service) GetLeaderboard(ctx context.Context, competitionID uuid.UUID, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = defaultLeaderboardLimit
+	}
+	if q.Limit > maxLeaderboardLimit {
+		q.Limit = maxLeaderboardLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Status != model.MemberFilterAll && q.Status != model.MemberFilterActive && q.Status != model.MemberFilterDisqualified {
+		return model.LeaderboardPage{}, ErrInvalidLeaderboardFilter
+	}
+	if q.MinTrades < 0 {
+		return model.LeaderboardPage{}, ErrInvalidLeaderboardFilter
+	}
+
+	q.UsernamePrefix = strings.ToLower(strings.TrimSpace(q.UsernamePrefix))
 
-func (s *Service) GetLeaderboard(ctx context.Context, competitionID uuid.UUID, limit int32, offset int32) ([]model.LeaderboardEntry, error) {
-	//if limit <= 0 {
-	//	limit = defaultLeaderboardLimit
-	//}
-	//if limit > maxLeaderboardLimit {
-	//	limit = maxLeaderboardLimit
-	//}
-	//if offset < 0 {
-	//	offset = 0
-	//}
+	return s.leaderboardPage(ctx, competitionID, q)
+}
 
-	entries, err := s.repo.GetLeaderboard(ctx, competitionID, 100, 0)
+// leaderboard returns every ranked entry of a published competition.
+func (s *Service) leaderboard(ctx context.Context, competitionID uuid.UUID) ([]model.LeaderboardEntry, error) {
+	page, err := s.leaderboardPage(ctx, competitionID, model.LeaderboardQuery{Limit: math.MaxInt32})
 	if err != nil {
 		return nil, err
 	}
+	return page.Entries, nil
+}
 
-	// Verify competition exists if no entries
-	if len(entries) == 0 {
-		if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
-			return nil, err
+// leaderboardPage returns one page of a published competition's leaderboard.
+func (s *Service) leaderboardPage(ctx context.Context, competitionID uuid.UUID, q model.LeaderboardQuery) (model.LeaderboardPage, error) {
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return model.LeaderboardPage{}, err
+	}
+	var page model.LeaderboardPage
+	switch c.Status {
+	case model.StatusDraft:
+		// Drafts are not published yet
+		return model.LeaderboardPage{}, ErrNotFound
+	case model.StatusFinalized, model.StatusArchived:
+		// Final standings are frozen, metrics included
+		page, err = s.repo.ListResults(ctx, competitionID, q)
+	default:
+		var ranking model.Ranking
+		ranking, err = s.GetRanking(ctx, competitionID)
+		if err == nil {
+			page, err = s.repo.GetLeaderboard(ctx, competitionID, ranking, q)
 		}
 	}
+	if err != nil {
+		return model.LeaderboardPage{}, err
+	}
 
-	return entries, nil
+	if err := s.applyMovement(ctx, competitionID, page.Entries); err != nil {
+		return model.LeaderboardPage{}, err
+	}
+	return page, nil
 }
 
-func (s *Service) InsertTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade) error {
+// InsertTrades ingests a batch of closed trades and reports the outcome of
+// each one, in batch order. Invalid trades are rejected individually instead
+// of failing the whole batch; the valid ones are written atomically. Trades
+// outside the competition window, as defined by its window mode, are
+// quarantined for an admin to review. A trade the broker reports again with
+// different values is applied as a correction, after which the member's rules
+// are re-evaluated and the leaderboard republished.
+func (s *Service) InsertTrades(ctx context.Context, competitionID uuid.UUID, login int64, trades []model.Trade) ([]model.TradeOutcome, error) {
 	if competitionID == uuid.Nil {
-		return ErrNotFound
+		return nil, ErrNotFound
 	}
 	if login <= 0 {
-		return ErrInvalidLogin
+		return nil, ErrInvalidLogin
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAcceptsTrades(c.Status); err != nil {
+		return nil, err
 	}
 
 	size, err := s.repo.GetMemberAccountSize(ctx, competitionID, login)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if size == 0 {
-		return ErrAccountSizeNotSet
+		return nil, ErrAccountSizeNotSet
+	}
+
+	trades, err = s.tagInstruments(ctx, login, trades)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, valid, quarantined := prepareTrades(c, trades)
+
+	if err := s.repo.QuarantineTrades(ctx, competitionID, login, quarantined); err != nil {
+		return nil, err
+	}
+
+	written, err := s.repo.InsertTrades(ctx, competitionID, login, valid)
+	if err != nil {
+		return nil, err
 	}
 
+	changed := false
 	for i, t := range trades {
+		if outcomes[i].Status != "" {
+			continue
+		}
+		o := written[t.PositionID]
+		o.Index = i
+		outcomes[i] = o
+		changed = changed || o.Status == model.TradeInserted || o.Status == model.TradeUpdated
+	}
+
+	if !changed {
+		return outcomes, nil
+	}
+	defer s.publishLeaderboardChange(ctx, competitionID)
+
+	if err := s.enforceRules(ctx, c, login, size); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// tagInstruments returns a copy of trades with the canonical instrument of
+// each symbol, as named by the account's broker, filled in. Symbols missing
+// from the catalogue are kept without an instrument.
+func (s *Service) tagInstruments(ctx context.Context, login int64, trades []model.Trade) ([]model.Trade, error) {
+	broker, err := s.repo.GetBroker(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	catalogue, err := s.instruments.Catalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := make([]model.Trade, len(trades))
+	for i, t := range trades {
+		if inst, ok := catalogue.Resolve(broker, t.Symbol); ok {
+			t.Instrument = inst.Symbol
+		}
+		tagged[i] = t
+	}
+	return tagged, nil
+}
+
+// prepareTrades validates a batch and matches it against the competition
+// window. It returns an outcome per trade, with Status set only for rejected
+// and quarantined ones, the trades that may be written and those to
+// quarantine.
+func prepareTrades(c model.Competition, trades []model.Trade) ([]model.TradeOutcome, []model.Trade, []model.QuarantinedTrade) {
+	outcomes := make([]model.TradeOutcome, len(trades))
+	last := make(map[int64]int, len(trades))
+	for i, t := range trades {
+		outcomes[i] = model.TradeOutcome{Index: i, PositionID: t.PositionID}
 		if err := validateTrade(t); err != nil {
-			return fmt.Errorf("trade[%d]: %w", i, err)
+			outcomes[i].Status = model.TradeRejected
+			outcomes[i].Reason = err.Error()
+			continue
+		}
+		if prev, ok := last[t.PositionID]; ok {
+			outcomes[prev].Status = model.TradeRejected
+			outcomes[prev].Reason = "superseded by a later trade with the same position in this batch"
+		}
+		last[t.PositionID] = i
+	}
+
+	valid := make([]model.Trade, 0, len(last))
+	var quarantined []model.QuarantinedTrade
+	for i, t := range trades {
+		if outcomes[i].Status != "" || last[t.PositionID] != i {
+			continue
+		}
+		counted, reason, ok := applyWindow(c, t)
+		if !ok {
+			outcomes[i].Status = model.TradeQuarantined
+			outcomes[i].Reason = reason
+			quarantined = append(quarantined, model.QuarantinedTrade{Trade: t, Reason: reason})
+			continue
+		}
+		valid = append(valid, counted)
+	}
+	return outcomes, valid, quarantined
+}
+
+// GetTradeRevisions lists the corrections applied to a member's trade,
+// oldest first.
+func (s *Service) GetTradeRevisions(ctx context.Context, competitionID uuid.UUID, login, positionID int64) ([]model.TradeRevision, error) {
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListTradeRevisions(ctx, competitionID, login, positionID)
+}
+
+// enforceRules re-evaluates the member's full trade history against the
+// competition rules and disqualifies them on the first breach.
+func (s *Service) enforceRules(ctx context.Context, c model.Competition, login int64, accountSize float64) error {
+	rules, err := s.repo.GetRules(ctx, c.ID)
+	if err != nil {
+		if errors.Is(err, ErrRulesNotSet) {
+			return nil
+		}
+		return err
+	}
+
+	trades, err := s.repo.ListMemberTrades(ctx, c.ID, login)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	breach := EvaluateRules(rules, accountSize, trades, now.After(c.EndsAt))
+	if breach == nil {
+		return nil
+	}
+
+	return s.repo.DisqualifyMember(ctx, c.ID, login, *breach, now)
+}
+
+func (s *Service) GetRules(ctx context.Context, competitionID uuid.UUID) (model.Rules, error) {
+	if competitionID == uuid.Nil {
+		return model.Rules{}, ErrNotFound
+	}
+
+	rules, err := s.repo.GetRules(ctx, competitionID)
+	if err == nil {
+		return rules, nil
+	}
+	if !errors.Is(err, ErrRulesNotSet) {
+		return model.Rules{}, err
+	}
+
+	// No rules configured yet: report an empty rule set for existing competitions.
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return model.Rules{}, err
+	}
+	return model.Rules{CompetitionID: competitionID}, nil
+}
+
+func (s *Service) SetRules(ctx context.Context, rules model.Rules) (model.Rules, error) {
+	if rules.CompetitionID == uuid.Nil {
+		return model.Rules{}, ErrNotFound
+	}
+	if err := validateRules(rules); err != nil {
+		return model.Rules{}, err
+	}
+
+	symbols := make([]string, 0, len(rules.AllowedSymbols))
+	for _, sym := range rules.AllowedSymbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		if sym == "" {
+			return model.Rules{}, ErrInvalidRules
 		}
+		symbols = append(symbols, sym)
 	}
+	rules.AllowedSymbols = symbols
+
+	return s.repo.UpsertRules(ctx, rules)
+}
 
-	return s.repo.InsertTrades(ctx, competitionID, login, trades)
+func validateRules(r model.Rules) error {
+	if r.MaxDrawdownPercent != nil && (*r.MaxDrawdownPercent <= 0 || *r.MaxDrawdownPercent > 100) {
+		return ErrInvalidRules
+	}
+	if r.MaxDailyLossPercent != nil && (*r.MaxDailyLossPercent <= 0 || *r.MaxDailyLossPercent > 100) {
+		return ErrInvalidRules
+	}
+	if r.MaxLotSize != nil && *r.MaxLotSize <= 0 {
+		return ErrInvalidRules
+	}
+	if r.MinTradingDays < 0 {
+		return ErrInvalidRules
+	}
+	return nil
 }
 
 func validateTrade(t model.Trade) error {
@@ -196,9 +468,64 @@ func (s *Service) GetCurrentCompetition(ctx context.Context) (*dto.CompetitionRe
 		Name:     c.Name,
 		StartsAt: c.StartsAt,
 		EndsAt:   c.EndsAt,
+		Status:   model.Status(c.Status),
 	}, nil
 }
 
 func (s *Service) RequestAccount(ctx context.Context, userID, competitionID uuid.UUID) error {
 	return s.repo.CreateAccountRequest(ctx, userID, competitionID)
 }
+
+func (s *Service) ListByStatus(ctx context.Context, status model.Status) ([]model.Competition, error) {
+	if !ValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+	return s.repo.ListByStatus(ctx, status)
+}
+
+// Transition moves a competition to the given lifecycle status and notifies
+// subscribers once the change is stored.
+func (s *Service) Transition(ctx context.Context, competitionID uuid.UUID, to model.Status) error {
+	if !ValidStatus(to) {
+		return ErrInvalidStatus
+	}
+
+	c, err := s.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	if !CanTransition(c.Status, to) {
+		return ErrInvalidTransition
+	}
+	if to == model.StatusFinalized {
+		return s.Finalize(ctx, competitionID, nil)
+	}
+
+	if err := s.repo.TransitionStatus(ctx, competitionID, c.Status, to); err != nil {
+		return err
+	}
+
+	s.emit(model.Event{
+		CompetitionID: competitionID,
+		From:          c.Status,
+		To:            to,
+		At:            time.Now(),
+	})
+	return nil
+}
+
+// Subscribe registers fn to be called after every lifecycle transition.
+// Listeners run synchronously and should return quickly.
+func (s *Service) Subscribe(fn func(model.Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+func (s *Service) emit(e model.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, fn := range s.listeners {
+		fn(e)
+	}
+}
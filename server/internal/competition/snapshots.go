@@ -0,0 +1,78 @@
+package competition
+
+import (
+	"context"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// TakeSnapshot records the current standings so later leaderboards can show
+// how members moved since.
+func (s *Service) TakeSnapshot(ctx context.Context, competitionID uuid.UUID) error {
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.standings(ctx, c)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return s.repo.CreateSnapshot(ctx, competitionID, entries)
+}
+
+// GetRankHistory returns a member's snapshotted standings, oldest first.
+func (s *Service) GetRankHistory(ctx context.Context, competitionID uuid.UUID, login int64) ([]model.RankHistoryPoint, error) {
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == model.StatusDraft {
+		return nil, ErrNotFound
+	}
+
+	return s.repo.ListRankHistory(ctx, competitionID, login)
+}
+
+// applyMovement fills in rank change, gain change and best rank from the
+// latest snapshot. A positive rank change means the member moved up.
+func (s *Service) applyMovement(ctx context.Context, competitionID uuid.UUID, entries []model.LeaderboardEntry) error {
+	previous, err := s.repo.GetLatestSnapshot(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	best, err := s.repo.GetBestRanks(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+
+	byLogin := make(map[int64]model.SnapshotEntry, len(previous))
+	for _, p := range previous {
+		byLogin[p.TradingAccountLogin] = p
+	}
+
+	for i := range entries {
+		e := &entries[i]
+
+		e.BestRank = e.Rank
+		if b, ok := best[e.TradingAccountLogin]; ok && b < e.BestRank {
+			e.BestRank = b
+		}
+
+		p, ok := byLogin[e.TradingAccountLogin]
+		if !ok {
+			continue
+		}
+		rankChange := p.Rank - e.Rank
+		gainChange := e.GainPercent - p.GainPercent
+		e.RankChange = &rankChange
+		e.GainChange = &gainChange
+	}
+
+	return nil
+}
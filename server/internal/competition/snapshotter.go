@@ -0,0 +1,51 @@
+package competition
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+// Snapshotter periodically records the leaderboard of every competition that
+// is still trading or settling.
+type Snapshotter struct {
+	service  *Service
+	interval time.Duration
+}
+
+func NewSnapshotter(service *Service, interval time.Duration) *Snapshotter {
+	return &Snapshotter{service: service, interval: interval}
+}
+
+// Run takes a snapshot every interval until ctx is done.
+func (s *Snapshotter) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *Snapshotter) tick(ctx context.Context) {
+	for _, status := range []model.Status{model.StatusRunning, model.StatusSettling} {
+		competitions, err := s.service.ListByStatus(ctx, status)
+		if err != nil {
+			log.Printf("SNAPSHOTTER: list %s competitions: %v", status, err)
+			continue
+		}
+
+		for _, c := range competitions {
+			if err := s.service.TakeSnapshot(ctx, c.ID); err != nil {
+				log.Printf("SNAPSHOTTER: snapshot competition %s: %v", c.ID, err)
+			}
+		}
+	}
+}
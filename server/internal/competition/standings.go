@@ -0,0 +1,304 @@
+package competition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// fallbackRiskPercent sizes one R, as a percent of the account, for members
+// without a losing trade to measure their typical risk from.
+const fallbackRiskPercent = 1.0
+
+// metricColumns maps every ranking metric to its column in the standings,
+// oriented so that higher is better. Only these expressions are ever spliced
+// into the standings query.
+var metricColumns = map[model.Metric]string{
+	model.MetricGain:         "gain_percent",
+	model.MetricMaxDrawdown:  "-max_drawdown_percent",
+	model.MetricSharpe:       "sharpe",
+	model.MetricSortino:      "sortino",
+	model.MetricProfitFactor: "profit_factor",
+	model.MetricWinRate:      "win_rate",
+	model.MetricAverageR:     "average_r",
+	model.MetricTradeCount:   "trade_count::FLOAT8",
+}
+
+// standingsMetrics computes every member's leaderboard values from the
+// maintained stats, daily P&L, floating P&L and balance operations, up to
+// now or the end of the competition, whichever comes first.
+//
+// Balance operations made during the competition are weighted by the share
+// of the period they spent in the account (modified Dietz), so a deposit on
+// the last day barely raises the capital base that gain is measured against.
+// Sharpe and Sortino use daily returns for every UTC day of the period,
+// counting days without closed trades as flat, and are not annualized. One R
+// is the member's average losing trade. Ratios over zero are +Infinity when
+// positive and 0 otherwise.
+//
+// $1 is the competition, $8 whether floating P&L counts and $9 the fallback
+// risk percent.
+const standingsMetrics = `
+WITH period AS (
+    SELECT starts_at, LEAST(now(), ends_at) AS ends_at
+    FROM competitions
+    WHERE id = $1::UUID
+), flows AS (
+    SELECT
+        o.trading_account_login,
+        SUM(o.signed) AS net_deposits,
+        SUM(o.signed * o.weight) AS weighted_deposits
+    FROM (
+        SELECT
+            b.trading_account_login,
+            CASE WHEN b.type = 'withdrawal' THEN -b.amount ELSE b.amount END AS signed,
+            CASE
+                WHEN p.ends_at > p.starts_at THEN GREATEST(0, LEAST(1,
+                    EXTRACT(EPOCH FROM p.ends_at - b.occurred_at) / EXTRACT(EPOCH FROM p.ends_at - p.starts_at)
+                ))
+                ELSE 1
+            END AS weight
+        FROM balance_operations b
+        CROSS JOIN period p
+        WHERE b.competition_id = $1::UUID
+        AND b.occurred_at >= p.starts_at
+    ) o
+    GROUP BY o.trading_account_login
+), daily AS (
+    SELECT
+        cm.trading_account_login,
+        COALESCE(d.net_profit, 0) AS pnl,
+        cm.account_size + COALESCE(SUM(d.net_profit) OVER (
+            PARTITION BY cm.trading_account_login
+            ORDER BY g.day
+            ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+        ), 0) AS equity
+    FROM competition_members cm
+    CROSS JOIN period p
+    CROSS JOIN generate_series(
+        (p.starts_at AT TIME ZONE 'UTC')::DATE,
+        (p.ends_at AT TIME ZONE 'UTC')::DATE,
+        INTERVAL '1 day'
+    ) AS g(day)
+    LEFT JOIN competition_member_daily_pnl d ON d.competition_id = cm.competition_id
+    AND d.trading_account_login = cm.trading_account_login
+    AND d.day = g.day::DATE
+    WHERE cm.competition_id = $1::UUID
+), returns AS (
+    SELECT
+        trading_account_login,
+        COUNT(*) AS days,
+        AVG(pnl / equity) AS mean,
+        STDDEV_SAMP(pnl / equity) AS std_dev,
+        SQRT(SUM(CASE WHEN pnl < 0 THEN (pnl / equity) ^ 2 ELSE 0 END) / COUNT(*)) AS downside_dev
+    FROM daily
+    WHERE equity > 0
+    GROUP BY trading_account_login
+), members AS (
+    SELECT
+        cm.trading_account_login,
+        ta.user_id,
+        u.username,
+        cm.account_size::FLOAT8 AS account_size,
+        COALESCE(s.realized_profit + s.commission + s.swap, 0)::FLOAT8 AS profit,
+        COALESCE(f.floating_profit, 0)::FLOAT8 AS floating_profit,
+        COALESCE(fl.net_deposits, 0)::FLOAT8 AS net_deposits,
+        (cm.account_size + COALESCE(fl.weighted_deposits, 0))::FLOAT8 AS capital_base,
+        (
+            COALESCE(s.realized_profit + s.commission + s.swap, 0)
+            + CASE WHEN $8::BOOLEAN THEN COALESCE(f.floating_profit, 0) ELSE 0 END
+        )::FLOAT8 AS pnl,
+        cm.disqualified_at,
+        COALESCE(cm.disqualification_reason, '') AS disqualification_reason,
+        COALESCE(s.trade_count, 0) AS trade_count,
+        COALESCE(s.max_drawdown_percent, 0) AS max_drawdown_percent,
+        COALESCE(s.wins * 100.0 / NULLIF(s.trade_count, 0), 0)::FLOAT8 AS win_rate,
+        CASE
+            WHEN s.gross_loss > 0 THEN (s.gross_profit / s.gross_loss)::FLOAT8
+            WHEN s.gross_profit > 0 THEN 'Infinity'::FLOAT8
+            ELSE 0
+        END AS profit_factor,
+        COALESCE(
+            (s.realized_profit + s.commission + s.swap) / NULLIF(s.trade_count, 0) / NULLIF(
+                CASE WHEN s.losses > 0 THEN s.gross_loss / s.losses ELSE cm.account_size * $9::FLOAT8 / 100 END,
+                0
+            ),
+            0
+        )::FLOAT8 AS average_r,
+        CASE
+            WHEN r.days IS NULL OR r.days < 2 THEN 0
+            WHEN r.std_dev = 0 THEN CASE WHEN r.mean > 0 THEN 'Infinity'::FLOAT8 ELSE 0 END
+            ELSE (r.mean / r.std_dev)::FLOAT8
+        END AS sharpe,
+        CASE
+            WHEN r.days IS NULL OR r.days < 2 THEN 0
+            WHEN r.downside_dev = 0 THEN CASE WHEN r.mean > 0 THEN 'Infinity'::FLOAT8 ELSE 0 END
+            ELSE (r.mean / r.downside_dev)::FLOAT8
+        END AS sortino
+    FROM competition_members cm
+    JOIN trading_accounts ta ON ta.login = cm.trading_account_login
+    JOIN users u ON u.id = ta.user_id
+    LEFT JOIN competition_member_stats s ON s.competition_id = cm.competition_id
+    AND s.trading_account_login = cm.trading_account_login
+    LEFT JOIN competition_member_floating f ON f.competition_id = cm.competition_id
+    AND f.trading_account_login = cm.trading_account_login
+    LEFT JOIN flows fl ON fl.trading_account_login = cm.trading_account_login
+    LEFT JOIN returns r ON r.trading_account_login = cm.trading_account_login
+    WHERE cm.competition_id = $1::UUID
+), valued AS (
+    SELECT
+        m.*,
+        m.account_size + m.net_deposits + m.pnl AS equity,
+        CASE WHEN m.capital_base > 0 THEN m.pnl / m.capital_base * 100 ELSE 0 END AS gain_percent
+    FROM members m
+)`
+
+// standingsRanking ranks the scored members; %s is the ordering of the
+// ranking.
+const standingsRanking = `, ranked AS (
+    SELECT *, ROW_NUMBER() OVER (ORDER BY %s)::INT AS rank
+    FROM scored
+)`
+
+// standingsPage filters the ranked rows and cuts out one page. It always
+// returns at least one row carrying the filtered total and the offset of the
+// page; the entry columns are NULL when the page is empty, and the offset is
+// NULL when the page should centre on a user ($5) who has no entry.
+//
+// $2 is the lowercase username prefix, $3 the member filter, $4 the minimum
+// trade count, $6 the offset and $7 the limit.
+const standingsPage = `, filtered AS (
+    SELECT *, ROW_NUMBER() OVER (ORDER BY rank) AS position
+    FROM ranked
+    WHERE starts_with(lower(username), $2::TEXT)
+    AND ($3::TEXT = '' OR ($3::TEXT = 'disqualified') = (disqualified_at IS NOT NULL))
+    AND trade_count >= $4::INT
+), page AS (
+    SELECT
+        (SELECT COUNT(*) FROM filtered) AS total,
+        CASE
+            WHEN $5::UUID IS NULL THEN $6::BIGINT
+            ELSE (SELECT GREATEST(0, MIN(position) - 1 - $7::BIGINT / 2) FROM filtered WHERE user_id = $5::UUID)
+        END AS page_offset
+)
+SELECT
+    p.total,
+    p.page_offset,
+    f.trading_account_login,
+    COALESCE(f.rank, 0),
+    COALESCE(f.username, ''),
+    COALESCE(f.account_size, 0),
+    COALESCE(f.profit, 0),
+    COALESCE(f.floating_profit, 0),
+    COALESCE(f.net_deposits, 0),
+    COALESCE(f.equity, 0),
+    COALESCE(f.gain_percent, 0),
+    f.disqualified_at,
+    COALESCE(f.disqualification_reason, ''),
+    COALESCE(f.trade_count, 0),
+    COALESCE(f.max_drawdown_percent, 0),
+    COALESCE(f.sharpe, 0),
+    COALESCE(f.sortino, 0),
+    COALESCE(f.profit_factor, 0),
+    COALESCE(f.win_rate, 0),
+    COALESCE(f.average_r, 0),
+    f.score
+FROM page p
+LEFT JOIN filtered f ON f.position > p.page_offset
+AND f.position <= p.page_offset + $7::BIGINT
+ORDER BY f.position`
+
+// standingsQuery builds the query for one page of the live standings of
+// competitionID, ranked the way ranking says. Disqualified members always
+// come last. Equal primary values are settled by the tie-breaks in order,
+// and finally by the lower trading account login.
+func standingsQuery(competitionID uuid.UUID, ranking model.Ranking, q model.LeaderboardQuery) (string, []any) {
+	args := []any{
+		competitionID,
+		q.UsernamePrefix,
+		string(q.Status),
+		q.MinTrades,
+		q.AroundUserID,
+		int64(q.Offset),
+		int64(q.Limit),
+		ranking.EquityMode != model.EquityModeRealized,
+		fallbackRiskPercent,
+	}
+
+	scored := `, scored AS (
+    SELECT *, NULL::FLOAT8 AS score
+    FROM valued
+)`
+	primary := metricColumn(model.Metric(ranking.Mode))
+	if ranking.Mode == model.RankingWeighted {
+		scored, args = weightedScore(ranking.Weights, args)
+		primary = "score"
+	}
+
+	tieBreaks := ranking.TieBreaks
+	if len(tieBreaks) == 0 {
+		tieBreaks = defaultTieBreaks
+	}
+	order := []string{"(disqualified_at IS NOT NULL)", primary + " DESC"}
+	for _, m := range tieBreaks {
+		order = append(order, metricColumn(m)+" DESC")
+	}
+	order = append(order, "trading_account_login")
+
+	ranked := fmt.Sprintf(standingsRanking, strings.Join(order, ", "))
+	return standingsMetrics + scored + ranked + standingsPage, args
+}
+
+func metricColumn(m model.Metric) string {
+	if col, ok := metricColumns[m]; ok {
+		return col
+	}
+	return metricColumns[model.MetricGain]
+}
+
+// weightedScore returns the CTEs that give every valued member its
+// weighted score, appending the weights to args. Every metric is min-max
+// normalized across the members first, so weights are comparable whatever
+// the metric's scale. Infinite values are left out of the range and
+// normalize to 1 or 0.
+func weightedScore(weights map[model.Metric]float64, args []any) (string, []any) {
+	metrics := make([]model.Metric, 0, len(weights))
+	for m, w := range weights {
+		if w != 0 {
+			metrics = append(metrics, m)
+		}
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i] < metrics[j] })
+
+	var bounds, terms []string
+	for i, m := range metrics {
+		col := metricColumn(m)
+		args = append(args, weights[m])
+
+		bounds = append(bounds,
+			fmt.Sprintf("MIN(%[1]s) FILTER (WHERE abs(%[1]s) <> 'Infinity') AS lo_%[2]d", col, i),
+			fmt.Sprintf("MAX(%[1]s) FILTER (WHERE abs(%[1]s) <> 'Infinity') AS hi_%[2]d", col, i),
+		)
+		terms = append(terms, fmt.Sprintf(`$%[1]d::FLOAT8 * CASE
+            WHEN %[2]s = 'Infinity' THEN 1
+            WHEN %[2]s = '-Infinity' THEN 0
+            WHEN hi_%[3]d > lo_%[3]d THEN (%[2]s - lo_%[3]d) / (hi_%[3]d - lo_%[3]d)
+            ELSE 1
+        END`, len(args), col, i))
+	}
+
+	return fmt.Sprintf(`, bounds AS (
+    SELECT
+        %s
+    FROM valued
+), scored AS (
+    SELECT
+        valued.*,
+        %s AS score
+    FROM valued
+    CROSS JOIN bounds
+)`, strings.Join(bounds, ",\n        "), strings.Join(terms, "\n        + ")), args
+}
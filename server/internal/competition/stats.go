@@ -0,0 +1,52 @@
+package competition
+
+import (
+	"context"
+	"errors"
+
+	"github.com/filipcvejic/trading_tournament/internal/analytics"
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+// GetMemberStats analyses a member's closed trades, starting the equity
+// curve at their account size.
+func (s *Service) GetMemberStats(ctx context.Context, competitionID uuid.UUID, login int64) (analytics.Report, error) {
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return analytics.Report{}, err
+	}
+
+	size, err := s.repo.GetMemberAccountSize(ctx, competitionID, login)
+	if err != nil {
+		if errors.Is(err, ErrNotMember) {
+			return analytics.Report{}, ErrMemberNotFound
+		}
+		return analytics.Report{}, err
+	}
+	trades, err := s.repo.ListMemberTrades(ctx, competitionID, login)
+	if err != nil {
+		return analytics.Report{}, err
+	}
+
+	return analytics.Analyze(size, analyticsTrades(trades)), nil
+}
+
+func analyticsTrades(trades []model.Trade) []analytics.Trade {
+	out := make([]analytics.Trade, 0, len(trades))
+	for _, t := range trades {
+		symbol := t.Instrument
+		if symbol == "" {
+			symbol = t.Symbol
+		}
+		out = append(out, analytics.Trade{
+			PositionID: t.PositionID,
+			Symbol:     symbol,
+			Side:       t.Side,
+			Volume:     t.Volume,
+			OpenTime:   t.OpenTime,
+			CloseTime:  t.CloseTime,
+			NetProfit:  t.Profit + t.Commission + t.Swap,
+		})
+	}
+	return out
+}
@@ -0,0 +1,319 @@
+package competition
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/dto"
+	"github.com/filipcvejic/trading_tournament/internal/competition/mapper"
+	"github.com/filipcvejic/trading_tournament/internal/pubsub"
+	"github.com/google/uuid"
+)
+
+// LeaderboardChannel carries the IDs of competitions whose standings may
+// have changed.
+const LeaderboardChannel = "leaderboard_changes"
+
+const (
+	// streamBacklog is how many past events are kept for Last-Event-ID replay.
+	streamBacklog = 256
+	// clientBuffer is how many events a client may fall behind before it is
+	// dropped and has to reconnect.
+	clientBuffer = 16
+)
+
+const (
+	StreamEventSnapshot = "snapshot"
+	StreamEventDiff     = "diff"
+)
+
+// StreamEvent is one server-sent event. Its ID is "<epoch>-<seq>": seq
+// counts the stream's events and epoch identifies the stream itself, which
+// is new after a restart, on another instance, or once everyone has left.
+// IDs from another epoch cannot be replayed.
+type StreamEvent struct {
+	ID   string
+	Type string
+	Data []byte
+
+	seq uint64
+}
+
+// Broadcaster turns leaderboard change notifications into per-competition
+// streams of diffs.
+type Broadcaster struct {
+	service *Service
+	hub     pubsub.Hub
+
+	mu      sync.Mutex
+	streams map[uuid.UUID]*leaderboardStream
+}
+
+type leaderboardStream struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	loaded  bool
+	dirty   bool
+	current []dto.LeaderboardEntryResponse
+	backlog []StreamEvent
+	clients map[chan StreamEvent]struct{}
+	// removed is set once the last client left and the stream is being
+	// dropped; new clients must start a new stream.
+	removed bool
+}
+
+func newLeaderboardStream() *leaderboardStream {
+	epoch := make([]byte, 6)
+	_, _ = rand.Read(epoch)
+	return &leaderboardStream{
+		epoch:   hex.EncodeToString(epoch),
+		clients: make(map[chan StreamEvent]struct{}),
+	}
+}
+
+func (st *leaderboardStream) eventID(seq uint64) string {
+	return fmt.Sprintf("%s-%d", st.epoch, seq)
+}
+
+func NewBroadcaster(service *Service, hub pubsub.Hub) *Broadcaster {
+	return &Broadcaster{
+		service: service,
+		hub:     hub,
+		streams: make(map[uuid.UUID]*leaderboardStream),
+	}
+}
+
+// Run consumes change notifications until ctx is done. Competitions nobody
+// is watching have no stream and are recomputed on the next subscribe.
+func (b *Broadcaster) Run(ctx context.Context) {
+	notifications, cancel := b.hub.Subscribe(LeaderboardChannel)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case payload := <-notifications:
+			id, err := uuid.Parse(payload)
+			if err != nil {
+				continue
+			}
+
+			b.mu.Lock()
+			st := b.streams[id]
+			b.mu.Unlock()
+			if st == nil {
+				continue
+			}
+
+			st.mu.Lock()
+			if len(st.clients) == 0 {
+				st.dirty = true
+			} else if err := b.refresh(ctx, id, st); err != nil {
+				log.Printf("STREAM: refresh leaderboard %s: %v", id, err)
+			}
+			st.mu.Unlock()
+		}
+	}
+}
+
+// Subscribe registers a client for a competition's leaderboard. The returned
+// replay holds the events the client missed after lastEventID, or a full
+// snapshot when they cannot be replayed. The events channel is closed when
+// cancel is called or when the client falls too far behind.
+func (b *Broadcaster) Subscribe(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	lastEventID string,
+) ([]StreamEvent, <-chan StreamEvent, func(), error) {
+	var st *leaderboardStream
+	for {
+		st = b.stream(competitionID)
+		st.mu.Lock()
+		if !st.removed {
+			break
+		}
+		st.mu.Unlock()
+		b.drop(competitionID, st)
+	}
+	defer st.mu.Unlock()
+
+	if !st.loaded || st.dirty {
+		if err := b.refresh(ctx, competitionID, st); err != nil {
+			if len(st.clients) == 0 {
+				st.removed = true
+				go b.drop(competitionID, st)
+			}
+			return nil, nil, nil, err
+		}
+	}
+
+	replay, ok := st.replaySince(lastEventID)
+	if !ok {
+		snapshot, err := json.Marshal(st.current)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		replay = []StreamEvent{{ID: st.eventID(st.seq), Type: StreamEventSnapshot, Data: snapshot, seq: st.seq}}
+	}
+
+	ch := make(chan StreamEvent, clientBuffer)
+	st.clients[ch] = struct{}{}
+
+	cancel := func() {
+		st.mu.Lock()
+		if _, ok := st.clients[ch]; ok {
+			delete(st.clients, ch)
+			close(ch)
+		}
+		last := len(st.clients) == 0 && !st.removed
+		if last {
+			st.removed = true
+		}
+		st.mu.Unlock()
+
+		if last {
+			b.drop(competitionID, st)
+		}
+	}
+
+	return replay, ch, cancel, nil
+}
+
+// stream returns the competition's stream, starting one if there is none.
+func (b *Broadcaster) stream(competitionID uuid.UUID) *leaderboardStream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.streams[competitionID]
+	if !ok {
+		st = newLeaderboardStream()
+		b.streams[competitionID] = st
+	}
+	return st
+}
+
+// drop forgets st, unless the competition has moved on to a newer stream.
+func (b *Broadcaster) drop(competitionID uuid.UUID, st *leaderboardStream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.streams[competitionID] == st {
+		delete(b.streams, competitionID)
+	}
+}
+
+// refresh reloads the leaderboard and sends the diff against the previous
+// state to every client. The caller must hold st.mu.
+func (b *Broadcaster) refresh(ctx context.Context, competitionID uuid.UUID, st *leaderboardStream) error {
+	entries, err := b.service.leaderboard(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	next := mapper.LeaderboardToDTO(entries)
+
+	if !st.loaded {
+		st.current = next
+		st.loaded = true
+		st.dirty = false
+		return nil
+	}
+
+	diff := diffLeaderboard(st.current, next)
+	st.current = next
+	st.dirty = false
+	if len(diff.Updated) == 0 && len(diff.Removed) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	st.seq++
+	e := StreamEvent{ID: st.eventID(st.seq), Type: StreamEventDiff, Data: data, seq: st.seq}
+
+	st.backlog = append(st.backlog, e)
+	if len(st.backlog) > streamBacklog {
+		st.backlog = st.backlog[len(st.backlog)-streamBacklog:]
+	}
+
+	for ch := range st.clients {
+		select {
+		case ch <- e:
+		default:
+			delete(st.clients, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// replaySince returns the backlog after lastEventID, or false if lastEventID
+// is unknown, from another epoch or older than the backlog.
+func (st *leaderboardStream) replaySince(lastEventID string) ([]StreamEvent, bool) {
+	epoch, seq, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != st.epoch {
+		return nil, false
+	}
+	last, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || last > st.seq {
+		return nil, false
+	}
+	if last == st.seq {
+		return nil, true
+	}
+	if len(st.backlog) == 0 || last+1 < st.backlog[0].seq {
+		return nil, false
+	}
+
+	return append([]StreamEvent(nil), st.backlog[last+1-st.backlog[0].seq:]...), true
+}
+
+func diffLeaderboard(prev, next []dto.LeaderboardEntryResponse) dto.LeaderboardDiffResponse {
+	diff := dto.LeaderboardDiffResponse{
+		Updated: []dto.LeaderboardEntryResponse{},
+		Removed: []int64{},
+	}
+
+	byLogin := make(map[int64]dto.LeaderboardEntryResponse, len(prev))
+	for _, e := range prev {
+		byLogin[e.TradingAccountLogin] = e
+	}
+
+	for _, e := range next {
+		old, ok := byLogin[e.TradingAccountLogin]
+		if !ok || !reflect.DeepEqual(old, e) {
+			diff.Updated = append(diff.Updated, e)
+		}
+		delete(byLogin, e.TradingAccountLogin)
+	}
+
+	for login := range byLogin {
+		diff.Removed = append(diff.Removed, login)
+	}
+
+	return diff
+}
+
+// publishLeaderboardChange tells every instance that the competition's
+// standings may have moved. Failures are logged rather than returned, since
+// the change itself has already been stored.
+func (s *Service) publishLeaderboardChange(ctx context.Context, competitionID uuid.UUID) {
+	if s.hub == nil {
+		return
+	}
+	if err := s.hub.Publish(ctx, LeaderboardChannel, competitionID.String()); err != nil {
+		log.Printf("STREAM: publish leaderboard change %s: %v", competitionID, err)
+	}
+}
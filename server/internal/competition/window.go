@@ -0,0 +1,143 @@
+package competition
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+	"github.com/google/uuid"
+)
+
+func validWindowMode(m model.WindowMode) bool {
+	switch m {
+	case model.WindowCloseTime, model.WindowOpenInside, model.WindowProRate:
+		return true
+	}
+	return false
+}
+
+// SetWindowMode changes which trades count towards the competition. It is
+// only allowed before the competition starts, so that every trade of a
+// competition is judged the same way.
+func (s *Service) SetWindowMode(ctx context.Context, competitionID uuid.UUID, mode model.WindowMode) error {
+	if !validWindowMode(mode) {
+		return ErrInvalidWindowMode
+	}
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return err
+	}
+
+	return s.repo.SetWindowMode(ctx, competitionID, mode)
+}
+
+func (s *Service) ListQuarantinedTrades(ctx context.Context, competitionID uuid.UUID, status model.QuarantineStatus) ([]model.QuarantinedTrade, error) {
+	switch status {
+	case "", model.QuarantinePending, model.QuarantineApproved, model.QuarantineDismissed:
+	default:
+		return nil, ErrInvalidQuarantineStatus
+	}
+	if _, err := s.repo.GetByID(ctx, competitionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListQuarantinedTrades(ctx, competitionID, status)
+}
+
+// ResolveQuarantinedTrade closes a pending quarantine entry. An approved
+// trade counts in full, whatever the window mode, after which the member's
+// rules are re-evaluated.
+func (s *Service) ResolveQuarantinedTrade(
+	ctx context.Context,
+	competitionID uuid.UUID,
+	id int64,
+	status model.QuarantineStatus,
+	reviewedBy uuid.UUID,
+) error {
+	if status != model.QuarantineApproved && status != model.QuarantineDismissed {
+		return ErrInvalidQuarantineStatus
+	}
+
+	c, err := s.repo.GetByID(ctx, competitionID)
+	if err != nil {
+		return err
+	}
+	if status == model.QuarantineApproved {
+		if err := checkAcceptsTrades(c.Status); err != nil {
+			return err
+		}
+	}
+
+	resolved, err := s.repo.ResolveQuarantinedTrade(ctx, competitionID, id, status, reviewedBy)
+	if err != nil {
+		return err
+	}
+	if status != model.QuarantineApproved {
+		return nil
+	}
+	defer s.publishLeaderboardChange(ctx, competitionID)
+
+	login := resolved.Trade.TradingAccountLogin
+	size, err := s.repo.GetMemberAccountSize(ctx, competitionID, login)
+	if err != nil {
+		return err
+	}
+	return s.enforceRules(ctx, c, login, size)
+}
+
+// applyWindow matches a trade against the competition window. It returns the
+// trade as it should be stored, pro-rated when the mode asks for it, or false
+// and the reason the trade belongs in quarantine.
+func applyWindow(c model.Competition, t model.Trade) (model.Trade, string, bool) {
+	switch c.WindowMode {
+	case model.WindowOpenInside:
+		if t.OpenTime.Before(c.StartsAt) {
+			return t, "opened before the competition started", false
+		}
+		if t.CloseTime.After(c.EndsAt) {
+			return t, "closed after the competition ended", false
+		}
+	case model.WindowProRate:
+		if t.CloseTime.Before(c.StartsAt) {
+			return t, "closed before the competition started", false
+		}
+		if t.OpenTime.After(c.EndsAt) {
+			return t, "opened after the competition ended", false
+		}
+		return proRate(t, c.StartsAt, c.EndsAt), "", true
+	default:
+		if t.CloseTime.Before(c.StartsAt) {
+			return t, "closed before the competition started", false
+		}
+		if t.CloseTime.After(c.EndsAt) {
+			return t, "closed after the competition ended", false
+		}
+	}
+	return t, "", true
+}
+
+// proRate scales the trade's profit, commission and swap by the share of its
+// duration that lies inside [start, end], rounded to cents.
+func proRate(t model.Trade, start, end time.Time) model.Trade {
+	d := t.CloseTime.Sub(t.OpenTime)
+	if d <= 0 {
+		return t
+	}
+
+	from, to := t.OpenTime, t.CloseTime
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	share := float64(to.Sub(from)) / float64(d)
+	if share >= 1 {
+		return t
+	}
+
+	t.Profit = math.Round(t.Profit*share*100) / 100
+	t.Commission = math.Round(t.Commission*share*100) / 100
+	t.Swap = math.Round(t.Swap*share*100) / 100
+	return t
+}
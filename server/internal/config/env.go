@@ -1,7 +1,27 @@
 package config
 
-import "os"
+import (
+	"log"
+	"os"
+	"time"
+)
 
 func IsProduction() bool {
 	return os.Getenv("ENV") == "production"
 }
+
+// Duration reads a time.ParseDuration value (e.g. "30s", "1h") from the
+// environment, falling back when the variable is unset or invalid.
+func Duration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration for %s: %q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,115 @@
+package instrument
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Catalogue resolves raw broker symbols to instruments. It is immutable and
+// safe for concurrent use.
+type Catalogue struct {
+	instruments map[string]Instrument
+	aliases     map[aliasKey]string
+	// names holds every symbol and alias, longest first, for matching
+	// symbols that carry a broker prefix or suffix.
+	names []name
+}
+
+type aliasKey struct {
+	broker string
+	alias  string
+}
+
+type name struct {
+	key    string
+	broker string
+	symbol string
+}
+
+func NewCatalogue(instruments []Instrument, aliases []Alias) *Catalogue {
+	c := &Catalogue{
+		instruments: make(map[string]Instrument, len(instruments)),
+		aliases:     make(map[aliasKey]string, len(aliases)),
+	}
+
+	for _, i := range instruments {
+		c.instruments[i.Symbol] = i
+		c.names = append(c.names, name{key: i.Symbol, symbol: i.Symbol})
+	}
+	for _, a := range aliases {
+		if _, ok := c.instruments[a.Symbol]; !ok {
+			continue
+		}
+		broker := NormalizeBroker(a.Broker)
+		c.aliases[aliasKey{broker, a.Alias}] = a.Symbol
+		c.names = append(c.names, name{key: a.Alias, broker: broker, symbol: a.Symbol})
+	}
+
+	// Longer names first so that e.g. US500 wins over US50, and aliases of
+	// a specific broker before global ones of the same length.
+	sort.SliceStable(c.names, func(i, j int) bool {
+		if len(c.names[i].key) != len(c.names[j].key) {
+			return len(c.names[i].key) > len(c.names[j].key)
+		}
+		return c.names[i].broker > c.names[j].broker
+	})
+	return c
+}
+
+func (c *Catalogue) Get(symbol string) (Instrument, bool) {
+	i, ok := c.instruments[symbol]
+	return i, ok
+}
+
+// Resolve finds the instrument a broker's symbol refers to. Exact aliases of
+// the broker win, then global aliases and canonical symbols, then the
+// longest name the symbol starts with (EURUSDpro, XAUUSD.m) and finally, for
+// names of at least six characters, one it ends with (mEURUSD).
+func (c *Catalogue) Resolve(broker, symbol string) (Instrument, bool) {
+	key := CleanSymbol(symbol)
+	if key == "" {
+		return Instrument{}, false
+	}
+	broker = NormalizeBroker(broker)
+
+	if s, ok := c.aliases[aliasKey{broker, key}]; ok && broker != "" {
+		return c.instruments[s], true
+	}
+	if s, ok := c.aliases[aliasKey{"", key}]; ok {
+		return c.instruments[s], true
+	}
+	if i, ok := c.instruments[key]; ok {
+		return i, true
+	}
+
+	for _, n := range c.names {
+		if (n.broker == "" || n.broker == broker) && strings.HasPrefix(key, n.key) {
+			return c.instruments[n.symbol], true
+		}
+	}
+	for _, n := range c.names {
+		if (n.broker == "" || n.broker == broker) && len(n.key) >= 6 && strings.HasSuffix(key, n.key) {
+			return c.instruments[n.symbol], true
+		}
+	}
+	return Instrument{}, false
+}
+
+// CleanSymbol upper-cases a raw symbol and drops everything but letters and
+// digits, the form aliases are stored in.
+func CleanSymbol(symbol string) string {
+	var b strings.Builder
+	b.Grow(len(symbol))
+	for _, r := range symbol {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// NormalizeBroker makes broker names from trading accounts comparable.
+func NormalizeBroker(broker string) string {
+	return strings.ToLower(strings.TrimSpace(broker))
+}
@@ -0,0 +1,45 @@
+package instrument
+
+import "time"
+
+type InstrumentResponse struct {
+	Symbol        string  `json:"symbol"`
+	AssetClass    string  `json:"assetClass"`
+	ContractSize  float64 `json:"contractSize"`
+	PipSize       float64 `json:"pipSize"`
+	Digits        int32   `json:"digits"`
+	QuoteCurrency string  `json:"quoteCurrency"`
+}
+
+type AliasRequest struct {
+	Broker string `json:"broker"`
+	Alias  string `json:"alias"`
+	Symbol string `json:"symbol"`
+}
+
+type AliasResponse struct {
+	Broker    string    `json:"broker"`
+	Alias     string    `json:"alias"`
+	Symbol    string    `json:"symbol"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ToResponse(i Instrument) InstrumentResponse {
+	return InstrumentResponse{
+		Symbol:        i.Symbol,
+		AssetClass:    string(i.AssetClass),
+		ContractSize:  i.ContractSize,
+		PipSize:       i.PipSize,
+		Digits:        i.Digits,
+		QuoteCurrency: i.QuoteCurrency,
+	}
+}
+
+func ToAliasResponse(a Alias) AliasResponse {
+	return AliasResponse{
+		Broker:    a.Broker,
+		Alias:     a.Alias,
+		Symbol:    a.Symbol,
+		CreatedAt: a.CreatedAt,
+	}
+}
@@ -0,0 +1,9 @@
+package instrument
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("instrument not found")
+	ErrAliasNotFound = errors.New("alias not found")
+	ErrInvalidAlias  = errors.New("invalid alias")
+)
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+)
+
+type errorMapping struct {
+	status  int
+	message string
+}
+
+var errorMap = map[error]errorMapping{
+	instrument.ErrNotFound:      {http.StatusNotFound, "Instrument not found"},
+	instrument.ErrAliasNotFound: {http.StatusNotFound, "Alias not found"},
+	instrument.ErrInvalidAlias:  {http.StatusBadRequest, "Alias must have 1 to 32 letters or digits and name an instrument"},
+}
+
+// writeDomainError maps domain errors to HTTP responses
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	for domainErr, mapping := range errorMap {
+		if errors.Is(err, domainErr) {
+			httputil.WriteError(w, r, mapping.status, mapping.message, err)
+			return
+		}
+	}
+
+	// Unknown error
+	httputil.WriteInternalError(w, r, err)
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/auth"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service *instrument.Service
+}
+
+func NewHandler(service *instrument.Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.With(auth.AuthenticationMiddleware).Get("/instruments", h.listInstruments)
+
+	r.Route("/admin/instruments/aliases", func(r chi.Router) {
+		r.Use(auth.AuthenticationMiddleware)
+		r.Use(auth.RequireAdmin)
+
+		r.Get("/", h.listAliases)
+		r.Put("/", h.setAlias)
+		r.Delete("/", h.deleteAlias)
+	})
+}
+
+func (h *Handler) listInstruments(w http.ResponseWriter, r *http.Request) {
+	instruments, err := h.service.List(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	out := make([]instrument.InstrumentResponse, 0, len(instruments))
+	for _, i := range instruments {
+		out = append(out, instrument.ToResponse(i))
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) listAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.service.ListAliases(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	out := make([]instrument.AliasResponse, 0, len(aliases))
+	for _, a := range aliases {
+		out = append(out, instrument.ToAliasResponse(a))
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) setAlias(w http.ResponseWriter, r *http.Request) {
+	var req instrument.AliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteClientError(w, r, "Invalid JSON body", err)
+		return
+	}
+
+	a, err := h.service.SetAlias(r.Context(), instrument.Alias{
+		Broker: req.Broker,
+		Alias:  req.Alias,
+		Symbol: req.Symbol,
+	})
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, instrument.ToAliasResponse(a))
+}
+
+// deleteAlias takes the broker and alias as query parameters; broker names
+// are free text and may not fit in a path segment.
+func (h *Handler) deleteAlias(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if err := h.service.DeleteAlias(r.Context(), q.Get("broker"), q.Get("alias")); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
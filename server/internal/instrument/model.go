@@ -0,0 +1,59 @@
+package instrument
+
+import (
+	"strings"
+	"time"
+)
+
+type AssetClass string
+
+const (
+	AssetForex  AssetClass = "forex"
+	AssetMetal  AssetClass = "metal"
+	AssetIndex  AssetClass = "index"
+	AssetEnergy AssetClass = "energy"
+	AssetCrypto AssetClass = "crypto"
+)
+
+// Instrument is a canonical symbol with its contract specification. Brokers
+// quote the same instrument under different names; see Alias.
+type Instrument struct {
+	Symbol     string
+	AssetClass AssetClass
+	// ContractSize is the number of units in one lot.
+	ContractSize float64
+	PipSize      float64
+	Digits       int32
+	// QuoteCurrency is the currency prices, and so pip values, are in.
+	QuoteCurrency string
+}
+
+// Pips returns how many pips a trade gained, negative for a loss.
+func (i Instrument) Pips(side string, openPrice, closePrice float64) float64 {
+	move := closePrice - openPrice
+	if strings.EqualFold(side, "sell") {
+		move = -move
+	}
+	return move / i.PipSize
+}
+
+// PipValue returns what one pip is worth, in the quote currency, for the
+// given number of lots.
+func (i Instrument) PipValue(lots float64) float64 {
+	return i.PipSize * i.ContractSize * lots
+}
+
+// Alias maps a broker's name for an instrument to the canonical symbol. An
+// empty Broker applies to every broker.
+type Alias struct {
+	Broker    string
+	Alias     string
+	Symbol    string
+	CreatedAt time.Time
+}
+
+// BrokerSymbol is a raw symbol as stored for trades of a broker's accounts.
+type BrokerSymbol struct {
+	Broker string
+	Symbol string
+}
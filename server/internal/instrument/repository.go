@@ -0,0 +1,128 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/filipcvejic/trading_tournament/db"
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	ListInstruments(ctx context.Context) ([]Instrument, error)
+	ListAliases(ctx context.Context) ([]Alias, error)
+	UpsertAlias(ctx context.Context, a Alias) (Alias, error)
+	DeleteAlias(ctx context.Context, broker, alias string) error
+	ListTradeSymbols(ctx context.Context) ([]BrokerSymbol, error)
+	// TagTrades sets the instrument of every trade of the broker's accounts
+	// with the given raw symbol. An empty instrument clears it.
+	TagTrades(ctx context.Context, s BrokerSymbol, instrument string) (int64, error)
+}
+
+type PostgresRepository struct {
+	db *db.DB
+}
+
+func NewPostgresRepository(database *db.DB) *PostgresRepository {
+	return &PostgresRepository{db: database}
+}
+
+func (r *PostgresRepository) ListInstruments(ctx context.Context) ([]Instrument, error) {
+	rows, err := r.db.Query.ListInstruments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list instruments: %w", err)
+	}
+
+	instruments := make([]Instrument, 0, len(rows))
+	for _, row := range rows {
+		instruments = append(instruments, Instrument{
+			Symbol:        row.Symbol,
+			AssetClass:    AssetClass(row.AssetClass),
+			ContractSize:  row.ContractSize,
+			PipSize:       row.PipSize,
+			Digits:        row.Digits,
+			QuoteCurrency: row.QuoteCurrency,
+		})
+	}
+	return instruments, nil
+}
+
+func (r *PostgresRepository) ListAliases(ctx context.Context) ([]Alias, error) {
+	rows, err := r.db.Query.ListInstrumentAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list instrument aliases: %w", err)
+	}
+
+	aliases := make([]Alias, 0, len(rows))
+	for _, row := range rows {
+		aliases = append(aliases, aliasFromDB(row))
+	}
+	return aliases, nil
+}
+
+func (r *PostgresRepository) UpsertAlias(ctx context.Context, a Alias) (Alias, error) {
+	row, err := r.db.Query.UpsertInstrumentAlias(ctx, sqlc.UpsertInstrumentAliasParams{
+		Broker: a.Broker,
+		Alias:  a.Alias,
+		Symbol: a.Symbol,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return Alias{}, ErrNotFound
+		}
+		return Alias{}, fmt.Errorf("upsert instrument alias: %w", err)
+	}
+	return aliasFromDB(row), nil
+}
+
+func (r *PostgresRepository) DeleteAlias(ctx context.Context, broker, alias string) error {
+	n, err := r.db.Query.DeleteInstrumentAlias(ctx, sqlc.DeleteInstrumentAliasParams{
+		Broker: broker,
+		Alias:  alias,
+	})
+	if err != nil {
+		return fmt.Errorf("delete instrument alias: %w", err)
+	}
+	if n == 0 {
+		return ErrAliasNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListTradeSymbols(ctx context.Context) ([]BrokerSymbol, error) {
+	rows, err := r.db.Query.ListTradeSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list trade symbols: %w", err)
+	}
+
+	symbols := make([]BrokerSymbol, 0, len(rows))
+	for _, row := range rows {
+		symbols = append(symbols, BrokerSymbol{Broker: row.Broker, Symbol: row.Symbol})
+	}
+	return symbols, nil
+}
+
+func (r *PostgresRepository) TagTrades(ctx context.Context, s BrokerSymbol, instrument string) (int64, error) {
+	n, err := r.db.Query.TagTradeInstruments(ctx, sqlc.TagTradeInstrumentsParams{
+		Instrument: pgtype.Text{String: instrument, Valid: instrument != ""},
+		Broker:     s.Broker,
+		Symbol:     s.Symbol,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("tag trades: %w", err)
+	}
+	return n, nil
+}
+
+func aliasFromDB(row sqlc.InstrumentAlias) Alias {
+	return Alias{
+		Broker:    row.Broker,
+		Alias:     row.Alias,
+		Symbol:    row.Symbol,
+		CreatedAt: row.CreatedAt,
+	}
+}
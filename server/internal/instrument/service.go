@@ -0,0 +1,116 @@
+package instrument
+
+import (
+	"context"
+	"sync"
+)
+
+// Service keeps the catalogue in memory. It is loaded on first use and
+// reloaded whenever an alias changes.
+type Service struct {
+	repo Repository
+
+	mu        sync.RWMutex
+	catalogue *Catalogue
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Catalogue(ctx context.Context) (*Catalogue, error) {
+	s.mu.RLock()
+	c := s.catalogue
+	s.mu.RUnlock()
+	if c != nil {
+		return c, nil
+	}
+	return s.reload(ctx)
+}
+
+func (s *Service) reload(ctx context.Context) (*Catalogue, error) {
+	instruments, err := s.repo.ListInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.repo.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewCatalogue(instruments, aliases)
+	s.mu.Lock()
+	s.catalogue = c
+	s.mu.Unlock()
+	return c, nil
+}
+
+// Resolve finds the instrument a broker's symbol refers to; see
+// Catalogue.Resolve.
+func (s *Service) Resolve(ctx context.Context, broker, symbol string) (Instrument, bool, error) {
+	c, err := s.Catalogue(ctx)
+	if err != nil {
+		return Instrument{}, false, err
+	}
+	i, ok := c.Resolve(broker, symbol)
+	return i, ok, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]Instrument, error) {
+	return s.repo.ListInstruments(ctx)
+}
+
+func (s *Service) ListAliases(ctx context.Context) ([]Alias, error) {
+	return s.repo.ListAliases(ctx)
+}
+
+// SetAlias maps a broker's symbol to an instrument. Existing trades keep
+// their instrument until RetagTrades runs.
+func (s *Service) SetAlias(ctx context.Context, a Alias) (Alias, error) {
+	a.Broker = NormalizeBroker(a.Broker)
+	a.Alias = CleanSymbol(a.Alias)
+	if a.Alias == "" || len(a.Alias) > 32 || a.Symbol == "" {
+		return Alias{}, ErrInvalidAlias
+	}
+
+	a, err := s.repo.UpsertAlias(ctx, a)
+	if err != nil {
+		return Alias{}, err
+	}
+	if _, err := s.reload(ctx); err != nil {
+		return Alias{}, err
+	}
+	return a, nil
+}
+
+func (s *Service) DeleteAlias(ctx context.Context, broker, alias string) error {
+	if err := s.repo.DeleteAlias(ctx, NormalizeBroker(broker), CleanSymbol(alias)); err != nil {
+		return err
+	}
+	_, err := s.reload(ctx)
+	return err
+}
+
+// RetagTrades resolves the symbol of every stored trade again, for instance
+// after aliases were added, and returns how many trades changed instrument.
+func (s *Service) RetagTrades(ctx context.Context) (int64, error) {
+	c, err := s.reload(ctx)
+	if err != nil {
+		return 0, err
+	}
+	symbols, err := s.repo.ListTradeSymbols(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, bs := range symbols {
+		i, _ := c.Resolve(bs.Broker, bs.Symbol)
+		n, err := s.repo.TagTrades(ctx, bs, i.Symbol)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryHub fans payloads out to subscribers within this process only.
+type MemoryHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan string]struct{}
+}
+
+func NewMemoryHub() *MemoryHub {
+	return &MemoryHub{subscribers: make(map[string]map[chan string]struct{})}
+}
+
+func (h *MemoryHub) Publish(_ context.Context, channel, payload string) error {
+	h.deliver(channel, payload)
+	return nil
+}
+
+func (h *MemoryHub) Subscribe(channel string) (<-chan string, func()) {
+	ch := make(chan string, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[channel] == nil {
+		h.subscribers[channel] = make(map[chan string]struct{})
+	}
+	h.subscribers[channel][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[channel], ch)
+			if len(h.subscribers[channel]) == 0 {
+				delete(h.subscribers, channel)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
+
+// deliver never blocks: a subscriber that is not keeping up misses payloads
+// rather than stalling the publisher.
+func (h *MemoryHub) deliver(channel, payload string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[channel] {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresHub publishes through NOTIFY and relays LISTEN notifications to
+// local subscribers, so every API instance sees every publish.
+type PostgresHub struct {
+	pool     *pgxpool.Pool
+	channels []string
+	local    *MemoryHub
+}
+
+// NewPostgresHub creates a hub that listens on the given channels once Run is
+// started. Publishing to other channels still notifies Postgres, but nothing
+// in this process will receive it.
+func NewPostgresHub(pool *pgxpool.Pool, channels ...string) *PostgresHub {
+	return &PostgresHub{pool: pool, channels: channels, local: NewMemoryHub()}
+}
+
+func (h *PostgresHub) Publish(ctx context.Context, channel, payload string) error {
+	if _, err := h.pool.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fmt.Errorf("notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+func (h *PostgresHub) Subscribe(channel string) (<-chan string, func()) {
+	return h.local.Subscribe(channel)
+}
+
+// Run holds a dedicated connection listening on the hub's channels until ctx
+// is done, reconnecting after failures.
+func (h *PostgresHub) Run(ctx context.Context) {
+	for {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("PUBSUB: listen: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (h *PostgresHub) listen(ctx context.Context) error {
+	pooled, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// The connection carries LISTEN state, so it must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	for _, channel := range h.channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("listen %s: %w", channel, err)
+		}
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		h.local.deliver(n.Channel, n.Payload)
+	}
+}
@@ -0,0 +1,17 @@
+package pubsub
+
+import "context"
+
+// Hub delivers small notifications between publishers and subscribers of a
+// channel. Payloads are plain strings so a hub can be backed by Postgres
+// NOTIFY, which caps them at 8000 bytes.
+type Hub interface {
+	Publish(ctx context.Context, channel, payload string) error
+	// Subscribe returns a stream of payloads published on channel and a
+	// function that cancels the subscription and closes the stream.
+	Subscribe(channel string) (<-chan string, func())
+}
+
+// subscriberBuffer is how many undelivered payloads a subscriber may queue
+// before new ones are dropped for it.
+const subscriberBuffer = 64
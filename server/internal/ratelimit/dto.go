@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LockoutResponse struct {
+	ID          int64      `json:"id"`
+	Scope       string     `json:"scope"`
+	Key         string     `json:"key"`
+	Failures    int        `json:"failures"`
+	LockedUntil time.Time  `json:"lockedUntil"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ReleasedBy  *uuid.UUID `json:"releasedBy"`
+	ReleasedAt  *time.Time `json:"releasedAt"`
+}
+
+func ToResponse(l Lockout) LockoutResponse {
+	return LockoutResponse{
+		ID:          l.ID,
+		Scope:       l.Scope,
+		Key:         l.Key,
+		Failures:    l.Failures,
+		LockedUntil: l.LockedUntil,
+		CreatedAt:   l.CreatedAt,
+		ReleasedBy:  l.ReleasedBy,
+		ReleasedAt:  l.ReleasedAt,
+	}
+}
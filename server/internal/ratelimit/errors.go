@@ -0,0 +1,9 @@
+package ratelimit
+
+import "errors"
+
+var (
+	ErrLocked          = errors.New("too many attempts")
+	ErrLockoutNotFound = errors.New("lockout not found")
+	ErrInvalidLimit    = errors.New("invalid limit")
+)
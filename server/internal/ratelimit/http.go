@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+)
+
+// Middleware counts every request against the client's IP address and
+// rejects it while the address is blocked.
+func Middleware(l *Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := l.Hit(r.Context(), IPKey(r)); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IPKey is the rate limit key of the client's address. It relies on
+// TrustedProxies.RealIP having replaced RemoteAddr behind a proxy.
+func IPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// TrustedProxies lists the networks of the reverse proxies in front of the
+// API. Only requests arriving from one of them may name the client in a
+// forwarded header; anyone else could send a new address with every request
+// and get a fresh rate limit bucket each time.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses a comma separated list of CIDRs or single
+// addresses, e.g. "127.0.0.1,10.0.0.0/8". An empty list trusts nobody.
+func ParseTrustedProxies(s string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("parse trusted proxy %q: %w", field, err)
+			}
+			addr = addr.Unmap()
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", field, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func (t TrustedProxies) contains(addr netip.Addr) bool {
+	for _, p := range t {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RealIP replaces RemoteAddr with the client's address when the request
+// arrived from a trusted proxy. The client is the right-most X-Forwarded-For
+// entry that is not a trusted proxy itself, or X-Real-IP when there is no
+// X-Forwarded-For; entries left of it were written by the client and are
+// ignored. Requests from anywhere else keep their socket address whatever
+// headers they carry.
+func (t TrustedProxies) RealIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr, ok := t.clientAddr(r); ok {
+			r.RemoteAddr = addr.String()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t TrustedProxies) clientAddr(r *http.Request) (netip.Addr, bool) {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	client := peer.Addr().Unmap()
+	if !t.contains(client) {
+		return netip.Addr{}, false
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0 && t.contains(client); i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			client = hop.Unmap()
+		}
+		return client, true
+	}
+
+	if hop, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return hop.Unmap(), true
+	}
+	return client, true
+}
+
+// WriteError answers 429 with Retry-After for a *LockedError and 500 for
+// anything else.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		httputil.WriteInternalError(w, r, err)
+		return
+	}
+
+	wait := max(time.Until(locked.Until), time.Second)
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	httputil.WriteError(w, r, http.StatusTooManyRequests,
+		fmt.Sprintf("Too many attempts, try again in %s", formatWait(seconds)), err)
+}
+
+func formatWait(seconds int) string {
+	switch {
+	case seconds < 60:
+		if seconds == 1 {
+			return "1 second"
+		}
+		return fmt.Sprintf("%d seconds", seconds)
+	case seconds < 3600:
+		if m := (seconds + 59) / 60; m != 1 {
+			return fmt.Sprintf("%d minutes", m)
+		}
+		return "1 minute"
+	default:
+		if h := (seconds + 3599) / 3600; h != 1 {
+			return fmt.Sprintf("%d hours", h)
+		}
+		return "1 hour"
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/ratelimit"
+)
+
+type errorMapping struct {
+	status  int
+	message string
+}
+
+var errorMap = map[error]errorMapping{
+	ratelimit.ErrLockoutNotFound: {http.StatusNotFound, "Lockout not found or already released"},
+	ratelimit.ErrInvalidLimit:    {http.StatusBadRequest, "Limit must be between 1 and 500"},
+}
+
+// writeDomainError maps domain errors to HTTP responses
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	for domainErr, mapping := range errorMap {
+		if errors.Is(err, domainErr) {
+			httputil.WriteError(w, r, mapping.status, mapping.message, err)
+			return
+		}
+	}
+
+	httputil.WriteInternalError(w, r, err)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/filipcvejic/trading_tournament/internal/auth"
+	"github.com/filipcvejic/trading_tournament/internal/httputil"
+	"github.com/filipcvejic/trading_tournament/internal/ratelimit"
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service *ratelimit.Service
+}
+
+func NewHandler(service *ratelimit.Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/lockouts", func(r chi.Router) {
+		r.Use(auth.AuthenticationMiddleware)
+		r.Use(auth.RequireAdmin)
+
+		r.Get("/", h.listLockouts)
+		r.Post("/{lockoutID}/release", h.releaseLockout)
+	})
+}
+
+// listLockouts accepts scope (e.g. login), active=true and limit.
+func (h *Handler) listLockouts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := ratelimit.LockoutFilter{
+		Scope:      query.Get("scope"),
+		ActiveOnly: query.Get("active") == "true",
+	}
+
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			httputil.WriteClientError(w, r, "Invalid limit parameter", err)
+			return
+		}
+		filter.Limit = n
+	}
+
+	lockouts, err := h.service.ListLockouts(r.Context(), filter)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	out := make([]ratelimit.LockoutResponse, 0, len(lockouts))
+	for _, l := range lockouts {
+		out = append(out, ratelimit.ToResponse(l))
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) releaseLockout(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "lockoutID"), 10, 64)
+	if err != nil {
+		httputil.WriteClientError(w, r, "Invalid lockout ID", err)
+		return
+	}
+
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		httputil.WriteUnauthorized(w, r)
+		return
+	}
+
+	lockout, err := h.service.ReleaseLockout(r.Context(), id, userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ratelimit.ToResponse(lockout))
+}
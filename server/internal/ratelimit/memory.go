@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps counts in this process only. Each API instance limits on
+// its own and the counts are lost on restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]State
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]State)}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.entries[key], nil
+}
+
+func (s *MemoryStore) AddFailure(_ context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.entries[key]
+	if st.LastFailureAt.Before(at.Add(-window)) {
+		st.Failures = 0
+	}
+	st.Failures++
+	st.LastFailureAt = at
+	s.entries[key] = st
+
+	return st.Failures, nil
+}
+
+func (s *MemoryStore) Block(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.entries[key]; ok {
+		st.BlockedUntil = until
+		s.entries[key] = st
+	}
+	return nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) Prune(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, st := range s.entries {
+		if st.LastFailureAt.Before(before) && st.BlockedUntil.Before(before) {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAddFailure(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	const window = time.Hour
+
+	tests := []struct {
+		name string
+		// gaps between consecutive failures
+		gaps []time.Duration
+		want int
+	}{
+		{name: "first failure", gaps: []time.Duration{0}, want: 1},
+		{name: "failures in a row", gaps: []time.Duration{0, time.Minute, time.Minute}, want: 3},
+		{name: "last failure at the end of the window", gaps: []time.Duration{0, window}, want: 2},
+		{name: "last failure past the window", gaps: []time.Duration{0, window + time.Nanosecond}, want: 1},
+		// The window runs from the latest failure, not the first.
+		{name: "window slides", gaps: []time.Duration{0, 50 * time.Minute, 50 * time.Minute}, want: 3},
+		{name: "count starts over", gaps: []time.Duration{0, time.Minute, 2 * window, time.Minute}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemoryStore()
+			ctx := context.Background()
+
+			at := start
+			var n int
+			for _, gap := range tt.gaps {
+				at = at.Add(gap)
+				var err error
+				if n, err = s.AddFailure(ctx, "login:trader", at, window); err != nil {
+					t.Fatalf("AddFailure: %v", err)
+				}
+			}
+			if n != tt.want {
+				t.Errorf("failures = %d, want %d", n, tt.want)
+			}
+
+			st, err := s.Get(ctx, "login:trader")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if st.Failures != tt.want || !st.LastFailureAt.Equal(at) {
+				t.Errorf("state = %+v, want %d failures, the last at %v", st, tt.want, at)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreBlockAndReset(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	at := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// Only keys with failures get blocked.
+	if err := s.Block(ctx, "login:unknown", at.Add(time.Hour)); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if st, _ := s.Get(ctx, "login:unknown"); st != (State{}) {
+		t.Errorf("unknown key state = %+v, want zero", st)
+	}
+
+	if _, err := s.AddFailure(ctx, "login:trader", at, time.Hour); err != nil {
+		t.Fatalf("AddFailure: %v", err)
+	}
+	if err := s.Block(ctx, "login:trader", at.Add(time.Hour)); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if st, _ := s.Get(ctx, "login:trader"); !st.BlockedUntil.Equal(at.Add(time.Hour)) {
+		t.Errorf("blocked until %v, want %v", st.BlockedUntil, at.Add(time.Hour))
+	}
+
+	if err := s.Reset(ctx, "login:trader"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if st, _ := s.Get(ctx, "login:trader"); st != (State{}) {
+		t.Errorf("state after reset = %+v, want zero", st)
+	}
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+
+	fail := func(key string, at time.Time) {
+		t.Helper()
+		if _, err := s.AddFailure(ctx, key, at, time.Hour); err != nil {
+			t.Fatalf("AddFailure: %v", err)
+		}
+	}
+
+	fail("login:stale", now.Add(-2*time.Hour))
+	fail("login:recent", now.Add(-time.Minute))
+	// An old failure, but still locked out.
+	fail("login:locked", now.Add(-2*time.Hour))
+	if err := s.Block(ctx, "login:locked", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+
+	n, err := s.Prune(ctx, before)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("pruned %d keys, want 1", n)
+	}
+	for key, want := range map[string]int{"login:stale": 0, "login:recent": 1, "login:locked": 1} {
+		if st, _ := s.Get(ctx, key); st.Failures != want {
+			t.Errorf("%s: %d failures, want %d", key, st.Failures, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/db"
+	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostgresStore keeps counts in Postgres, shared by every API instance.
+type PostgresStore struct {
+	db *db.DB
+}
+
+func NewPostgresStore(database *db.DB) *PostgresStore {
+	return &PostgresStore{db: database}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (State, error) {
+	row, err := s.db.Query.GetRateLimit(ctx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return State{}, nil
+		}
+		return State{}, err
+	}
+
+	st := State{
+		Failures:      int(row.Failures),
+		LastFailureAt: row.LastFailureAt,
+	}
+	if row.BlockedUntil != nil {
+		st.BlockedUntil = *row.BlockedUntil
+	}
+	return st, nil
+}
+
+func (s *PostgresStore) AddFailure(ctx context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	n, err := s.db.Query.AddRateLimitFailure(ctx, sqlc.AddRateLimitFailureParams{
+		Key:         key,
+		FailedAt:    at,
+		WindowStart: at.Add(-window),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (s *PostgresStore) Block(ctx context.Context, key string, until time.Time) error {
+	return s.db.Query.BlockRateLimit(ctx, sqlc.BlockRateLimitParams{
+		Key:          key,
+		BlockedUntil: &until,
+	})
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.db.Query.DeleteRateLimit(ctx, key)
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	return s.db.Query.DeleteStaleRateLimits(ctx, before)
+}
+
+type PostgresLockoutLog struct {
+	db *db.DB
+}
+
+func NewPostgresLockoutLog(database *db.DB) *PostgresLockoutLog {
+	return &PostgresLockoutLog{db: database}
+}
+
+func (l *PostgresLockoutLog) Record(ctx context.Context, lockout Lockout) error {
+	return l.db.Query.CreateRateLimitLockout(ctx, sqlc.CreateRateLimitLockoutParams{
+		Scope:       lockout.Scope,
+		Key:         lockout.Key,
+		Failures:    int32(lockout.Failures),
+		LockedUntil: lockout.LockedUntil,
+	})
+}
+
+func (l *PostgresLockoutLog) List(ctx context.Context, filter LockoutFilter) ([]Lockout, error) {
+	rows, err := l.db.Query.ListRateLimitLockouts(ctx, sqlc.ListRateLimitLockoutsParams{
+		Scope:      pgtype.Text{String: filter.Scope, Valid: filter.Scope != ""},
+		ActiveOnly: filter.ActiveOnly,
+		RowLimit:   int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list lockouts: %w", err)
+	}
+
+	out := make([]Lockout, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, lockoutFromDB(row))
+	}
+	return out, nil
+}
+
+func (l *PostgresLockoutLog) Release(ctx context.Context, id int64, releasedBy uuid.UUID) (Lockout, error) {
+	row, err := l.db.Query.ReleaseRateLimitLockout(ctx, sqlc.ReleaseRateLimitLockoutParams{
+		ReleasedBy: &releasedBy,
+		ID:         id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Lockout{}, ErrLockoutNotFound
+		}
+		return Lockout{}, fmt.Errorf("release lockout: %w", err)
+	}
+	return lockoutFromDB(row), nil
+}
+
+func lockoutFromDB(row sqlc.RateLimitLockout) Lockout {
+	return Lockout{
+		ID:          row.ID,
+		Scope:       row.Scope,
+		Key:         row.Key,
+		Failures:    int(row.Failures),
+		LockedUntil: row.LockedUntil,
+		CreatedAt:   row.CreatedAt,
+		ReleasedBy:  row.ReleasedBy,
+		ReleasedAt:  row.ReleasedAt,
+	}
+}
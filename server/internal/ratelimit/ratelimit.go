@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policy decides how long a key is blocked after each failure. A few
+// failures are free; after that every failure blocks the key for twice as
+// long as the one before, and from LockoutAfter on the key is locked out,
+// which is recorded for admins to review.
+type Policy struct {
+	// FreeFailures is how many failures in a row go unpunished.
+	FreeFailures int
+	// BaseDelay is the block after the first failure past FreeFailures.
+	BaseDelay time.Duration
+	// LockoutAfter is the failure count that locks the key out.
+	LockoutAfter int
+	// Lockout is how long the first lockout lasts; each further failure
+	// doubles it, up to MaxLockout.
+	Lockout    time.Duration
+	MaxLockout time.Duration
+	// Window is how long failures are remembered after the last one.
+	Window time.Duration
+}
+
+// maxDoublings keeps backoff from overflowing time.Duration.
+const maxDoublings = 30
+
+// block returns how long a key is blocked after its n-th failure, and
+// whether that block is a lockout.
+func (p Policy) block(failures int) (time.Duration, bool) {
+	switch {
+	case failures >= p.LockoutAfter:
+		return backoff(p.Lockout, failures-p.LockoutAfter, p.MaxLockout), true
+	case failures > p.FreeFailures:
+		return backoff(p.BaseDelay, failures-p.FreeFailures-1, p.Lockout), false
+	}
+	return 0, false
+}
+
+// backoff doubles base the given number of times, capped at max unless max
+// is zero.
+func backoff(base time.Duration, doublings int, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < doublings && i < maxDoublings; i++ {
+		d *= 2
+		if max > 0 && d >= max {
+			return max
+		}
+	}
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
+
+// State is what a store keeps per key.
+type State struct {
+	Failures      int
+	LastFailureAt time.Time
+	BlockedUntil  time.Time
+}
+
+// Store keeps failure counts. Keys arrive already prefixed with the
+// limiter's scope.
+type Store interface {
+	// Get returns the zero State for keys it has never seen.
+	Get(ctx context.Context, key string) (State, error)
+	// AddFailure counts a failure at at and returns the new count, starting
+	// over from one when the previous failure is more than window old.
+	AddFailure(ctx context.Context, key string, at time.Time, window time.Duration) (int, error)
+	Block(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// Prune forgets keys with no failure and no block since before.
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LockedError is returned while a key is blocked.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrLocked, e.Until.Format(time.RFC3339))
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Limiter applies a policy to the keys of one scope, e.g. "login", in a
+// store it may share with other limiters.
+type Limiter struct {
+	scope    string
+	policy   Policy
+	store    Store
+	lockouts LockoutLog
+	now      func() time.Time
+}
+
+// Allow returns a *LockedError when any of the keys is blocked, naming the
+// latest time they are all free again.
+func (l *Limiter) Allow(ctx context.Context, keys ...string) error {
+	now := l.now()
+	var until time.Time
+
+	for _, key := range keys {
+		st, err := l.store.Get(ctx, l.scoped(key))
+		if err != nil {
+			return fmt.Errorf("get rate limit: %w", err)
+		}
+		if st.BlockedUntil.After(now) && st.BlockedUntil.After(until) {
+			until = st.BlockedUntil
+		}
+	}
+
+	if !until.IsZero() {
+		return &LockedError{Until: until}
+	}
+	return nil
+}
+
+// Fail counts a failure against every key and blocks the ones that are
+// past their free failures.
+func (l *Limiter) Fail(ctx context.Context, keys ...string) error {
+	now := l.now()
+
+	for _, key := range keys {
+		n, err := l.store.AddFailure(ctx, l.scoped(key), now, l.policy.Window)
+		if err != nil {
+			return fmt.Errorf("add rate limit failure: %w", err)
+		}
+
+		d, lockout := l.policy.block(n)
+		if d <= 0 {
+			continue
+		}
+		until := now.Add(d)
+		if err := l.store.Block(ctx, l.scoped(key), until); err != nil {
+			return fmt.Errorf("block rate limit: %w", err)
+		}
+		if lockout {
+			if err := l.lockouts.Record(ctx, Lockout{
+				Scope:       l.scope,
+				Key:         key,
+				Failures:    n,
+				LockedUntil: until,
+			}); err != nil {
+				return fmt.Errorf("record lockout: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Hit is Allow followed by Fail, for endpoints where every request counts,
+// successful or not.
+func (l *Limiter) Hit(ctx context.Context, keys ...string) error {
+	if err := l.Allow(ctx, keys...); err != nil {
+		return err
+	}
+	return l.Fail(ctx, keys...)
+}
+
+// Reset forgets the failures of the keys, e.g. after a successful login.
+func (l *Limiter) Reset(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := l.store.Reset(ctx, l.scoped(key)); err != nil {
+			return fmt.Errorf("reset rate limit: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *Limiter) scoped(key string) string {
+	return scopedKey(l.scope, key)
+}
+
+func scopedKey(scope, key string) string {
+	return scope + ":" + key
+}
@@ -0,0 +1,319 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// loginPolicy is the policy the API puts on logins.
+var loginPolicy = Policy{
+	FreeFailures: 3,
+	BaseDelay:    time.Second,
+	LockoutAfter: 10,
+	Lockout:      15 * time.Minute,
+	MaxLockout:   24 * time.Hour,
+	Window:       24 * time.Hour,
+}
+
+func TestPolicyBlock(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      Policy
+		failures    int
+		want        time.Duration
+		wantLockout bool
+	}{
+		{name: "no failures", policy: loginPolicy, failures: 0},
+		{name: "first free failure", policy: loginPolicy, failures: 1},
+		{name: "last free failure", policy: loginPolicy, failures: 3},
+		{name: "first delay", policy: loginPolicy, failures: 4, want: time.Second},
+		{name: "delay doubles", policy: loginPolicy, failures: 5, want: 2 * time.Second},
+		{name: "last delay", policy: loginPolicy, failures: 9, want: 32 * time.Second},
+		{name: "first lockout", policy: loginPolicy, failures: 10, want: 15 * time.Minute, wantLockout: true},
+		{name: "lockout doubles", policy: loginPolicy, failures: 11, want: 30 * time.Minute, wantLockout: true},
+		{name: "last lockout under the cap", policy: loginPolicy, failures: 16, want: 16 * time.Hour, wantLockout: true},
+		{name: "lockout capped", policy: loginPolicy, failures: 17, want: 24 * time.Hour, wantLockout: true},
+		{name: "lockout stays capped", policy: loginPolicy, failures: 1000, want: 24 * time.Hour, wantLockout: true},
+		{
+			name:     "delay capped at the lockout",
+			policy:   Policy{BaseDelay: time.Minute, LockoutAfter: 30, Lockout: 15 * time.Minute},
+			failures: 5,
+			want:     15 * time.Minute,
+		},
+		{
+			name:     "delay stays capped at the lockout",
+			policy:   Policy{BaseDelay: time.Minute, LockoutAfter: 30, Lockout: 15 * time.Minute},
+			failures: 29,
+			want:     15 * time.Minute,
+		},
+		{
+			name:        "uncapped lockout stops doubling",
+			policy:      Policy{LockoutAfter: 1, Lockout: time.Second},
+			failures:    1000,
+			want:        time.Second << maxDoublings,
+			wantLockout: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lockout := tt.policy.block(tt.failures)
+			if got != tt.want || lockout != tt.wantLockout {
+				t.Errorf("block(%d) = (%v, %v), want (%v, %v)", tt.failures, got, lockout, tt.want, tt.wantLockout)
+			}
+		})
+	}
+}
+
+// testClock is a clock that only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// memoryLockoutLog records lockouts and nothing else.
+type memoryLockoutLog struct {
+	lockouts []Lockout
+}
+
+func (l *memoryLockoutLog) Record(ctx context.Context, lockout Lockout) error {
+	l.lockouts = append(l.lockouts, lockout)
+	return nil
+}
+
+func (l *memoryLockoutLog) List(ctx context.Context, filter LockoutFilter) ([]Lockout, error) {
+	return l.lockouts, nil
+}
+
+func (l *memoryLockoutLog) Release(ctx context.Context, id int64, releasedBy uuid.UUID) (Lockout, error) {
+	return Lockout{}, ErrLockoutNotFound
+}
+
+func newTestLimiter(policy Policy) (*Limiter, *testClock, *memoryLockoutLog) {
+	clock := &testClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	lockouts := &memoryLockoutLog{}
+	l := &Limiter{scope: "login", policy: policy, store: NewMemoryStore(), lockouts: lockouts, now: clock.Now}
+	return l, clock, lockouts
+}
+
+// lockedUntil returns when Allow says key is free again, or the zero time
+// if it is not blocked.
+func lockedUntil(t *testing.T, l *Limiter, keys ...string) time.Time {
+	t.Helper()
+
+	err := l.Allow(context.Background(), keys...)
+	if err == nil {
+		return time.Time{}
+	}
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("Allow: %v is not ErrLocked", err)
+	}
+	return locked.Until
+}
+
+func TestLimiterFail(t *testing.T) {
+	l, clock, lockouts := newTestLimiter(loginPolicy)
+	ctx := context.Background()
+
+	// Each failure is made as soon as the previous block ends.
+	tests := []struct {
+		failures    int
+		wantBlock   time.Duration
+		wantLockout bool
+	}{
+		{failures: 1},
+		{failures: 2},
+		{failures: 3},
+		{failures: 4, wantBlock: time.Second},
+		{failures: 5, wantBlock: 2 * time.Second},
+		{failures: 9, wantBlock: 32 * time.Second},
+		{failures: 10, wantBlock: 15 * time.Minute, wantLockout: true},
+		{failures: 11, wantBlock: 30 * time.Minute, wantLockout: true},
+		{failures: 17, wantBlock: 24 * time.Hour, wantLockout: true},
+		{failures: 18, wantBlock: 24 * time.Hour, wantLockout: true},
+	}
+
+	failures := 0
+	for _, tt := range tests {
+		for failures < tt.failures {
+			if until := lockedUntil(t, l, "trader"); !until.IsZero() {
+				clock.now = until
+			}
+			if err := l.Fail(ctx, "trader"); err != nil {
+				t.Fatalf("Fail: %v", err)
+			}
+			failures++
+		}
+
+		until := lockedUntil(t, l, "trader")
+		var want time.Time
+		if tt.wantBlock > 0 {
+			want = clock.Now().Add(tt.wantBlock)
+		}
+		if !until.Equal(want) {
+			t.Errorf("after %d failures: locked until %v, want %v", tt.failures, until, want)
+		}
+
+		recorded := len(lockouts.lockouts) > 0 && lockouts.lockouts[len(lockouts.lockouts)-1].Failures == tt.failures
+		if recorded != tt.wantLockout {
+			t.Errorf("after %d failures: lockout recorded = %v, want %v", tt.failures, recorded, tt.wantLockout)
+		}
+	}
+
+	if got, want := len(lockouts.lockouts), 18-loginPolicy.LockoutAfter+1; got != want {
+		t.Errorf("recorded %d lockouts, want %d", got, want)
+	}
+	last := lockouts.lockouts[len(lockouts.lockouts)-1]
+	if last.Scope != "login" || last.Key != "trader" || !last.LockedUntil.Equal(clock.Now().Add(24*time.Hour)) {
+		t.Errorf("last lockout = %+v", last)
+	}
+}
+
+func TestLimiterBlockEnds(t *testing.T) {
+	l, clock, _ := newTestLimiter(loginPolicy)
+	ctx := context.Background()
+
+	for range loginPolicy.FreeFailures + 1 {
+		if err := l.Fail(ctx, "trader"); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+	}
+
+	clock.Advance(time.Second - time.Nanosecond)
+	if lockedUntil(t, l, "trader").IsZero() {
+		t.Errorf("free before the block ends")
+	}
+	clock.Advance(time.Nanosecond)
+	if until := lockedUntil(t, l, "trader"); !until.IsZero() {
+		t.Errorf("still locked until %v when the block ends", until)
+	}
+}
+
+func TestLimiterWindow(t *testing.T) {
+	policy := Policy{FreeFailures: 2, BaseDelay: time.Minute, LockoutAfter: 10, Lockout: time.Hour, Window: time.Hour}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		gap       time.Duration
+		wantBlock bool
+	}{
+		{name: "within the window", gap: 30 * time.Minute, wantBlock: true},
+		{name: "at the end of the window", gap: time.Hour, wantBlock: true},
+		{name: "past the window", gap: time.Hour + time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock, _ := newTestLimiter(policy)
+
+			for range policy.FreeFailures {
+				if err := l.Fail(ctx, "trader"); err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+			}
+			clock.Advance(tt.gap)
+			if err := l.Fail(ctx, "trader"); err != nil {
+				t.Fatalf("Fail: %v", err)
+			}
+
+			if blocked := !lockedUntil(t, l, "trader").IsZero(); blocked != tt.wantBlock {
+				t.Errorf("blocked = %v, want %v", blocked, tt.wantBlock)
+			}
+		})
+	}
+}
+
+func TestLimiterKeys(t *testing.T) {
+	l, clock, _ := newTestLimiter(Policy{BaseDelay: time.Minute, LockoutAfter: 10, Lockout: time.Hour})
+	ctx := context.Background()
+
+	// The account fails twice, from two addresses.
+	if err := l.Fail(ctx, "trader", "203.0.113.7"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if err := l.Fail(ctx, "trader", "198.51.100.1"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+
+	if until, want := lockedUntil(t, l, "203.0.113.7"), clock.Now().Add(time.Minute); !until.Equal(want) {
+		t.Errorf("first address locked until %v, want %v", until, want)
+	}
+	// Allow names the latest of the blocks.
+	if until, want := lockedUntil(t, l, "203.0.113.7", "trader"), clock.Now().Add(2*time.Minute); !until.Equal(want) {
+		t.Errorf("account locked until %v, want %v", until, want)
+	}
+	if until := lockedUntil(t, l, "192.0.2.1"); !until.IsZero() {
+		t.Errorf("unrelated address locked until %v", until)
+	}
+
+	// Another scope in the same store counts on its own.
+	other := &Limiter{scope: "register", policy: l.policy, store: l.store, lockouts: l.lockouts, now: l.now}
+	if until := lockedUntil(t, other, "trader"); !until.IsZero() {
+		t.Errorf("other scope locked until %v", until)
+	}
+}
+
+func TestLimiterHit(t *testing.T) {
+	l, clock, _ := newTestLimiter(Policy{FreeFailures: 2, BaseDelay: time.Minute, LockoutAfter: 10, Lockout: time.Hour, Window: time.Hour})
+	ctx := context.Background()
+
+	for i := range 3 {
+		if err := l.Hit(ctx, "203.0.113.7"); err != nil {
+			t.Fatalf("hit %d: %v", i+1, err)
+		}
+	}
+	// The third hit used up the free ones.
+	err := l.Hit(ctx, "203.0.113.7")
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("hit 4: err = %v, want %v", err, ErrLocked)
+	}
+
+	// A refused hit does not count.
+	clock.Advance(time.Minute)
+	if err := l.Hit(ctx, "203.0.113.7"); err != nil {
+		t.Fatalf("hit after the block: %v", err)
+	}
+	if until, want := lockedUntil(t, l, "203.0.113.7"), clock.Now().Add(2*time.Minute); !until.Equal(want) {
+		t.Errorf("locked until %v, want %v", until, want)
+	}
+}
+
+func TestLimiterReset(t *testing.T) {
+	l, clock, _ := newTestLimiter(loginPolicy)
+	ctx := context.Background()
+
+	for range loginPolicy.FreeFailures + 1 {
+		if err := l.Fail(ctx, "trader"); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+	}
+	if err := l.Reset(ctx, "trader"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if until := lockedUntil(t, l, "trader"); !until.IsZero() {
+		t.Errorf("locked until %v after reset", until)
+	}
+
+	// The count starts over.
+	clock.Advance(time.Minute)
+	for range loginPolicy.FreeFailures {
+		if err := l.Fail(ctx, "trader"); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+	}
+	if until := lockedUntil(t, l, "trader"); !until.IsZero() {
+		t.Errorf("free failures after reset locked until %v", until)
+	}
+}
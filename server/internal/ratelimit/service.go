@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLockoutLimit = 100
+	maxLockoutLimit     = 500
+
+	pruneInterval = 10 * time.Minute
+)
+
+// Lockout is a record of a key reaching its policy's LockoutAfter.
+type Lockout struct {
+	ID          int64
+	Scope       string
+	Key         string
+	Failures    int
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	ReleasedBy  *uuid.UUID
+	ReleasedAt  *time.Time
+}
+
+type LockoutFilter struct {
+	Scope string
+	// ActiveOnly leaves out lockouts that ended or were released.
+	ActiveOnly bool
+	Limit      int
+}
+
+// LockoutLog keeps lockouts for admin review, newest first.
+type LockoutLog interface {
+	Record(ctx context.Context, lockout Lockout) error
+	List(ctx context.Context, filter LockoutFilter) ([]Lockout, error)
+	// Release marks a lockout released; it fails with ErrLockoutNotFound if
+	// there is no such lockout or it was released already.
+	Release(ctx context.Context, id int64, releasedBy uuid.UUID) (Lockout, error)
+}
+
+// Service hands out limiters that share a store and a lockout log, and
+// lets admins review and lift lockouts.
+type Service struct {
+	store    Store
+	lockouts LockoutLog
+
+	mu     sync.Mutex
+	window time.Duration
+}
+
+func NewService(store Store, lockouts LockoutLog) *Service {
+	return &Service{store: store, lockouts: lockouts}
+}
+
+// Limiter returns a limiter for scope. Scopes keep the counts of different
+// endpoints apart, so each endpoint should have its own.
+func (s *Service) Limiter(scope string, policy Policy) *Limiter {
+	s.mu.Lock()
+	s.window = max(s.window, policy.Window, policy.MaxLockout)
+	s.mu.Unlock()
+
+	return &Limiter{scope: scope, policy: policy, store: s.store, lockouts: s.lockouts, now: time.Now}
+}
+
+func (s *Service) ListLockouts(ctx context.Context, filter LockoutFilter) ([]Lockout, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultLockoutLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxLockoutLimit {
+		return nil, ErrInvalidLimit
+	}
+	return s.lockouts.List(ctx, filter)
+}
+
+// ReleaseLockout lifts a lockout early and forgets the key's failures, so
+// the next attempt starts from a clean slate.
+func (s *Service) ReleaseLockout(ctx context.Context, id int64, releasedBy uuid.UUID) (Lockout, error) {
+	lockout, err := s.lockouts.Release(ctx, id, releasedBy)
+	if err != nil {
+		return Lockout{}, err
+	}
+	if err := s.store.Reset(ctx, scopedKey(lockout.Scope, lockout.Key)); err != nil {
+		return Lockout{}, err
+	}
+	return lockout, nil
+}
+
+// Run periodically forgets keys that no limiter would remember any more.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			window := s.window
+			s.mu.Unlock()
+
+			if _, err := s.store.Prune(ctx, time.Now().Add(-window)); err != nil {
+				log.Printf("RATELIMIT: prune: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+func parseCSV(text string, loc *time.Location) ([]model.Trade, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = detectDelimiter(text)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	// Trimming would also eat the tabs between empty fields, such as an
+	// unset S / L, and shift the columns that follow.
+	r.TrimLeadingSpace = r.Comma != '\t'
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoTradeTable
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	l, ok := layoutFromHeader(header)
+	if !ok {
+		return nil, ErrNoTradeTable
+	}
+
+	var trades []model.Trade
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		t, ok, err := l.trade(record, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			trades = append(trades, t)
+		}
+	}
+	return trades, nil
+}
+
+// detectDelimiter picks the most frequent of tab, semicolon and comma in the
+// header line. MT5 exports use tabs; spreadsheets often re-save with commas
+// or semicolons.
+func detectDelimiter(text string) rune {
+	header, _, _ := strings.Cut(text, "\n")
+
+	best, count := ',', 0
+	for _, d := range []rune{'\t', ';', ','} {
+		if n := strings.Count(header, string(d)); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,115 @@
+package statement
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+var (
+	rowPattern     = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+	cellPattern    = regexp.MustCompile(`(?is)<t[dh]([^>]*)>(.*?)</t[dh]>`)
+	colspanPattern = regexp.MustCompile(`(?i)colspan\s*=\s*["']?(\d+)`)
+	hiddenPattern  = regexp.MustCompile(`(?i)class\s*=\s*["']?hidden`)
+	tagPattern     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// sectionTitles end the closed trades table; the sections that follow list
+// open positions and orders, whose rows would otherwise look like trades.
+var sectionTitles = map[string]bool{
+	"opentrades":    true,
+	"workingorders": true,
+	"summary":       true,
+	"details":       true,
+	"orders":        true,
+	"deals":         true,
+	"openpositions": true,
+	"results":       true,
+}
+
+func parseHTML(text string, loc *time.Location) ([]model.Trade, error) {
+	var (
+		trades  []model.Trade
+		current *layout
+		found   bool
+	)
+
+	for n, row := range rowPattern.FindAllStringSubmatch(text, -1) {
+		cells := htmlCells(row[1])
+
+		if title, ok := sectionTitle(cells); ok && sectionTitles[title] {
+			current = nil
+			continue
+		}
+		if isHeader(cells) {
+			current = nil
+			if l, ok := layoutFromHeader(cells); ok {
+				current = &l
+				found = true
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		t, ok, err := current.trade(cells, loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n+1, err)
+		}
+		if ok {
+			trades = append(trades, t)
+		}
+	}
+
+	if !found {
+		return nil, ErrNoTradeTable
+	}
+	return trades, nil
+}
+
+// htmlCells returns the text of a row's cells. Cells spanning several
+// columns are padded so indexes line up with the header, and the hidden
+// cells MT5 uses for layout are dropped.
+func htmlCells(row string) []string {
+	var cells []string
+	for _, m := range cellPattern.FindAllStringSubmatch(row, -1) {
+		attrs, content := m[1], m[2]
+		if hiddenPattern.MatchString(attrs) {
+			continue
+		}
+
+		text := html.UnescapeString(tagPattern.ReplaceAllString(content, " "))
+		cells = append(cells, strings.Join(strings.Fields(text), " "))
+
+		if span := colspanPattern.FindStringSubmatch(attrs); span != nil {
+			if n, err := strconv.Atoi(span[1]); err == nil {
+				for i := 1; i < n; i++ {
+					cells = append(cells, "")
+				}
+			}
+		}
+	}
+	return cells
+}
+
+// sectionTitle returns the normalized text of a row holding a single
+// non-empty cell.
+func sectionTitle(cells []string) (string, bool) {
+	title := ""
+	for _, c := range cells {
+		if c == "" {
+			continue
+		}
+		if title != "" {
+			return "", false
+		}
+		title = c
+	}
+	return normalizeHeader(title), title != ""
+}
@@ -0,0 +1,269 @@
+// Package statement parses MetaTrader account statements into closed trades.
+// It understands MT4 detailed statements and MT5 trade reports saved as
+// HTML, and history exports saved as CSV.
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatCSV  Format = "csv"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown statement format")
+	ErrNoTradeTable  = errors.New("no closed trades table found")
+)
+
+// Parse decodes a statement in the given format. An empty format is detected
+// from the content. Times in the statement are read in loc, which should be
+// the broker's server time zone; nil means UTC.
+func Parse(data []byte, format Format, loc *time.Location) ([]model.Trade, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	text := decode(data)
+
+	if format == "" {
+		format = Detect(text)
+	}
+	switch format {
+	case FormatHTML:
+		return parseHTML(text, loc)
+	case FormatCSV:
+		return parseCSV(text, loc)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Detect tells HTML reports from CSV exports.
+func Detect(text string) Format {
+	head := strings.ToLower(text[:min(len(text), 4096)])
+	if strings.Contains(head, "<html") || strings.Contains(head, "<table") {
+		return FormatHTML
+	}
+	return FormatCSV
+}
+
+// decode turns the raw file into a string. MT5 writes its reports as
+// UTF-16 with a byte order mark.
+func decode(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], false)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], true)
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:])
+	default:
+		return string(data)
+	}
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// layout maps trade fields to column indexes. Commission, taxes and swap are
+// optional and -1 when missing.
+type layout struct {
+	position, symbol, side, volume             int
+	openTime, openPrice, closeTime, closePrice int
+	commission, taxes, swap, profit            int
+}
+
+// layoutFromHeader recognises the closed trades header of the supported
+// statements. Reports name both the open and close columns "Time" and
+// "Price", so the first occurrence is taken as open and the second as close.
+func layoutFromHeader(cells []string) (layout, bool) {
+	l := layout{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
+	for i, c := range cells {
+		switch normalizeHeader(c) {
+		case "ticket", "position", "positionid":
+			l.position = i
+		case "item", "symbol":
+			l.symbol = i
+		case "type":
+			l.side = i
+		case "size", "volume", "lots":
+			l.volume = i
+		case "opentime":
+			l.openTime = i
+		case "closetime":
+			l.closeTime = i
+		case "time":
+			if l.openTime < 0 {
+				l.openTime = i
+			} else {
+				l.closeTime = i
+			}
+		case "openprice":
+			l.openPrice = i
+		case "closeprice":
+			l.closePrice = i
+		case "price":
+			if l.openPrice < 0 {
+				l.openPrice = i
+			} else {
+				l.closePrice = i
+			}
+		case "commission":
+			l.commission = i
+		case "taxes":
+			l.taxes = i
+		case "swap":
+			l.swap = i
+		case "profit":
+			l.profit = i
+		}
+	}
+
+	required := []int{l.position, l.symbol, l.side, l.volume, l.openTime, l.openPrice, l.closeTime, l.closePrice, l.profit}
+	for _, idx := range required {
+		if idx < 0 {
+			return layout{}, false
+		}
+	}
+	return l, true
+}
+
+func normalizeHeader(s string) string {
+	s = strings.ToLower(s)
+	s = strings.NewReplacer(" ", "", "_", "", "/", "", ":", "").Replace(s)
+	return s
+}
+
+// isHeader reports whether cells look like a table header rather than data.
+func isHeader(cells []string) bool {
+	var hasType, hasSymbol bool
+	for _, c := range cells {
+		switch normalizeHeader(c) {
+		case "type":
+			hasType = true
+		case "symbol", "item":
+			hasSymbol = true
+		}
+	}
+	return hasType && hasSymbol
+}
+
+// trade converts a row. ok is false for rows that are not closed buy or sell
+// positions, such as balance operations and cancelled orders.
+func (l layout) trade(cells []string, loc *time.Location) (model.Trade, bool, error) {
+	last := max(l.position, l.symbol, l.side, l.volume, l.openTime, l.openPrice,
+		l.closeTime, l.closePrice, l.commission, l.taxes, l.swap, l.profit)
+	if len(cells) <= last {
+		return model.Trade{}, false, nil
+	}
+
+	side := strings.ToLower(strings.TrimSpace(cells[l.side]))
+	if side != "buy" && side != "sell" {
+		return model.Trade{}, false, nil
+	}
+
+	var (
+		t   = model.Trade{Symbol: strings.TrimSpace(cells[l.symbol]), Side: side}
+		err error
+	)
+	if t.PositionID, err = strconv.ParseInt(strings.TrimSpace(cells[l.position]), 10, 64); err != nil {
+		return model.Trade{}, false, fmt.Errorf("invalid position %q", cells[l.position])
+	}
+	if t.Volume, err = parseVolume(cells[l.volume]); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.OpenTime, err = parseTime(cells[l.openTime], loc); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.CloseTime, err = parseTime(cells[l.closeTime], loc); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.OpenPrice, err = parseNumber(cells[l.openPrice]); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.ClosePrice, err = parseNumber(cells[l.closePrice]); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.Profit, err = parseNumber(cells[l.profit]); err != nil {
+		return model.Trade{}, false, err
+	}
+	if t.Commission, err = optionalNumber(cells, l.commission); err != nil {
+		return model.Trade{}, false, err
+	}
+	taxes, err := optionalNumber(cells, l.taxes)
+	if err != nil {
+		return model.Trade{}, false, err
+	}
+	t.Commission += taxes
+	if t.Swap, err = optionalNumber(cells, l.swap); err != nil {
+		return model.Trade{}, false, err
+	}
+
+	return t, true, nil
+}
+
+var timeLayouts = []string{
+	"2006.01.02 15:04:05",
+	"2006.01.02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+}
+
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// parseNumber accepts the thousands separators MetaTrader uses.
+func parseNumber(s string) (float64, error) {
+	clean := strings.NewReplacer(" ", "", "\u00a0", "").Replace(strings.TrimSpace(s))
+	if clean == "" || clean == "-" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(clean, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+func optionalNumber(cells []string, idx int) (float64, error) {
+	if idx < 0 {
+		return 0, nil
+	}
+	return parseNumber(cells[idx])
+}
+
+// parseVolume reads MT5's "closed / opened" volume pairs as the first value.
+func parseVolume(s string) (float64, error) {
+	if i := strings.Index(s, "/"); i >= 0 {
+		s = s[:i]
+	}
+	return parseNumber(s)
+}
@@ -0,0 +1,192 @@
+package statement
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/filipcvejic/trading_tournament/internal/competition/model"
+)
+
+// serverTime is the broker's server time zone in the fixtures.
+var serverTime = time.FixedZone("EET", 2*60*60)
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.DateTime, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// mt5Trades are the closed positions in both MT5 fixtures.
+var mt5Trades = []model.Trade{
+	{
+		PositionID: 5001, Symbol: "EURUSD", Side: "buy", Volume: 0.5,
+		OpenTime: utc("2024-03-04 07:15:00"), CloseTime: utc("2024-03-04 14:45:12"),
+		OpenPrice: 1.08410, ClosePrice: 1.08790, Commission: -2.5, Profit: 190,
+	},
+	{
+		PositionID: 5002, Symbol: "US500", Side: "sell", Volume: 2,
+		OpenTime: utc("2024-03-05 20:40:00"), CloseTime: utc("2024-03-06 12:00:00"),
+		OpenPrice: 5120.5, ClosePrice: 5100, Swap: -1.2, Profit: 41,
+	},
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+// utf16LE encodes data the way MT5 saves its reports.
+func utf16LE(data []byte) []byte {
+	out := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(string(data))) {
+		out = append(out, byte(u), byte(u>>8))
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		encode  func([]byte) []byte
+		format  Format
+		want    []model.Trade
+	}{
+		{
+			// Besides the closed trades, the statement has a deposit, a
+			// cancelled limit order, a subtotal row and an open trade.
+			name:    "MT4 detailed statement",
+			fixture: "mt4.html",
+			want: []model.Trade{
+				{
+					PositionID: 40001, Symbol: "eurusd", Side: "buy", Volume: 1,
+					OpenTime: utc("2024-03-04 08:00:00"), CloseTime: utc("2024-03-04 10:30:00"),
+					OpenPrice: 1.08500, ClosePrice: 1.08650, Commission: -7, Profit: 150,
+				},
+				{
+					PositionID: 40002, Symbol: "gbpusd", Side: "sell", Volume: 0.5,
+					OpenTime: utc("2024-03-05 21:50:00"), CloseTime: utc("2024-03-05 23:10:00"),
+					OpenPrice: 1.27000, ClosePrice: 1.27200, Commission: -3.5, Swap: -1.25, Profit: -100,
+				},
+				{
+					// Taxes count as commission.
+					PositionID: 40003, Symbol: "xauusd", Side: "buy", Volume: 2,
+					OpenTime: utc("2024-03-06 07:00:00"), CloseTime: utc("2024-03-07 13:00:00"),
+					OpenPrice: 2150.5, ClosePrice: 2165.75, Commission: -14.5, Swap: -12.4, Profit: 3050,
+				},
+			},
+		},
+		{
+			// The orders and the deals, the deposit among them, are not
+			// positions.
+			name:    "MT5 trade report",
+			fixture: "mt5.html",
+			want:    mt5Trades,
+		},
+		{
+			name:    "MT5 trade report in UTF-16",
+			fixture: "mt5.html",
+			encode:  utf16LE,
+			want:    mt5Trades,
+		},
+		{
+			name:    "MT5 history export",
+			fixture: "mt5.csv",
+			want:    mt5Trades,
+		},
+		{
+			name:    "MT5 history export, format given",
+			fixture: "mt5.csv",
+			format:  FormatCSV,
+			want:    mt5Trades,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := readFixture(t, tt.fixture)
+			if tt.encode != nil {
+				data = tt.encode(data)
+			}
+
+			got, err := Parse(data, tt.format, serverTime)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d trades, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !sameTrade(got[i], tt.want[i]) {
+					t.Errorf("trade %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func sameTrade(a, b model.Trade) bool {
+	return a.PositionID == b.PositionID &&
+		a.Symbol == b.Symbol &&
+		a.Side == b.Side &&
+		a.Volume == b.Volume &&
+		a.OpenTime.Equal(b.OpenTime) &&
+		a.CloseTime.Equal(b.CloseTime) &&
+		a.OpenPrice == b.OpenPrice &&
+		a.ClosePrice == b.ClosePrice &&
+		a.Commission == b.Commission &&
+		a.Swap == b.Swap &&
+		a.Profit == b.Profit
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		format  Format
+		wantErr error
+		// wantMsg is checked instead of wantErr when set.
+		wantMsg string
+	}{
+		{name: "CSV without a trades header", data: readFixture(t, "unknown.csv"), wantErr: ErrNoTradeTable},
+		{name: "HTML without a trades header", data: readFixture(t, "unknown.html"), wantErr: ErrNoTradeTable},
+		{name: "empty file", data: nil, wantErr: ErrNoTradeTable},
+		{name: "HTML read as CSV", data: readFixture(t, "mt4.html"), format: FormatCSV, wantErr: ErrNoTradeTable},
+		{name: "unknown format", data: readFixture(t, "mt5.csv"), format: "pdf", wantErr: ErrUnknownFormat},
+		{
+			name:    "bad time",
+			data:    []byte("Position,Symbol,Type,Volume,Open Time,Open Price,Close Time,Close Price,Profit\n1,EURUSD,buy,1,yesterday,1.1,2024.03.04 10:00,1.2,100\n"),
+			wantMsg: `line 2: invalid time "yesterday"`,
+		},
+		{
+			name:    "bad number",
+			data:    []byte("Position,Symbol,Type,Volume,Open Time,Open Price,Close Time,Close Price,Profit\n1,EURUSD,buy,1,2024.03.04 09:00,1.1,2024.03.04 10:00,1.2,1O0\n"),
+			wantMsg: `line 2: invalid number "1O0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data, tt.format, serverTime)
+			switch {
+			case tt.wantMsg != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Errorf("err = %v, want %q", err, tt.wantMsg)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -11,20 +11,24 @@ const (
 )
 
 type IngestTrackedTradeEventRequest struct {
-	EventType  EventType  `json:"eventType" validate:"required,oneof=OPEN UPDATE CLOSE"`
-	PositionID int64      `json:"positionId" validate:"required"`
-	Symbol     string     `json:"symbol,omitempty"`
-	Side       string     `json:"side,omitempty"`
-	OpenPrice  float64    `json:"openPrice,omitempty"`
-	StopLoss   *float64   `json:"stopLoss,omitempty"`
-	Volume     float64    `json:"volume,omitempty"`
-	OpenedAt   *time.Time `json:"openedAt,omitempty"`
-	ClosedAt   *time.Time `json:"closedAt,omitempty"`
+	EventType  EventType `json:"eventType" validate:"required,oneof=OPEN UPDATE CLOSE"`
+	PositionID int64     `json:"positionId" validate:"required"`
+	Symbol     string    `json:"symbol,omitempty"`
+	// Broker names the broker quoting Symbol, so its aliases can be used to
+	// resolve the instrument.
+	Broker    string     `json:"broker,omitempty"`
+	Side      string     `json:"side,omitempty"`
+	OpenPrice float64    `json:"openPrice,omitempty"`
+	StopLoss  *float64   `json:"stopLoss,omitempty"`
+	Volume    float64    `json:"volume,omitempty"`
+	OpenedAt  *time.Time `json:"openedAt,omitempty"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"`
 }
 
 type TrackedTradeResponse struct {
 	PositionID int64      `json:"positionId"`
 	Symbol     string     `json:"symbol"`
+	Instrument string     `json:"instrument,omitempty"`
 	Side       string     `json:"side"`
 	OpenPrice  float64    `json:"openPrice"`
 	Volume     float64    `json:"volume"`
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"github.com/filipcvejic/trading_tournament/internal/apikey"
 	"github.com/filipcvejic/trading_tournament/internal/auth"
 	"github.com/go-chi/chi/v5"
 	nethttp "net/http"
@@ -13,11 +14,13 @@ import (
 
 type Handler struct {
 	service *trackedtrade.Service
+	apiKeys *apikey.Service
 }
 
-func NewHandler(service *trackedtrade.Service) *Handler {
+func NewHandler(service *trackedtrade.Service, apiKeys *apikey.Service) *Handler {
 	return &Handler{
 		service: service,
+		apiKeys: apiKeys,
 	}
 }
 
@@ -29,7 +32,8 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Get("/tracked-trades", h.List)
 	})
 
-	r.Post("/tracked-trades/events", h.IngestEvent)
+	r.With(apikey.RequireAPIKey(h.apiKeys, apikey.ScopeTrackedTradesWrite)).
+		Post("/tracked-trades/events", h.IngestEvent)
 }
 
 func (h *Handler) IngestEvent(w nethttp.ResponseWriter, r *nethttp.Request) {
@@ -66,6 +70,7 @@ func (h *Handler) List(w nethttp.ResponseWriter, r *nethttp.Request) {
 		response = append(response, trackedtrade.TrackedTradeResponse{
 			PositionID: t.PositionID,
 			Symbol:     t.Symbol,
+			Instrument: t.Instrument,
 			Side:       string(t.Side),
 			OpenPrice:  t.OpenPrice,
 			Volume:     t.Volume,
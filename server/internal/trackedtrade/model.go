@@ -12,6 +12,9 @@ const (
 type TrackedTrade struct {
 	PositionID int64
 	Symbol     string
+	// Instrument is the canonical symbol, empty when Symbol is not in the
+	// instrument catalogue.
+	Instrument string
 	Side       Side
 	OpenPrice  float64
 	Volume     float64
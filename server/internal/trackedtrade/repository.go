@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/filipcvejic/trading_tournament/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Repository interface {
@@ -32,6 +33,7 @@ func (r *PostgresRepository) Create(ctx context.Context, trade TrackedTrade) err
 		StopLoss:   trade.StopLoss,
 		Volume:     trade.Volume,
 		OpenedAt:   trade.OpenedAt,
+		Instrument: pgtype.Text{String: trade.Instrument, Valid: trade.Instrument != ""},
 	})
 }
 
@@ -62,6 +64,7 @@ func (r *PostgresRepository) List(ctx context.Context) ([]TrackedTrade, error) {
 		trades = append(trades, TrackedTrade{
 			PositionID: row.PositionID,
 			Symbol:     row.Symbol,
+			Instrument: row.Instrument.String,
 			Side:       Side(row.Side),
 			OpenPrice:  row.OpenPrice,
 			StopLoss:   row.StopLoss,
@@ -3,15 +3,19 @@ package trackedtrade
 import (
 	"context"
 	"strings"
+
+	"github.com/filipcvejic/trading_tournament/internal/instrument"
 )
 
 type Service struct {
-	repository Repository
+	repository  Repository
+	instruments *instrument.Service
 }
 
-func NewService(repository Repository) *Service {
+func NewService(repository Repository, instruments *instrument.Service) *Service {
 	return &Service{
-		repository: repository,
+		repository:  repository,
+		instruments: instruments,
 	}
 }
 
@@ -27,9 +31,15 @@ func (s *Service) IngestEvent(ctx context.Context, req IngestTrackedTradeEventRe
 			return ErrMissingOpenFields
 		}
 
+		inst, _, err := s.instruments.Resolve(ctx, req.Broker, req.Symbol)
+		if err != nil {
+			return err
+		}
+
 		trade := TrackedTrade{
 			PositionID: req.PositionID,
 			Symbol:     req.Symbol,
+			Instrument: inst.Symbol,
 			Side:       side,
 			OpenPrice:  req.OpenPrice,
 			Volume:     req.Volume,
@@ -20,20 +20,23 @@ type TradingAccountResponse struct {
 }
 
 type TradeHistoryResponse struct {
-	Username string     `json:"username"`
-	Trades   []TradeDTO `json:"trades"`
+	Username   string     `json:"username"`
+	Trades     []TradeDTO `json:"trades"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 }
 
 type TradeDTO struct {
-	PositionID int64     `json:"positionId"`
-	Symbol     string    `json:"symbol"`
-	Side       string    `json:"side"`
-	Volume     float64   `json:"volume"`
-	OpenTime   time.Time `json:"openTime"`
-	CloseTime  time.Time `json:"closeTime"`
-	OpenPrice  float64   `json:"openPrice"`
-	ClosePrice float64   `json:"closePrice"`
-	Profit     float64   `json:"profit"`
-	Commission float64   `json:"commission"`
-	Swap       float64   `json:"swap"`
+	CompetitionID uuid.UUID `json:"competitionId"`
+	PositionID    int64     `json:"positionId"`
+	Symbol        string    `json:"symbol"`
+	Instrument    string    `json:"instrument,omitempty"`
+	Side          string    `json:"side"`
+	Volume        float64   `json:"volume"`
+	OpenTime      time.Time `json:"openTime"`
+	CloseTime     time.Time `json:"closeTime"`
+	OpenPrice     float64   `json:"openPrice"`
+	ClosePrice    float64   `json:"closePrice"`
+	Profit        float64   `json:"profit"`
+	Commission    float64   `json:"commission"`
+	Swap          float64   `json:"swap"`
 }
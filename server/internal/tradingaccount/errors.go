@@ -8,4 +8,9 @@ var (
 	ErrInvalidBroker           = errors.New("invalid broker")
 	ErrInvalidInvestorPassword = errors.New("invalid investor password")
 	ErrLoginTaken              = errors.New("trading account login already taken")
+	ErrInvalidSide             = errors.New("invalid side")
+	ErrInvalidOutcome          = errors.New("invalid outcome")
+	ErrInvalidDateRange        = errors.New("invalid date range")
+	ErrInvalidLimit            = errors.New("invalid limit")
+	ErrInvalidCursor           = errors.New("invalid cursor")
 )
@@ -0,0 +1,67 @@
+package tradingaccount
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 500
+)
+
+func validateHistoryFilter(login int64, f *TradeHistoryFilter) error {
+	if login <= 0 {
+		return ErrInvalidLogin
+	}
+	switch f.Side {
+	case "", "buy", "sell":
+	default:
+		return ErrInvalidSide
+	}
+	switch f.Outcome {
+	case "", OutcomeWin, OutcomeLoss:
+	default:
+		return ErrInvalidOutcome
+	}
+	if f.ClosedFrom != nil && f.ClosedTo != nil && !f.ClosedTo.After(*f.ClosedFrom) {
+		return ErrInvalidDateRange
+	}
+	if f.Limit < 0 || f.Limit > maxHistoryLimit {
+		return ErrInvalidLimit
+	}
+	if f.Limit == 0 {
+		f.Limit = defaultHistoryLimit
+	}
+	return nil
+}
+
+// EncodeCursor turns a cursor into an opaque, URL-safe token.
+func EncodeCursor(c TradeCursor) string {
+	raw := strconv.FormatInt(c.CloseTime.UnixMicro(), 10) + ":" + strconv.FormatInt(c.PositionID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(token string) (TradeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return TradeCursor{}, ErrInvalidCursor
+	}
+
+	closeTime, positionID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return TradeCursor{}, ErrInvalidCursor
+	}
+	micros, err := strconv.ParseInt(closeTime, 10, 64)
+	if err != nil {
+		return TradeCursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(positionID, 10, 64)
+	if err != nil || id <= 0 {
+		return TradeCursor{}, ErrInvalidCursor
+	}
+
+	return TradeCursor{CloseTime: time.UnixMicro(micros).UTC(), PositionID: id}, nil
+}
@@ -39,6 +39,26 @@ var errorMap = map[error]errorMapping{
 		http.StatusBadRequest,
 		"Investor password must be at least 5 characters",
 	},
+	tradingaccount.ErrInvalidSide: {
+		http.StatusBadRequest,
+		"Side must be 'buy' or 'sell'",
+	},
+	tradingaccount.ErrInvalidOutcome: {
+		http.StatusBadRequest,
+		"Outcome must be 'win' or 'loss'",
+	},
+	tradingaccount.ErrInvalidDateRange: {
+		http.StatusBadRequest,
+		"'to' must be after 'from'",
+	},
+	tradingaccount.ErrInvalidLimit: {
+		http.StatusBadRequest,
+		"Limit must be between 1 and 500",
+	},
+	tradingaccount.ErrInvalidCursor: {
+		http.StatusBadRequest,
+		"Invalid cursor",
+	},
 
 	// Auth errors
 	auth.ErrUnauthorized: {http.StatusUnauthorized, "Unauthorized"},
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/filipcvejic/trading_tournament/internal/tradingaccount"
+)
+
+// exportFormat writes trades as a downloadable file instead of a JSON page.
+type exportFormat struct {
+	contentType string
+	extension   string
+	newWriter   func(w http.ResponseWriter) tradeWriter
+}
+
+type tradeWriter interface {
+	Write(t tradingaccount.TradeDTO) error
+	Flush() error
+}
+
+var jsonLinesExport = &exportFormat{
+	contentType: "application/x-ndjson",
+	extension:   "ndjson",
+	newWriter:   newJSONLinesTradeWriter,
+}
+
+var exportFormats = map[string]*exportFormat{
+	"text/csv": {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		newWriter:   newCSVTradeWriter,
+	},
+	"application/x-ndjson": jsonLinesExport,
+	"application/jsonl":    jsonLinesExport,
+}
+
+// negotiateExport picks the export format from the Accept header, or nil
+// when the client wants the regular paginated JSON response.
+func negotiateExport(r *http.Request) *exportFormat {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" {
+			return nil
+		}
+		if f, ok := exportFormats[mediaType]; ok {
+			return f
+		}
+	}
+	return nil
+}
+
+func (h *Handler) exportTradeHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+	login int64,
+	filter tradingaccount.TradeHistoryFilter,
+	format *exportFormat,
+) {
+	var tw tradeWriter
+	start := func() {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trades-%d.%s"`, login, format.extension))
+		w.WriteHeader(http.StatusOK)
+		tw = format.newWriter(w)
+	}
+
+	err := h.service.ExportTradeHistory(r.Context(), login, filter, func(t tradingaccount.TradeDTO) error {
+		if tw == nil {
+			start()
+		}
+		return tw.Write(t)
+	})
+	if err != nil && tw == nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	if err != nil {
+		// Headers are already sent; the client sees a truncated file.
+		log.Printf("ERROR: %s %s - export aborted: %v", r.Method, r.URL.Path, err)
+		return
+	}
+
+	if tw == nil {
+		start()
+	}
+	if err := tw.Flush(); err != nil {
+		log.Printf("ERROR: %s %s - export flush: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+var csvHeader = []string{
+	"competition_id", "position_id", "symbol", "instrument", "side", "volume",
+	"open_time", "close_time", "open_price", "close_price", "profit", "commission", "swap",
+}
+
+type csvTradeWriter struct {
+	w *csv.Writer
+}
+
+func newCSVTradeWriter(w http.ResponseWriter) tradeWriter {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(csvHeader)
+	return &csvTradeWriter{w: cw}
+}
+
+func (c *csvTradeWriter) Write(t tradingaccount.TradeDTO) error {
+	return c.w.Write([]string{
+		t.CompetitionID.String(),
+		strconv.FormatInt(t.PositionID, 10),
+		t.Symbol,
+		t.Instrument,
+		t.Side,
+		formatFloat(t.Volume),
+		t.OpenTime.UTC().Format(time.RFC3339),
+		t.CloseTime.UTC().Format(time.RFC3339),
+		formatFloat(t.OpenPrice),
+		formatFloat(t.ClosePrice),
+		formatFloat(t.Profit),
+		formatFloat(t.Commission),
+		formatFloat(t.Swap),
+	})
+}
+
+func (c *csvTradeWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+type jsonLinesTradeWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLinesTradeWriter(w http.ResponseWriter) tradeWriter {
+	return &jsonLinesTradeWriter{enc: json.NewEncoder(w)}
+}
+
+// Write encodes one trade per line; json.Encoder appends the newline.
+func (j *jsonLinesTradeWriter) Write(t tradingaccount.TradeDTO) error {
+	return j.enc.Encode(t)
+}
+
+func (j *jsonLinesTradeWriter) Flush() error {
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"github.com/filipcvejic/trading_tournament/internal/tradingaccount"
 	"github.com/filipcvejic/trading_tournament/internal/validation"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type Handler struct {
@@ -88,7 +91,17 @@ func (h *Handler) getTradeHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.service.GetTradeHistory(r.Context(), login)
+	filter, ok := parseHistoryFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if format := negotiateExport(r); format != nil {
+		h.exportTradeHistory(w, r, login, filter, format)
+		return
+	}
+
+	resp, err := h.service.GetTradeHistory(r.Context(), login, filter)
 	if err != nil {
 		writeDomainError(w, r, err)
 		return
@@ -96,3 +109,59 @@ func (h *Handler) getTradeHistory(w http.ResponseWriter, r *http.Request) {
 
 	httputil.WriteJSON(w, http.StatusOK, resp)
 }
+
+func parseHistoryFilter(w http.ResponseWriter, r *http.Request) (tradingaccount.TradeHistoryFilter, bool) {
+	query := r.URL.Query()
+	filter := tradingaccount.TradeHistoryFilter{
+		Symbol:  query.Get("symbol"),
+		Side:    query.Get("side"),
+		Outcome: tradingaccount.TradeOutcome(query.Get("outcome")),
+	}
+
+	if v := query.Get("competitionId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			httputil.WriteClientError(w, r, "Invalid competition ID format", err)
+			return filter, false
+		}
+		filter.CompetitionID = &id
+	}
+
+	if v := query.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			httputil.WriteClientError(w, r, "Invalid from parameter, expected RFC 3339", err)
+			return filter, false
+		}
+		filter.ClosedFrom = &t
+	}
+
+	if v := query.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			httputil.WriteClientError(w, r, "Invalid to parameter, expected RFC 3339", err)
+			return filter, false
+		}
+		filter.ClosedTo = &t
+	}
+
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			httputil.WriteClientError(w, r, "Invalid limit parameter", err)
+			return filter, false
+		}
+		filter.Limit = int32(min(n, math.MaxInt32))
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		c, err := tradingaccount.DecodeCursor(v)
+		if err != nil {
+			writeDomainError(w, r, err)
+			return filter, false
+		}
+		filter.After = &c
+	}
+
+	return filter, true
+}
@@ -11,3 +11,30 @@ type TradingAccount struct {
 	Broker    string
 	CreatedAt time.Time
 }
+
+type TradeOutcome string
+
+const (
+	OutcomeWin  TradeOutcome = "win"
+	OutcomeLoss TradeOutcome = "loss"
+)
+
+// TradeHistoryFilter narrows an account's trade history. Zero values match
+// everything; ClosedTo is exclusive.
+type TradeHistoryFilter struct {
+	CompetitionID *uuid.UUID
+	ClosedFrom    *time.Time
+	ClosedTo      *time.Time
+	Symbol        string
+	Side          string
+	Outcome       TradeOutcome
+	After         *TradeCursor
+	Limit         int32
+}
+
+// TradeCursor is the position of the last trade of a page. History is
+// ordered by close time, then position ID, both descending.
+type TradeCursor struct {
+	CloseTime  time.Time
+	PositionID int64
+}
@@ -7,12 +7,14 @@ import (
 	"github.com/filipcvejic/trading_tournament/db"
 	"github.com/filipcvejic/trading_tournament/db/sqlc"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Repository interface {
 	Create(ctx context.Context, login int64, userID uuid.UUID, broker, investorPasswordEncrypted string) (TradingAccount, error)
 	GetByLogin(ctx context.Context, login int64) (TradingAccount, error)
-	GetTradeHistory(ctx context.Context, login int64) (username string, trades []TradeDTO, err error)
+	GetUsername(ctx context.Context, login int64) (string, error)
+	ListTrades(ctx context.Context, login int64, filter TradeHistoryFilter) ([]TradeDTO, error)
 }
 
 type PostgresRepository struct {
@@ -65,39 +67,56 @@ func (r *PostgresRepository) GetByLogin(ctx context.Context, login int64) (Tradi
 	}, nil
 }
 
-func (r *PostgresRepository) GetTradeHistory(
-	ctx context.Context,
-	login int64,
-) (string, []TradeDTO, error) {
+func (r *PostgresRepository) GetUsername(ctx context.Context, login int64) (string, error) {
 	username, err := r.db.Query.GetUsernameByTradingAccountLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", nil, ErrNotFound
+			return "", ErrNotFound
 		}
-		return "", nil, err
+		return "", err
+	}
+	return username, nil
+}
+
+func (r *PostgresRepository) ListTrades(ctx context.Context, login int64, filter TradeHistoryFilter) ([]TradeDTO, error) {
+	params := sqlc.ListTradeHistoryParams{
+		TradingAccountLogin: login,
+		CompetitionID:       filter.CompetitionID,
+		ClosedFrom:          filter.ClosedFrom,
+		ClosedTo:            filter.ClosedTo,
+		Symbol:              pgtype.Text{String: filter.Symbol, Valid: filter.Symbol != ""},
+		Side:                pgtype.Text{String: filter.Side, Valid: filter.Side != ""},
+		Outcome:             pgtype.Text{String: string(filter.Outcome), Valid: filter.Outcome != ""},
+		RowLimit:            filter.Limit,
+	}
+	if filter.After != nil {
+		params.CursorCloseTime = &filter.After.CloseTime
+		params.CursorPositionID = pgtype.Int8{Int64: filter.After.PositionID, Valid: true}
 	}
 
-	rows, err := r.db.Query.ListTradesByAccountLogin(ctx, login)
+	rows, err := r.db.Query.ListTradeHistory(ctx, params)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
 	trades := make([]TradeDTO, 0, len(rows))
 	for _, row := range rows {
 		trades = append(trades, TradeDTO{
-			PositionID: row.PositionID,
-			Symbol:     row.Symbol,
-			Side:       row.Side,
-			Volume:     row.Volume,
-			OpenTime:   row.OpenTime,
-			CloseTime:  row.CloseTime,
-			OpenPrice:  row.OpenPrice,
-			ClosePrice: row.ClosePrice,
-			Profit:     row.Profit,
-			Commission: row.Commission,
-			Swap:       row.Swap,
+			CompetitionID: row.CompetitionID,
+			PositionID:    row.PositionID,
+			Symbol:        row.Symbol,
+			Instrument:    row.Instrument.String,
+			Side:          row.Side,
+			Volume:        row.Volume,
+			OpenTime:      row.OpenTime,
+			CloseTime:     row.CloseTime,
+			OpenPrice:     row.OpenPrice,
+			ClosePrice:    row.ClosePrice,
+			Profit:        row.Profit,
+			Commission:    row.Commission,
+			Swap:          row.Swap,
 		})
 	}
 
-	return username, trades, nil
+	return trades, nil
 }
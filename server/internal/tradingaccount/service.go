@@ -62,18 +62,60 @@ func (s *Service) GetByLogin(ctx context.Context, login int64) (TradingAccount,
 	return s.repo.GetByLogin(ctx, login)
 }
 
-func (s *Service) GetTradeHistory(ctx context.Context, login int64) (TradeHistoryResponse, error) {
-	if login <= 0 {
-		return TradeHistoryResponse{}, ErrInvalidLogin
+// GetTradeHistory returns one page of an account's closed trades, newest
+// first. NextCursor is set when more trades match the filter.
+func (s *Service) GetTradeHistory(ctx context.Context, login int64, filter TradeHistoryFilter) (TradeHistoryResponse, error) {
+	if err := validateHistoryFilter(login, &filter); err != nil {
+		return TradeHistoryResponse{}, err
 	}
 
-	username, trades, err := s.repo.GetTradeHistory(ctx, login)
+	username, err := s.repo.GetUsername(ctx, login)
 	if err != nil {
 		return TradeHistoryResponse{}, err
 	}
 
-	return TradeHistoryResponse{
-		Username: username,
-		Trades:   trades,
-	}, nil
+	limit := filter.Limit
+	filter.Limit++
+	trades, err := s.repo.ListTrades(ctx, login, filter)
+	if err != nil {
+		return TradeHistoryResponse{}, err
+	}
+
+	resp := TradeHistoryResponse{Username: username, Trades: trades}
+	if int32(len(trades)) > limit {
+		resp.Trades = trades[:limit]
+		last := resp.Trades[limit-1]
+		resp.NextCursor = EncodeCursor(TradeCursor{CloseTime: last.CloseTime, PositionID: last.PositionID})
+	}
+	return resp, nil
+}
+
+// ExportTradeHistory passes every trade matching the filter to emit, newest
+// first, reading the history page by page. The filter's limit is ignored.
+func (s *Service) ExportTradeHistory(ctx context.Context, login int64, filter TradeHistoryFilter, emit func(TradeDTO) error) error {
+	filter.Limit = maxHistoryLimit
+	if err := validateHistoryFilter(login, &filter); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetUsername(ctx, login); err != nil {
+		return err
+	}
+
+	for {
+		trades, err := s.repo.ListTrades(ctx, login, filter)
+		if err != nil {
+			return err
+		}
+		for _, t := range trades {
+			if err := emit(t); err != nil {
+				return err
+			}
+		}
+		if int32(len(trades)) < filter.Limit {
+			return nil
+		}
+
+		last := trades[len(trades)-1]
+		filter.After = &TradeCursor{CloseTime: last.CloseTime, PositionID: last.PositionID}
+	}
 }
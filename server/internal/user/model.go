@@ -22,4 +22,9 @@ type User struct {
 	Role            Role
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	EmailVerifiedAt *time.Time
+}
+
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
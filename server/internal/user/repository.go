@@ -14,6 +14,8 @@ type Repository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
 	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, hash string) error
+	// MarkEmailVerified reports whether the email was unverified until now.
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
 }
 
 type PostgresRepository struct {
@@ -45,6 +47,7 @@ func (r *PostgresRepository) Create(
 		DiscordUsername: row.DiscordUsername,
 		CreatedAt:       row.CreatedAt,
 		UpdatedAt:       row.UpdatedAt,
+		EmailVerifiedAt: row.EmailVerifiedAt,
 	}, nil
 }
 
@@ -57,14 +60,7 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (User, e
 		return User{}, err
 	}
 
-	return User{
-		ID:              row.ID,
-		Email:           row.Email,
-		Username:        row.Username,
-		DiscordUsername: row.DiscordUsername,
-		CreatedAt:       row.CreatedAt,
-		UpdatedAt:       row.UpdatedAt,
-	}, nil
+	return userFromDB(row), nil
 }
 
 func (r *PostgresRepository) GetByEmail(ctx context.Context, email string) (User, error) {
@@ -76,6 +72,25 @@ func (r *PostgresRepository) GetByEmail(ctx context.Context, email string) (User
 		return User{}, err
 	}
 
+	return userFromDB(row), nil
+}
+
+func (r *PostgresRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, hash string) error {
+	return r.db.Query.UpdatePasswordHash(ctx, sqlc.UpdatePasswordHashParams{
+		ID:           userID,
+		PasswordHash: hash,
+	})
+}
+
+func (r *PostgresRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	n, err := r.db.Query.MarkEmailVerified(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func userFromDB(row sqlc.User) User {
 	return User{
 		ID:              row.ID,
 		Email:           row.Email,
@@ -85,12 +100,6 @@ func (r *PostgresRepository) GetByEmail(ctx context.Context, email string) (User
 		Role:            Role(row.Role),
 		CreatedAt:       row.CreatedAt,
 		UpdatedAt:       row.UpdatedAt,
-	}, nil
-}
-
-func (r *PostgresRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, hash string) error {
-	return r.db.Query.UpdatePasswordHash(ctx, sqlc.UpdatePasswordHashParams{
-		ID:           userID,
-		PasswordHash: hash,
-	})
+		EmailVerifiedAt: row.EmailVerifiedAt,
+	}
 }